@@ -109,6 +109,10 @@ type Client interface {
 	SplitRegions(ctx context.Context, splitKeys [][]byte, opts ...RegionsOption) (*pdpb.SplitRegionsResponse, error)
 	// GetOperator gets the status of operator of the specified region.
 	GetOperator(ctx context.Context, regionID uint64) (*pdpb.GetOperatorResponse, error)
+	// ScatterRegionsAndWait scatters the specified regions and waits until all the
+	// scatter operators are finished or the timeout is reached. It returns an error
+	// listing the regions whose scatter operators do not succeed.
+	ScatterRegionsAndWait(ctx context.Context, regionsID []uint64, timeout time.Duration) error
 	// Close closes the client.
 	Close()
 }
@@ -175,6 +179,7 @@ const (
 	maxInitClusterRetries = 100
 	retryInterval         = 1 * time.Second
 	maxRetryTimes         = 5
+	scatterWaitInterval   = 50 * time.Millisecond
 )
 
 // LeaderHealthCheckInterval might be chagned in the unit to shorten the testing time.
@@ -1311,6 +1316,90 @@ func (c *client) GetOperator(ctx context.Context, regionID uint64) (*pdpb.GetOpe
 	return c.getClient().GetOperator(ctx, req)
 }
 
+func (c *client) ScatterRegionsAndWait(ctx context.Context, regionsID []uint64, timeout time.Duration) error {
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		span = opentracing.StartSpan("pdclient.ScatterRegionsAndWait", opentracing.ChildOf(span.Context()))
+		defer span.Finish()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp, err := c.scatterRegionsWithOptions(ctx, regionsID)
+	if err != nil {
+		return err
+	}
+	if resp.GetFinishedPercentage() < 100 {
+		return errors.Errorf("scatter regions %v failed: only %d%% finished", regionsID, resp.GetFinishedPercentage())
+	}
+
+	pending := make(map[uint64]struct{}, len(regionsID))
+	for _, id := range regionsID {
+		pending[id] = struct{}{}
+	}
+	// seen records the regions whose scatter operator has been found.
+	seen := make(map[uint64]bool, len(regionsID))
+	// failed records why the scatter operator of the region did not succeed.
+	failed := make(map[uint64]string)
+	ticker := time.NewTicker(scatterWaitInterval)
+	defer ticker.Stop()
+	for {
+		for id := range pending {
+			op, err := c.GetOperator(ctx, id)
+			if err != nil {
+				if ctx.Err() != nil {
+					return errors.Errorf("wait for scattering regions timeout, %d regions are still scattering", len(pending))
+				}
+				return errors.WithStack(err)
+			}
+			if e := op.GetHeader().GetError(); e != nil {
+				delete(pending, id)
+				// The record of a finished operator is removed after a while, so
+				// a scatter operator which has been seen running is finished.
+				if e.GetType() != pdpb.ErrorType_REGION_NOT_FOUND || !seen[id] {
+					failed[id] = e.String()
+				}
+				continue
+			}
+			if desc := string(op.GetDesc()); desc != "scatter-region" {
+				delete(pending, id)
+				failed[id] = "replaced by operator " + desc
+				continue
+			}
+			seen[id] = true
+			switch op.GetStatus() {
+			case pdpb.OperatorStatus_RUNNING:
+			case pdpb.OperatorStatus_SUCCESS:
+				delete(pending, id)
+			default:
+				delete(pending, id)
+				failed[id] = op.GetStatus().String()
+			}
+		}
+		if len(pending) == 0 {
+			return scatterFailedError(regionsID, failed)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return errors.Errorf("wait for scattering regions timeout, %d regions are still scattering", len(pending))
+		}
+	}
+}
+
+// scatterFailedError returns an error listing the regions whose scatter
+// operators did not succeed, or nil if there is no such region.
+func scatterFailedError(regionsID []uint64, failed map[uint64]string) error {
+	if len(failed) == 0 {
+		return nil
+	}
+	reasons := make([]string, 0, len(failed))
+	for _, id := range regionsID {
+		if reason, ok := failed[id]; ok {
+			reasons = append(reasons, fmt.Sprintf("region %d: %s", id, reason))
+		}
+	}
+	return errors.Errorf("scatter regions failed: %s", strings.Join(reasons, ", "))
+}
+
 // SplitRegions split regions by given split keys
 func (c *client) SplitRegions(ctx context.Context, splitKeys [][]byte, opts ...RegionsOption) (*pdpb.SplitRegionsResponse, error) {
 	if span := opentracing.SpanFromContext(ctx); span != nil {
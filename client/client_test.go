@@ -68,6 +68,15 @@ func (s *testClientSuite) TestUpdateURLs(c *C) {
 	c.Assert(cli.urls, DeepEquals, getURLs([]*pdpb.Member{members[1], members[3], members[2], members[0]}))
 }
 
+func (s *testClientSuite) TestScatterFailedError(c *C) {
+	c.Assert(scatterFailedError([]uint64{1, 2}, nil), IsNil)
+	err := scatterFailedError([]uint64{3, 1, 2}, map[uint64]string{
+		1: pdpb.OperatorStatus_TIMEOUT.String(),
+		3: pdpb.OperatorStatus_CANCEL.String(),
+	})
+	c.Assert(err, ErrorMatches, "scatter regions failed: region 3: CANCEL, region 1: TIMEOUT")
+}
+
 const testClientURL = "tmp://test.url:5255"
 
 var _ = Suite(&testClientCtxSuite{})
@@ -121,6 +121,25 @@ func sortIDs(ids []uint64) []uint64 {
 	return ids
 }
 
+func (s *testRegionCacheSuite) TestShrinkTTLCache(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cache := NewIDTTL(ctx, time.Minute, time.Minute)
+	for i := uint64(1); i <= 5; i++ {
+		cache.PutWithTTL(i, nil, time.Duration(i)*time.Second)
+	}
+	// refresh the TTL of 1.
+	cache.PutWithTTL(1, nil, time.Minute)
+	c.Assert(cache.Shrink(10), Equals, 0)
+	c.Assert(cache.Shrink(3), Equals, 2)
+	c.Assert(cache.Len(), Equals, 3)
+	c.Assert(cache.Exists(1), IsTrue)
+	c.Assert(cache.Exists(2), IsFalse)
+	c.Assert(cache.Exists(3), IsFalse)
+	c.Assert(cache.Shrink(0), Equals, 3)
+	c.Assert(cache.Len(), Equals, 0)
+}
+
 func (s *testRegionCacheSuite) TestLRUCache(c *C) {
 	cache := newLRU(3)
 
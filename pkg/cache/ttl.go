@@ -15,6 +15,7 @@ package cache
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -122,6 +123,28 @@ func (c *ttlCache) pop() (interface{}, interface{}, bool) {
 	return nil, nil, false
 }
 
+// shrink removes the items which expire earliest until there are at most
+// maxCount items, and returns the number of removed items.
+func (c *ttlCache) shrink(maxCount int) int {
+	c.Lock()
+	defer c.Unlock()
+	count := len(c.items) - maxCount
+	if count <= 0 {
+		return 0
+	}
+	keys := make([]interface{}, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return c.items[keys[i]].expire.Before(c.items[keys[j]].expire)
+	})
+	for _, key := range keys[:count] {
+		delete(c.items, key)
+	}
+	return count
+}
+
 // Len returns current cache size.
 func (c *ttlCache) Len() int {
 	c.RLock()
@@ -212,6 +235,12 @@ func (c *TTLUint64) Remove(key uint64) {
 	c.ttlCache.remove(key)
 }
 
+// Shrink removes the IDs which expire earliest until there are at most
+// maxCount IDs, and returns the number of removed IDs.
+func (c *TTLUint64) Shrink(maxCount int) int {
+	return c.ttlCache.shrink(maxCount)
+}
+
 // PutWithTTL puts an item into cache with specified TTL.
 func (c *TTLUint64) PutWithTTL(key uint64, value interface{}, ttl time.Duration) {
 	c.ttlCache.putWithTTL(key, value, ttl)
@@ -257,6 +286,11 @@ func (c *TTLString) Get(id string) (interface{}, bool) {
 	return c.ttlCache.get(id)
 }
 
+// Remove removes the key.
+func (c *TTLString) Remove(key string) {
+	c.ttlCache.remove(key)
+}
+
 // GetAllID returns all key ids
 func (c *TTLString) GetAllID() []string {
 	keys := c.ttlCache.getKeys()
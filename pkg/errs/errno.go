@@ -74,6 +74,8 @@ var (
 	ErrUnknownOperatorStep      = errors.Normalize("unknown operator step found", errors.RFCCodeText("PD:schedule:ErrUnknownOperatorStep"))
 	ErrMergeOperator            = errors.Normalize("merge operator error, %s", errors.RFCCodeText("PD:schedule:ErrMergeOperator"))
 	ErrCreateOperator           = errors.Normalize("unable to create operator, %s", errors.RFCCodeText("PD:schedule:ErrCreateOperator"))
+	ErrRegionLocked             = errors.Normalize("region %v is locked by operator %s", errors.RFCCodeText("PD:schedule:ErrRegionLocked"))
+	ErrCheckerNotFound          = errors.Normalize("checker not found", errors.RFCCodeText("PD:schedule:ErrCheckerNotFound"))
 )
 
 // scheduler errors
@@ -86,6 +88,7 @@ var (
 	ErrCacheOverflow                    = errors.Normalize("cache overflow", errors.RFCCodeText("PD:scheduler:ErrCacheOverflow"))
 	ErrInternalGrowth                   = errors.Normalize("unknown interval growth type error", errors.RFCCodeText("PD:scheduler:ErrInternalGrowth"))
 	ErrSchedulerCreateFuncNotRegistered = errors.Normalize("create func of %v is not registered", errors.RFCCodeText("PD:scheduler:ErrSchedulerCreateFuncNotRegistered"))
+	ErrSchedulerInvalid                 = errors.Normalize("invalid scheduler: %s", errors.RFCCodeText("PD:scheduler:ErrSchedulerInvalid"))
 )
 
 // placement errors
@@ -98,8 +101,9 @@ var (
 
 // cluster errors
 var (
-	ErrNotBootstrapped = errors.Normalize("TiKV cluster not bootstrapped, please start TiKV first", errors.RFCCodeText("PD:cluster:ErrNotBootstrapped"))
-	ErrStoreIsUp       = errors.Normalize("store is still up, please remove store gracefully", errors.RFCCodeText("PD:cluster:ErrStoreIsUp"))
+	ErrNotBootstrapped          = errors.Normalize("TiKV cluster not bootstrapped, please start TiKV first", errors.RFCCodeText("PD:cluster:ErrNotBootstrapped"))
+	ErrStoreIsUp                = errors.Normalize("store is still up, please remove store gracefully", errors.RFCCodeText("PD:cluster:ErrStoreIsUp"))
+	ErrStoreRemovalNotConfirmed = errors.Normalize("the confirmation token to remove store %d is invalid or expired", errors.RFCCodeText("PD:cluster:ErrStoreRemovalNotConfirmed"))
 )
 
 // versioninfo errors
@@ -249,6 +253,7 @@ var (
 // plugin errors
 var (
 	ErrLoadPlugin       = errors.Normalize("failed to load plugin", errors.RFCCodeText("PD:plugin:ErrLoadPlugin"))
+	ErrCheckPlugin      = errors.Normalize("plugin %s is unhealthy, %s", errors.RFCCodeText("PD:plugin:ErrCheckPlugin"))
 	ErrLookupPluginFunc = errors.Normalize("failed to lookup plugin function", errors.RFCCodeText("PD:plugin:ErrLookupPluginFunc"))
 )
 
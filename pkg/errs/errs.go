@@ -14,6 +14,8 @@
 package errs
 
 import (
+	"time"
+
 	"github.com/pingcap/errors"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -33,3 +35,48 @@ func ZapError(err error, causeError ...error) zap.Field {
 	}
 	return zap.Field{Key: "error", Type: zapcore.ErrorType, Interface: err}
 }
+
+// RetryableError marks a transient failure, such as no store being available
+// right now. Retrying after RetryAfter may succeed, while other errors are
+// regarded as permanent.
+type RetryableError struct {
+	Cause      error
+	RetryAfter time.Duration
+}
+
+// NewRetryableError wraps the cause into a RetryableError.
+func NewRetryableError(cause error, retryAfter time.Duration) *RetryableError {
+	return &RetryableError{Cause: cause, RetryAfter: retryAfter}
+}
+
+// Error implements the error interface.
+func (e *RetryableError) Error() string {
+	if e.Cause == nil {
+		return "retryable error"
+	}
+	return e.Cause.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *RetryableError) Unwrap() error {
+	return e.Cause
+}
+
+// IsRetryable checks whether the error or any error it wraps is a
+// RetryableError, and returns the duration to wait before retrying.
+func IsRetryable(err error) (time.Duration, bool) {
+	for err != nil {
+		if e, ok := err.(*RetryableError); ok {
+			return e.RetryAfter, true
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Cause() error }:
+			err = e.Cause()
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
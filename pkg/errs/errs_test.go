@@ -19,6 +19,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/errors"
@@ -142,3 +143,20 @@ func (s *testErrorSuite) TestErrorWithStack(c *C) {
 	// So they have the same length stack. Move this test to another place need to change the corresponding length.
 	c.Assert(len(m1[strings.Index(m1, "[stack="):]), Equals, len(m2[strings.Index(m2, "[stack="):]))
 }
+
+func (s *testErrorSuite) TestRetryableError(c *C) {
+	err := NewRetryableError(errors.New("no store"), time.Second)
+	c.Assert(err.Error(), Equals, "no store")
+	retryAfter, ok := IsRetryable(err)
+	c.Assert(ok, IsTrue)
+	c.Assert(retryAfter, Equals, time.Second)
+
+	// the wrapped retryable error is still retryable.
+	_, ok = IsRetryable(errors.Annotate(err, "fail to fix"))
+	c.Assert(ok, IsTrue)
+
+	_, ok = IsRetryable(errors.New("invalid rule"))
+	c.Assert(ok, IsFalse)
+	_, ok = IsRetryable(nil)
+	c.Assert(ok, IsFalse)
+}
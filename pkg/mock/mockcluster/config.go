@@ -45,6 +45,46 @@ func (mc *Cluster) SetMaxSnapshotCount(v int) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.MaxSnapshotCount = uint64(v) })
 }
 
+// SetMaxOrphanPeerFixesPerCycle updates the MaxOrphanPeerFixesPerCycle configuration.
+func (mc *Cluster) SetMaxOrphanPeerFixesPerCycle(v int) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.MaxOrphanPeerFixesPerCycle = uint64(v) })
+}
+
+// SetOperatorSurgeThresholdRatio updates the OperatorSurgeThresholdRatio configuration.
+func (mc *Cluster) SetOperatorSurgeThresholdRatio(v float64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.OperatorSurgeThresholdRatio = v })
+}
+
+// SetOperatorSurgeWindow updates the OperatorSurgeWindow configuration.
+func (mc *Cluster) SetOperatorSurgeWindow(v time.Duration) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.OperatorSurgeWindow = typeutil.NewDuration(v) })
+}
+
+// SetPendingPeerGracePeriod updates the PendingPeerGracePeriod configuration.
+func (mc *Cluster) SetPendingPeerGracePeriod(v time.Duration) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.PendingPeerGracePeriod = typeutil.NewDuration(v) })
+}
+
+// SetPeerRoleConversionCooldown updates the PeerRoleConversionCooldown configuration.
+func (mc *Cluster) SetPeerRoleConversionCooldown(v time.Duration) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.PeerRoleConversionCooldown = typeutil.NewDuration(v) })
+}
+
+// SetMaxWaitingOperatorsPerKind updates the MaxWaitingOperatorsPerKind configuration of the given kind.
+func (mc *Cluster) SetMaxWaitingOperatorsPerKind(kind string, v int) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) {
+		if s.MaxWaitingOperatorsPerKind == nil {
+			s.MaxWaitingOperatorsPerKind = make(map[string]uint64)
+		}
+		s.MaxWaitingOperatorsPerKind[kind] = uint64(v)
+	})
+}
+
+// SetMaxTotalOperators updates the MaxTotalOperators configuration.
+func (mc *Cluster) SetMaxTotalOperators(v int) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.MaxTotalOperators = uint64(v) })
+}
+
 // SetEnableMakeUpReplica updates the EnableMakeUpReplica configuration.
 func (mc *Cluster) SetEnableMakeUpReplica(v bool) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.EnableMakeUpReplica = v })
@@ -75,11 +115,41 @@ func (mc *Cluster) SetLeaderSchedulePolicy(v string) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.LeaderSchedulePolicy = v })
 }
 
+// SetBalanceHysteresisRatio updates the BalanceHysteresisRatio configuration.
+func (mc *Cluster) SetBalanceHysteresisRatio(v float64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.BalanceHysteresisRatio = v })
+}
+
+// SetStoreLabelWeights updates the StoreLabelWeights configuration.
+func (mc *Cluster) SetStoreLabelWeights(v map[string]float64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.StoreLabelWeights = v })
+}
+
 // SetTolerantSizeRatio updates the TolerantSizeRatio configuration.
 func (mc *Cluster) SetTolerantSizeRatio(v float64) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.TolerantSizeRatio = v })
 }
 
+// SetCompactionOverheadRatio updates the CompactionOverheadRatio configuration of the given engine.
+func (mc *Cluster) SetCompactionOverheadRatio(engine string, ratio float64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) {
+		if s.CompactionOverheadRatio == nil {
+			s.CompactionOverheadRatio = make(map[string]float64)
+		}
+		s.CompactionOverheadRatio[engine] = ratio
+	})
+}
+
+// SetMinAvailableStorageBytes updates the MinAvailableStorageBytes configuration.
+func (mc *Cluster) SetMinAvailableStorageBytes(v uint64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.MinAvailableStorageBytes = typeutil.ByteSize(v) })
+}
+
+// SetMaxRegionsPerStore updates the MaxRegionsPerStore configuration.
+func (mc *Cluster) SetMaxRegionsPerStore(v int) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.MaxRegionsPerStore = uint64(v) })
+}
+
 // SetRegionScoreFormulaVersion updates the RegionScoreFormulaVersion configuration.
 func (mc *Cluster) SetRegionScoreFormulaVersion(v string) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.RegionScoreFormulaVersion = v })
@@ -100,6 +170,16 @@ func (mc *Cluster) SetMergeScheduleLimit(v int) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.MergeScheduleLimit = uint64(v) })
 }
 
+// SetHotRegionScheduleLimitPerStore updates the HotRegionScheduleLimitPerStore configuration.
+func (mc *Cluster) SetHotRegionScheduleLimitPerStore(v float64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.HotRegionScheduleLimitPerStore = v })
+}
+
+// SetEnableWorkStealing updates the EnableWorkStealing configuration.
+func (mc *Cluster) SetEnableWorkStealing(v bool) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.EnableWorkStealing = v })
+}
+
 // SetHotRegionScheduleLimit updates the HotRegionScheduleLimit configuration.
 func (mc *Cluster) SetHotRegionScheduleLimit(v int) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.HotRegionScheduleLimit = uint64(v) })
@@ -110,6 +190,21 @@ func (mc *Cluster) SetHotRegionCacheHitsThreshold(v int) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.HotRegionCacheHitsThreshold = uint64(v) })
 }
 
+// SetScatterPartitionLabel updates the ScatterPartitionLabel configuration.
+func (mc *Cluster) SetScatterPartitionLabel(v string) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.ScatterPartitionLabel = v })
+}
+
+// SetWriteAmplificationFactor updates the WriteAmplificationFactor configuration.
+func (mc *Cluster) SetWriteAmplificationFactor(v float64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.WriteAmplificationFactor = v })
+}
+
+// SetPreferredLeaderLabels updates the PreferredLeaderLabels configuration.
+func (mc *Cluster) SetPreferredLeaderLabels(v map[string]string) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.PreferredLeaderLabels = v })
+}
+
 // SetEnablePlacementRules updates the EnablePlacementRules configuration.
 func (mc *Cluster) SetEnablePlacementRules(v bool) {
 	mc.updateReplicationConfig(func(r *config.ReplicationConfig) { r.EnablePlacementRules = v })
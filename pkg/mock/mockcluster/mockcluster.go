@@ -82,6 +82,11 @@ func (mc *Cluster) ScanRegions(startKey, endKey []byte, limit int) []*core.Regio
 	return mc.Regions.ScanRange(startKey, endKey, limit)
 }
 
+// GetRegionStats returns the statistics of the regions in the range.
+func (mc *Cluster) GetRegionStats(startKey, endKey []byte) *statistics.RegionStats {
+	return statistics.GetRegionStats(mc.Regions.ScanRange(startKey, endKey, -1))
+}
+
 // LoadRegion puts region info without leader
 func (mc *Cluster) LoadRegion(regionID uint64, followerIds ...uint64) {
 	//  regions load from etcd will have no leader
@@ -34,17 +34,18 @@ func (b *ByteSize) UnmarshalJSON(text []byte) error {
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	v, err := units.RAMInBytes(s)
-	if err != nil {
-		return errors.WithStack(err)
-	}
-	*b = ByteSize(v)
-	return nil
+	return b.ParseFromText(s)
 }
 
 // UnmarshalText parses a Toml string into the byte size.
 func (b *ByteSize) UnmarshalText(text []byte) error {
-	v, err := units.RAMInBytes(string(text))
+	return b.ParseFromText(string(text))
+}
+
+// ParseFromText parses a human-readable size such as "1.598TiB" or "512MB"
+// into the byte size. It accepts the format produced by MarshalJSON.
+func (b *ByteSize) ParseFromText(s string) error {
+	v, err := units.RAMInBytes(s)
 	if err != nil {
 		return errors.WithStack(err)
 	}
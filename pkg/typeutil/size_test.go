@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/docker/go-units"
 	. "github.com/pingcap/check"
 )
 
@@ -43,3 +44,19 @@ func (s *testSizeSuite) TestJSON(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(string(o), Equals, `"1.598TiB"`)
 }
+
+func (s *testSizeSuite) TestParseFromText(c *C) {
+	var b ByteSize
+	c.Assert(b.ParseFromText("1.598TiB"), IsNil)
+	o, err := json.Marshal(b)
+	c.Assert(err, IsNil)
+	c.Assert(string(o), Equals, `"1.598TiB"`)
+
+	c.Assert(b.ParseFromText("512MB"), IsNil)
+	c.Assert(b, Equals, ByteSize(512*units.MiB))
+	c.Assert(b.ParseFromText("1KiB"), IsNil)
+	c.Assert(b, Equals, ByteSize(units.KiB))
+	c.Assert(b.ParseFromText("100"), IsNil)
+	c.Assert(b, Equals, ByteSize(100))
+	c.Assert(b.ParseFromText("1.5XB"), NotNil)
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/errors"
+	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server"
+	"github.com/unrolled/render"
+)
+
+type checkerHandler struct {
+	*server.Handler
+	r *render.Render
+}
+
+func newCheckerHandler(svr *server.Server, r *render.Render) *checkerHandler {
+	return &checkerHandler{
+		Handler: svr.GetHandler(),
+		r:       r,
+	}
+}
+
+// FIXME: details of input json body params
+// @Tags checker
+// @Summary Pause or resume a checker.
+// @Accept json
+// @Param name path string true "The name of the checker."
+// @Param body body object true "json params"
+// @Produce json
+// @Success 200 {string} string "Pause or resume the checker successfully."
+// @Failure 400 {string} string "Bad format request."
+// @Failure 404 {string} string "The checker is not found."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /checkers/{name} [patch]
+func (h *checkerHandler) PauseOrResume(w http.ResponseWriter, r *http.Request) {
+	var input map[string]int
+	if err := apiutil.ReadJSONRespondError(h.r, w, r.Body, &input); err != nil {
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	t, ok := input["delay"]
+	if !ok {
+		h.r.JSON(w, http.StatusBadRequest, "missing pause time")
+		return
+	}
+	if err := h.PauseOrResumeChecker(name, int64(t)); err != nil {
+		if errors.ErrorEqual(err, errs.ErrCheckerNotFound.FastGenByArgs()) {
+			h.r.JSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, "Pause or resume the checker successfully.")
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/server"
+)
+
+var _ = Suite(&testCheckerSuite{})
+
+type testCheckerSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testCheckerSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1/checkers", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testCheckerSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testCheckerSuite) patch(c *C, name string, body string) int {
+	req, err := http.NewRequest("PATCH", s.urlPrefix+"/"+name, bytes.NewBufferString(body))
+	c.Assert(err, IsNil)
+	resp, err := testDialClient.Do(req)
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func (s *testCheckerSuite) TestPauseOrResume(c *C) {
+	c.Assert(s.patch(c, "merge-checker", `{"delay": 300}`), Equals, http.StatusOK)
+	pauseUntil, err := s.svr.GetRaftCluster().GetCheckerPauseUntil("merge-checker")
+	c.Assert(err, IsNil)
+	c.Assert(pauseUntil, Greater, int64(0))
+
+	c.Assert(s.patch(c, "merge-checker", `{"delay": 0}`), Equals, http.StatusOK)
+	pauseUntil, err = s.svr.GetRaftCluster().GetCheckerPauseUntil("merge-checker")
+	c.Assert(err, IsNil)
+	c.Assert(pauseUntil, Equals, int64(0))
+
+	c.Assert(s.patch(c, "merge-checker", `{}`), Equals, http.StatusBadRequest)
+	c.Assert(s.patch(c, "not-exist", `{"delay": 300}`), Equals, http.StatusNotFound)
+}
@@ -15,8 +15,10 @@ package api
 
 import (
 	"net/http"
+	"sort"
 
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/core"
 	"github.com/unrolled/render"
 )
 
@@ -55,3 +57,65 @@ func (h *clusterHandler) GetClusterStatus(w http.ResponseWriter, r *http.Request
 	}
 	h.rd.JSON(w, http.StatusOK, status)
 }
+
+// TopologyNode is a location in the topology inferred from the location labels.
+type TopologyNode struct {
+	// Label and Value are empty for the root.
+	Label string `json:"label,omitempty"`
+	Value string `json:"value,omitempty"`
+	// StoreIDs are the stores in this location, including the ones of its children.
+	StoreIDs []uint64        `json:"store_ids"`
+	Children []*TopologyNode `json:"children,omitempty"`
+}
+
+func (n *TopologyNode) child(label, value string) *TopologyNode {
+	for _, c := range n.Children {
+		if c.Value == value {
+			return c
+		}
+	}
+	c := &TopologyNode{Label: label, Value: value}
+	n.Children = append(n.Children, c)
+	return c
+}
+
+func (n *TopologyNode) sort() {
+	sort.Slice(n.StoreIDs, func(i, j int) bool { return n.StoreIDs[i] < n.StoreIDs[j] })
+	sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Value < n.Children[j].Value })
+	for _, c := range n.Children {
+		c.sort()
+	}
+}
+
+// buildTopology builds the topology tree by the location labels. A store which
+// lacks a label stays at the location of the labels it has.
+func buildTopology(stores []*core.StoreInfo, locationLabels []string) *TopologyNode {
+	root := &TopologyNode{StoreIDs: []uint64{}}
+	for _, store := range stores {
+		if store.IsTombstone() {
+			continue
+		}
+		node := root
+		node.StoreIDs = append(node.StoreIDs, store.GetID())
+		for _, label := range locationLabels {
+			value := store.GetLabelValue(label)
+			if value == "" {
+				break
+			}
+			node = node.child(label, value)
+			node.StoreIDs = append(node.StoreIDs, store.GetID())
+		}
+	}
+	root.sort()
+	return root
+}
+
+// @Tags cluster
+// @Summary Get the topology inferred from the location labels of the stores.
+// @Produce json
+// @Success 200 {object} TopologyNode
+// @Router /cluster/topology [get]
+func (h *clusterHandler) GetTopology(w http.ResponseWriter, r *http.Request) {
+	rc := h.svr.GetRaftCluster()
+	h.rd.JSON(w, http.StatusOK, buildTopology(rc.GetStores(), rc.GetOpts().GetLocationLabels()))
+}
@@ -85,3 +85,62 @@ func (s *testClusterSuite) testGetClusterStatus(c *C) {
 	c.Assert(status.RaftBootstrapTime.After(now), IsTrue)
 	c.Assert(status.IsInitialized, IsTrue)
 }
+
+var _ = Suite(&testClusterTopologySuite{})
+
+type testClusterTopologySuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testClusterTopologySuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c, func(cfg *config.Config) {
+		cfg.Replication.LocationLabels = []string{"zone", "host"}
+	})
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testClusterTopologySuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testClusterTopologySuite) TestTopology(c *C) {
+	labels := func(zone, host string) []*metapb.StoreLabel {
+		var ls []*metapb.StoreLabel
+		if zone != "" {
+			ls = append(ls, &metapb.StoreLabel{Key: "zone", Value: zone})
+		}
+		if host != "" {
+			ls = append(ls, &metapb.StoreLabel{Key: "host", Value: host})
+		}
+		return ls
+	}
+	mustPutStore(c, s.svr, 2, metapb.StoreState_Up, labels("z2", "h1"))
+	mustPutStore(c, s.svr, 3, metapb.StoreState_Up, labels("z1", "h2"))
+	mustPutStore(c, s.svr, 4, metapb.StoreState_Up, labels("z1", "h1"))
+	mustPutStore(c, s.svr, 5, metapb.StoreState_Up, labels("z1", ""))
+
+	url := fmt.Sprintf("%s/cluster/topology", s.urlPrefix)
+	topology := &TopologyNode{}
+	c.Assert(readJSON(testDialClient, url, topology), IsNil)
+	// Store 1 is bootstrapped without labels.
+	c.Assert(topology.StoreIDs, DeepEquals, []uint64{1, 2, 3, 4, 5})
+	c.Assert(topology.Children, HasLen, 2)
+	z1, z2 := topology.Children[0], topology.Children[1]
+	c.Assert(z1.Label, Equals, "zone")
+	c.Assert(z1.Value, Equals, "z1")
+	c.Assert(z1.StoreIDs, DeepEquals, []uint64{3, 4, 5})
+	c.Assert(z1.Children, HasLen, 2)
+	c.Assert(z1.Children[0].Label, Equals, "host")
+	c.Assert(z1.Children[0].Value, Equals, "h1")
+	c.Assert(z1.Children[0].StoreIDs, DeepEquals, []uint64{4})
+	c.Assert(z1.Children[1].StoreIDs, DeepEquals, []uint64{3})
+	c.Assert(z2.StoreIDs, DeepEquals, []uint64{2})
+	c.Assert(z2.Children, HasLen, 1)
+}
@@ -161,6 +161,14 @@ func (s *testConfigSuite) TestConfigSchedule(c *C) {
 	sc1 := &config.ScheduleConfig{}
 	c.Assert(readJSON(testDialClient, addr, sc1), IsNil)
 	c.Assert(*sc, DeepEquals, *sc1)
+
+	postData, err = json.Marshal(map[string]interface{}{"patrol-region-batch-size": 256})
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(testDialClient, fmt.Sprintf("%s/config", s.urlPrefix), postData), IsNil)
+	c.Assert(s.svr.GetPersistOptions().GetPatrolRegionBatchSize(), Equals, 256)
+	postData, err = json.Marshal(map[string]interface{}{"patrol-region-batch-size": 1})
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(testDialClient, fmt.Sprintf("%s/config", s.urlPrefix), postData), NotNil)
 }
 
 func (s *testConfigSuite) TestConfigReplication(c *C) {
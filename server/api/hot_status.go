@@ -15,12 +15,18 @@ package api
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/gorilla/mux"
+	"github.com/pingcap/errcode"
+	"github.com/tikv/pd/pkg/apiutil"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/statistics"
 	"github.com/unrolled/render"
 )
 
+const defaultHotPeerCountWindow = time.Hour
+
 type hotStatusHandler struct {
 	*server.Handler
 	rd *render.Render
@@ -32,6 +38,9 @@ type HotStoreStats struct {
 	BytesReadStats  map[uint64]float64 `json:"bytes-read-rate,omitempty"`
 	KeysWriteStats  map[uint64]float64 `json:"keys-write-rate,omitempty"`
 	KeysReadStats   map[uint64]float64 `json:"keys-read-rate,omitempty"`
+	// the summaries are computed from the hot peers on each store.
+	WriteSummary map[uint64]*statistics.HotStoreSummary `json:"write-summary,omitempty"`
+	ReadSummary  map[uint64]*statistics.HotStoreSummary `json:"read-summary,omitempty"`
 }
 
 func newHotStatusHandler(handler *server.Handler, rd *render.Render) *hotStatusHandler {
@@ -77,5 +86,57 @@ func (h *hotStatusHandler) GetHotStores(w http.ResponseWriter, r *http.Request)
 		stats.KeysWriteStats[id] = loads[statistics.StoreWriteKeys]
 		stats.KeysReadStats[id] = loads[statistics.StoreReadKeys]
 	}
+	stats.WriteSummary = h.GetHotStoresSummary(statistics.WriteFlow)
+	stats.ReadSummary = h.GetHotStoresSummary(statistics.ReadFlow)
 	h.rd.JSON(w, http.StatusOK, stats)
 }
+
+// @Tags hotspot
+// @Summary Get the number of hot peers of a store over time.
+// @Param id path integer true "Store Id"
+// @Param kind query string false "The flow kind, write or read" default(write)
+// @Param window query string false "The duration of the time series" default(1h)
+// @Produce json
+// @Success 200 {array} statistics.TimeSeriesPoint
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /hotspot/stores/{id}/timeseries [get]
+func (h *hotStatusHandler) GetStoreHotPeerCountTimeSeries(w http.ResponseWriter, r *http.Request) {
+	rc, err := h.GetRaftCluster()
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	kind := statistics.WriteFlow
+	switch r.URL.Query().Get("kind") {
+	case "", "write":
+	case "read":
+		kind = statistics.ReadFlow
+	default:
+		h.rd.JSON(w, http.StatusBadRequest, "invalid kind")
+		return
+	}
+	window := defaultHotPeerCountWindow
+	if v := r.URL.Query().Get("window"); v != "" {
+		if window, err = time.ParseDuration(v); err != nil || window <= 0 {
+			h.rd.JSON(w, http.StatusBadRequest, "invalid window")
+			return
+		}
+	}
+
+	if rc.GetStore(storeID) == nil {
+		h.rd.JSON(w, http.StatusNotFound, server.ErrStoreNotFound(storeID).Error())
+		return
+	}
+	points := rc.GetStoreHotPeerCountTimeSeries(storeID, kind, window)
+	if points == nil {
+		points = []statistics.TimeSeriesPoint{}
+	}
+	h.rd.JSON(w, http.StatusOK, points)
+}
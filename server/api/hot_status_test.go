@@ -19,6 +19,7 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/tikv/pd/server"
 	_ "github.com/tikv/pd/server/schedulers"
+	"github.com/tikv/pd/server/statistics"
 )
 
 var _ = Suite(&testHotStatusSuite{})
@@ -47,4 +48,20 @@ func (s testHotStatusSuite) TestGetHotStore(c *C) {
 	stat := HotStoreStats{}
 	err := readJSON(testDialClient, s.urlPrefix+"/stores", &stat)
 	c.Assert(err, IsNil)
+	// no hot peer yet.
+	c.Assert(stat.WriteSummary, HasLen, 0)
+	c.Assert(stat.ReadSummary, HasLen, 0)
+}
+
+func (s testHotStatusSuite) TestGetStoreHotPeerCountTimeSeries(c *C) {
+	var points []statistics.TimeSeriesPoint
+	url := s.urlPrefix + "/stores/1/timeseries"
+	err := readJSON(testDialClient, url, &points)
+	c.Assert(err, IsNil)
+	// not sampled yet.
+	c.Assert(points, HasLen, 0)
+	c.Assert(readJSON(testDialClient, url+"?kind=read&window=10m", &points), IsNil)
+	c.Assert(readJSON(testDialClient, url+"?kind=unknown", &points), NotNil)
+	c.Assert(readJSON(testDialClient, url+"?window=abc", &points), NotNil)
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/stores/10086/timeseries", &points), NotNil)
 }
@@ -14,6 +14,7 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 
@@ -63,9 +64,21 @@ func (h *operatorHandler) Get(w http.ResponseWriter, r *http.Request) {
 	h.r.JSON(w, http.StatusOK, op)
 }
 
+// operatorDetail is the operator with the details asked by the query.
+type operatorDetail struct {
+	Operator *operator.Operator `json:"operator"`
+	// AgeSeconds is the seconds the operator has been running.
+	AgeSeconds *float64 `json:"age_seconds,omitempty"`
+	// FitSummary is the summary of the placement rule fit which triggers the
+	// operator, see operator.Operator.FitSummary.
+	FitSummary json.RawMessage `json:"fit_summary,omitempty"`
+}
+
 // @Tags operator
 // @Summary List pending operators.
 // @Param kind query string false "Specify the operator kind." Enums(admin, leader, region)
+// @Param with_age query bool false "Return the running seconds of each operator."
+// @Param with_fit query bool false "Return the rule fit summary of each operator created by the rule checker."
 // @Produce json
 // @Success 200 {array} operator.Operator
 // @Failure 500 {string} string "PD server failed to proceed the request."
@@ -104,6 +117,24 @@ func (h *operatorHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	withAge, _ := strconv.ParseBool(r.URL.Query().Get("with_age"))
+	withFit, _ := strconv.ParseBool(r.URL.Query().Get("with_fit"))
+	if withAge || withFit {
+		details := make([]operatorDetail, 0, len(results))
+		for _, op := range results {
+			detail := operatorDetail{Operator: op}
+			if withAge {
+				age := op.RunningTime().Seconds()
+				detail.AgeSeconds = &age
+			}
+			if withFit && op.FitSummary != "" {
+				detail.FitSummary = json.RawMessage(op.FitSummary)
+			}
+			details = append(details, detail)
+		}
+		h.r.JSON(w, http.StatusOK, details)
+		return
+	}
 	h.r.JSON(w, http.StatusOK, results)
 }
 
@@ -103,6 +103,14 @@ func (s *testOperatorSuite) TestAddRemovePeer(c *C) {
 	c.Assert(err, IsNil)
 	operator = mustReadURL(c, regionURL)
 	c.Assert(strings.Contains(operator, "add learner peer 2 on store 4"), IsTrue)
+	operators := mustReadURL(c, fmt.Sprintf("%s/operators?with_age=true", s.urlPrefix))
+	c.Assert(strings.Contains(operators, "add learner peer 2 on store 4"), IsTrue)
+	c.Assert(strings.Contains(operators, `"age_seconds"`), IsTrue)
+	// the operator not created by the rule checker has no fit summary.
+	operators = mustReadURL(c, fmt.Sprintf("%s/operators?with_fit=true", s.urlPrefix))
+	c.Assert(strings.Contains(operators, "add learner peer 2 on store 4"), IsTrue)
+	c.Assert(strings.Contains(operators, `"age_seconds"`), IsFalse)
+	c.Assert(strings.Contains(operators, `"fit_summary"`), IsFalse)
 
 	// Fail to add peer to tombstone store.
 	err = s.svr.GetRaftCluster().RemoveStore(3, true)
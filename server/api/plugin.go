@@ -14,9 +14,18 @@
 package api
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/tikv/pd/pkg/apiutil"
 	"github.com/tikv/pd/server"
@@ -64,6 +73,53 @@ func (h *pluginHandler) UnloadPlugin(w http.ResponseWriter, r *http.Request) {
 	h.processPluginCommand(w, r, cluster.PluginUnload)
 }
 
+// @Tags plugin
+// @Summary Download a plugin from the url and load it.
+// @Accept json
+// @Param body body object true "json params, the checksum is in the form of sha256:<hex>"
+// @Produce json
+// @Success 200 {object} map[string]string "The local path of the loaded plugin, which is used to unload it."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 403 {string} string "Loading the plugin from the url is not allowed."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /plugin/load [post]
+func (h *pluginHandler) LoadRemotePlugin(w http.ResponseWriter, r *http.Request) {
+	data := make(map[string]string)
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &data); err != nil {
+		return
+	}
+	sum, err := parsePluginChecksum(data["checksum"])
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	cfg := h.GetRemotePluginConfig()
+	if !cfg.Enable {
+		h.rd.JSON(w, http.StatusForbidden, "loading plugins from urls is disabled")
+		return
+	}
+	u, err := url.Parse(data["url"])
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid plugin url")
+		return
+	}
+	if !cfg.IsURLAllowed(u.String()) {
+		h.rd.JSON(w, http.StatusForbidden, "plugin url is not allowed")
+		return
+	}
+	path, err := downloadPlugin(u.String(), sum, int64(cfg.MaxSize))
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := h.PluginLoadRemote(path); err != nil {
+		os.Remove(path)
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, map[string]string{"plugin-path": path})
+}
+
 func (h *pluginHandler) processPluginCommand(w http.ResponseWriter, r *http.Request, action string) {
 	data := make(map[string]string)
 	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &data); err != nil {
@@ -106,3 +162,55 @@ func pathExists(path string) (bool, error) {
 	}
 	return false, err
 }
+
+const pluginDownloadTimeout = 5 * time.Minute
+
+// parsePluginChecksum parses the checksum in the form of sha256:<hex>.
+func parsePluginChecksum(checksum string) ([]byte, error) {
+	const prefix = "sha256:"
+	if !strings.HasPrefix(checksum, prefix) {
+		return nil, errors.New("checksum should be in the form of sha256:<hex>")
+	}
+	sum, err := hex.DecodeString(strings.TrimPrefix(checksum, prefix))
+	if err != nil || len(sum) != sha256.Size {
+		return nil, errors.New("invalid sha256 checksum")
+	}
+	return sum, nil
+}
+
+// downloadPlugin downloads the plugin to a temporary file and verifies its
+// checksum. It fails if the plugin is larger than maxSize. It returns the path
+// of the file.
+func downloadPlugin(pluginURL string, sum []byte, maxSize int64) (string, error) {
+	client := &http.Client{Timeout: pluginDownloadTimeout}
+	resp, err := client.Get(pluginURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download plugin, status: %s", resp.Status)
+	}
+
+	f, err := ioutil.TempFile("", "pd-plugin-*.so")
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	// One more byte is read to tell whether the plugin exceeds the max size.
+	n, err := io.Copy(io.MultiWriter(f, hash), io.LimitReader(resp.Body, maxSize+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && n > maxSize {
+		err = fmt.Errorf("plugin is larger than the max size %d", maxSize)
+	}
+	if err == nil && !bytes.Equal(hash.Sum(nil), sum) {
+		err = errors.New("plugin checksum mismatch")
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
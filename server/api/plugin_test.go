@@ -0,0 +1,71 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testPluginSuite{})
+
+type testPluginSuite struct{}
+
+func (s *testPluginSuite) TestParsePluginChecksum(c *C) {
+	sum := sha256.Sum256([]byte("plugin"))
+	parsed, err := parsePluginChecksum(fmt.Sprintf("sha256:%x", sum))
+	c.Assert(err, IsNil)
+	c.Assert(parsed, DeepEquals, sum[:])
+
+	for _, checksum := range []string{"", fmt.Sprintf("%x", sum), "sha256:xyz", "sha256:abcd"} {
+		_, err = parsePluginChecksum(checksum)
+		c.Assert(err, NotNil)
+	}
+}
+
+func (s *testPluginSuite) TestDownloadPlugin(c *C) {
+	content := []byte("plugin")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/plugin.so" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(content)
+	}))
+	defer srv.Close()
+	sum := sha256.Sum256(content)
+
+	path, err := downloadPlugin(srv.URL+"/plugin.so", sum[:], int64(len(content)))
+	c.Assert(err, IsNil)
+	defer os.Remove(path)
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, IsNil)
+	c.Assert(data, DeepEquals, content)
+
+	wrongSum := sha256.Sum256([]byte("other"))
+	_, err = downloadPlugin(srv.URL+"/plugin.so", wrongSum[:], int64(len(content)))
+	c.Assert(err, ErrorMatches, ".*checksum mismatch.*")
+
+	_, err = downloadPlugin(srv.URL+"/plugin.so", sum[:], int64(len(content)-1))
+	c.Assert(err, ErrorMatches, ".*larger than the max size.*")
+
+	_, err = downloadPlugin(srv.URL+"/not-found.so", sum[:], int64(len(content)))
+	c.Assert(err, NotNil)
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/tikv/pd/pkg/apiutil"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/server/schedule/operator"
 	"github.com/tikv/pd/server/statistics"
 	"github.com/unrolled/render"
@@ -56,6 +57,9 @@ type RegionInfo struct {
 	ApproximateKeys int64             `json:"approximate_keys"`
 
 	ReplicationStatus *ReplicationStatus `json:"replication_status,omitempty"`
+
+	// OperatorHistory is only filled when it is requested.
+	OperatorHistory []*schedule.OperatorRecord `json:"operator_history,omitempty"`
 }
 
 // ReplicationStatus represents the replication mode status of the region.
@@ -125,6 +129,7 @@ func newRegionHandler(svr *server.Server, rd *render.Render) *regionHandler {
 // @Tags region
 // @Summary Search for a region by region ID.
 // @Param id path integer true "Region Id"
+// @Param include_operator_history query bool false "Whether to include the recently finished operators"
 // @Produce json
 // @Success 200 {object} RegionInfo
 // @Failure 400 {string} string "The input is invalid."
@@ -140,8 +145,11 @@ func (h *regionHandler) GetRegionByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	regionInfo := rc.GetRegion(regionID)
-	h.rd.JSON(w, http.StatusOK, NewRegionInfo(regionInfo))
+	regionInfo := NewRegionInfo(rc.GetRegion(regionID))
+	if include, _ := strconv.ParseBool(r.URL.Query().Get("include_operator_history")); include && regionInfo != nil {
+		regionInfo.OperatorHistory = rc.GetOperatorController().GetRegionOperatorHistory(regionID)
+	}
+	h.rd.JSON(w, http.StatusOK, regionInfo)
 }
 
 // @Tags region
@@ -265,17 +273,40 @@ func (h *regionsHandler) GetStoreRegions(w http.ResponseWriter, r *http.Request)
 }
 
 // @Tags region
-// @Summary List all regions that miss peer.
+// @Summary List all regions that miss peer, which have fewer peers than the replication factor, ordered by region ID.
+// @Param limit query integer false "Limit count, 0 means no limit" default(0)
+// @Param offset query integer false "Offset of the first region" default(0)
 // @Produce json
 // @Success 200 {object} RegionsInfo
-// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Failure 400 {string} string "The input is invalid."
 // @Router /regions/check/miss-peer [get]
 func (h *regionsHandler) GetMissPeerRegions(w http.ResponseWriter, r *http.Request) {
-	handler := h.svr.GetHandler()
-	regions, err := handler.GetRegionsByType(statistics.MissPeer)
-	if err != nil {
-		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
-		return
+	rc := h.svr.GetRaftCluster()
+	var limit, offset int
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			h.rd.JSON(w, http.StatusBadRequest, fmt.Sprintf("invalid limit: %s", limitStr))
+			return
+		}
+	}
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			h.rd.JSON(w, http.StatusBadRequest, fmt.Sprintf("invalid offset: %s", offsetStr))
+			return
+		}
+	}
+	regions := rc.GetMissingPeerRegions()
+	sort.Slice(regions, func(i, j int) bool { return regions[i].GetID() < regions[j].GetID() })
+	if offset > len(regions) {
+		offset = len(regions)
+	}
+	regions = regions[offset:]
+	if limit > 0 && limit < len(regions) {
+		regions = regions[:limit]
 	}
 	regionsInfo := convertToAPIRegions(regions)
 	h.rd.JSON(w, http.StatusOK, regionsInfo)
@@ -728,6 +759,64 @@ func (h *regionsHandler) ScatterRegions(w http.ResponseWriter, r *http.Request)
 	h.rd.JSON(w, http.StatusOK, &s)
 }
 
+// @Tags region
+// @Summary Plan the scatter of regions by given key ranges or regions id distributed by given group without executing it
+// @Accept json
+// @Param body body object true "json params"
+// @Produce json
+// @Success 200 {object} map[uint64]string "The planned operators of the regions"
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /regions/scatter/dryrun [post]
+func (h *regionsHandler) ScatterRegionsDryRun(w http.ResponseWriter, r *http.Request) {
+	rc := h.svr.GetRaftCluster()
+	var input map[string]interface{}
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	group, _ := input["group"].(string)
+	regions := make(map[uint64]*core.RegionInfo)
+	_, ok1 := input["start_key"].(string)
+	_, ok2 := input["end_key"].(string)
+	if ok1 && ok2 {
+		startKey, _, err := parseKey("start_key", input)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		endKey, _, err := parseKey("end_key", input)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for _, region := range rc.ScanRegions(startKey, endKey, -1) {
+			regions[region.GetID()] = region
+		}
+	} else {
+		ids, ok := input["regions_id"].([]interface{})
+		if !ok {
+			h.rd.JSON(w, http.StatusBadRequest, "the key range or the regions id is required")
+			return
+		}
+		for _, v := range ids {
+			id, ok := v.(float64)
+			if !ok {
+				h.rd.JSON(w, http.StatusBadRequest, "invalid region id")
+				return
+			}
+			if region := rc.GetRegion(uint64(id)); region != nil {
+				regions[region.GetID()] = region
+			}
+		}
+	}
+	ops, err := rc.GetRegionScatter().ScatterRegionsDryRun(regions, group)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, ops)
+}
+
 // @Tags region
 // @Summary Split regions with given split keys
 // @Accept json
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/operator"
 )
 
 var _ = Suite(&testRegionSuite{})
@@ -93,6 +94,20 @@ func (s *testRegionSuite) TestRegion(c *C) {
 	c.Assert(r1m["written_keys"].(float64), Equals, float64(r.GetKeysWritten()))
 	c.Assert(r1m["read_bytes"].(float64), Equals, float64(r.GetBytesRead()))
 	c.Assert(r1m["read_keys"].(float64), Equals, float64(r.GetKeysRead()))
+	c.Assert(r1m["operator_history"], IsNil)
+
+	// the finished operators are returned if requested.
+	oc := s.svr.GetRaftCluster().GetOperatorController()
+	op := operator.NewOperator("test", "test", r.GetID(), r.GetRegionEpoch(), operator.OpLeader, operator.TransferLeader{FromStore: 1, ToStore: 2})
+	c.Assert(op.Start(), IsTrue)
+	oc.SetOperator(op)
+	c.Assert(oc.RemoveOperator(op), IsTrue)
+	r3 := &RegionInfo{}
+	err = readJSON(testDialClient, url+"?include_operator_history=true", r3)
+	c.Assert(err, IsNil)
+	c.Assert(r3.OperatorHistory, HasLen, 1)
+	c.Assert(r3.OperatorHistory[0].FinishReason, Equals, "CANCELED")
+	c.Assert(r3.OperatorHistory[0].Steps, DeepEquals, []string{op.Step(0).String()})
 
 	url = fmt.Sprintf("%s/region/key/%s", s.urlPrefix, "a")
 	r2 := &RegionInfo{}
@@ -193,6 +208,48 @@ func (s *testRegionSuite) TestRegions(c *C) {
 	}
 }
 
+func (s *testRegionSuite) TestMissingPeerRegions(c *C) {
+	rs := []*core.RegionInfo{
+		newTestRegionInfo(101, 1, []byte("m1"), []byte("m2")),
+		newTestRegionInfo(102, 1, []byte("m2"), []byte("m3")),
+		newTestRegionInfo(103, 1, []byte("m3"), []byte("m4")),
+		newTestRegionInfo(104, 1, []byte("m4"), []byte("m5"), core.WithAddPeer(&metapb.Peer{Id: 105, StoreId: 2}), core.WithAddPeer(&metapb.Peer{Id: 106, StoreId: 3})),
+	}
+	for _, r := range rs {
+		mustRegionHeartbeat(c, s.svr, r)
+	}
+	url := fmt.Sprintf("%s/regions/check/miss-peer", s.urlPrefix)
+	all := &RegionsInfo{}
+	err := readJSON(testDialClient, url, all)
+	c.Assert(err, IsNil)
+	ids := make(map[uint64]struct{})
+	for i, r := range all.Regions {
+		if i > 0 {
+			c.Assert(r.ID, Greater, all.Regions[i-1].ID)
+		}
+		ids[r.ID] = struct{}{}
+	}
+	for _, id := range []uint64{101, 102, 103} {
+		c.Assert(ids, HasKey, id)
+	}
+	c.Assert(ids, Not(HasKey), uint64(104))
+
+	page := &RegionsInfo{}
+	err = readJSON(testDialClient, url+"?limit=2&offset=1", page)
+	c.Assert(err, IsNil)
+	c.Assert(page.Count, Equals, 2)
+	c.Assert(page.Regions[0].ID, Equals, all.Regions[1].ID)
+	c.Assert(page.Regions[1].ID, Equals, all.Regions[2].ID)
+
+	page = &RegionsInfo{}
+	err = readJSON(testDialClient, fmt.Sprintf("%s?offset=%d", url, all.Count), page)
+	c.Assert(err, IsNil)
+	c.Assert(page.Count, Equals, 0)
+
+	err = readJSON(testDialClient, url+"?limit=-1", page)
+	c.Assert(err, NotNil)
+}
+
 func (s *testRegionSuite) TestStoreRegions(c *C) {
 	r1 := newTestRegionInfo(2, 1, []byte("a"), []byte("b"))
 	r2 := newTestRegionInfo(3, 1, []byte("b"), []byte("c"))
@@ -300,6 +357,27 @@ func (s *testRegionSuite) TestScatterRegions(c *C) {
 	c.Assert(op1 != nil || op2 != nil || op3 != nil, IsTrue)
 }
 
+func (s *testRegionSuite) TestScatterRegionsDryRun(c *C) {
+	r1 := newTestRegionInfo(701, 13, []byte("c1"), []byte("c2"))
+	r1.GetMeta().Peers = append(r1.GetMeta().Peers, &metapb.Peer{Id: 11, StoreId: 13}, &metapb.Peer{Id: 12, StoreId: 13})
+	mustRegionHeartbeat(c, s.svr, r1)
+	mustPutStore(c, s.svr, 13, metapb.StoreState_Up, []*metapb.StoreLabel{})
+	mustPutStore(c, s.svr, 14, metapb.StoreState_Up, []*metapb.StoreLabel{})
+	mustPutStore(c, s.svr, 15, metapb.StoreState_Up, []*metapb.StoreLabel{})
+
+	var ops map[uint64]string
+	err := postJSON(testDialClient, fmt.Sprintf("%s/regions/scatter/dryrun", s.urlPrefix), []byte(`{"regions_id": [701]}`), func(res []byte, _ int) {
+		c.Assert(json.Unmarshal(res, &ops), IsNil)
+	})
+	c.Assert(err, IsNil)
+	c.Assert(len(ops), LessEqual, 1)
+	// The planned operator is not added.
+	c.Assert(s.svr.GetRaftCluster().GetOperatorController().GetOperator(701), IsNil)
+
+	err = postJSON(testDialClient, fmt.Sprintf("%s/regions/scatter/dryrun", s.urlPrefix), []byte(`{}`))
+	c.Assert(err, NotNil)
+}
+
 func (s *testRegionSuite) TestSplitRegions(c *C) {
 	r1 := newTestRegionInfo(601, 13, []byte("aaa"), []byte("ggg"))
 	r1.GetMeta().Peers = append(r1.GetMeta().Peers, &metapb.Peer{Id: 5, StoreId: 13}, &metapb.Peer{Id: 6, StoreId: 13})
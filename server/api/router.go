@@ -68,15 +68,21 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 	schedulerHandler := newSchedulerHandler(svr, rd)
 	apiRouter.HandleFunc("/schedulers", schedulerHandler.List).Methods("GET")
 	apiRouter.HandleFunc("/schedulers", schedulerHandler.Post).Methods("POST")
+	apiRouter.HandleFunc("/schedulers/stats", schedulerHandler.Stats).Methods("GET")
+	apiRouter.HandleFunc("/schedulers/{name}", schedulerHandler.Get).Methods("GET")
 	apiRouter.HandleFunc("/schedulers/{name}", schedulerHandler.Delete).Methods("DELETE")
 	apiRouter.HandleFunc("/schedulers/{name}", schedulerHandler.PauseOrResume).Methods("POST")
 
+	checkerHandler := newCheckerHandler(svr, rd)
+	apiRouter.HandleFunc("/checkers/{name}", checkerHandler.PauseOrResume).Methods("PATCH")
+
 	schedulerConfigHandler := newSchedulerConfigHandler(svr, rd)
 	apiRouter.PathPrefix("/scheduler-config").Handler(schedulerConfigHandler)
 
 	clusterHandler := newClusterHandler(svr, rd)
 	apiRouter.Handle("/cluster", clusterHandler).Methods("GET")
 	apiRouter.HandleFunc("/cluster/status", clusterHandler.GetClusterStatus).Methods("GET")
+	clusterRouter.HandleFunc("/cluster/topology", clusterHandler.GetTopology).Methods("GET")
 
 	confHandler := newConfHandler(svr, rd)
 	apiRouter.HandleFunc("/config", confHandler.Get).Methods("GET")
@@ -97,17 +103,22 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 	clusterRouter.HandleFunc("/config/rules", rulesHandler.GetAll).Methods("GET")
 	clusterRouter.HandleFunc("/config/rules", rulesHandler.SetAll).Methods("POST")
 	clusterRouter.HandleFunc("/config/rules/batch", rulesHandler.Batch).Methods("POST")
+	clusterRouter.HandleFunc("/config/rules/validate", rulesHandler.Validate).Methods("POST")
+	clusterRouter.HandleFunc("/config/rules/export", rulesHandler.Export).Methods("GET")
+	clusterRouter.HandleFunc("/config/rules/import", rulesHandler.Import).Methods("POST")
 	clusterRouter.HandleFunc("/config/rules/group/{group}", rulesHandler.GetAllByGroup).Methods("GET")
 	clusterRouter.HandleFunc("/config/rules/region/{region}", rulesHandler.GetAllByRegion).Methods("GET")
 	clusterRouter.HandleFunc("/config/rules/key/{key}", rulesHandler.GetAllByKey).Methods("GET")
 	clusterRouter.HandleFunc("/config/rule/{group}/{id}", rulesHandler.Get).Methods("GET")
 	clusterRouter.HandleFunc("/config/rule", rulesHandler.Set).Methods("POST")
 	clusterRouter.HandleFunc("/config/rule/{group}/{id}", rulesHandler.Delete).Methods("DELETE")
+	clusterRouter.HandleFunc("/checker/rule/simulate", rulesHandler.Simulate).Methods("GET")
 
 	clusterRouter.HandleFunc("/config/rule_group/{id}", rulesHandler.GetGroupConfig).Methods("GET")
 	clusterRouter.HandleFunc("/config/rule_group", rulesHandler.SetGroupConfig).Methods("POST")
 	clusterRouter.HandleFunc("/config/rule_group/{id}", rulesHandler.DeleteGroupConfig).Methods("DELETE")
 	clusterRouter.HandleFunc("/config/rule_groups", rulesHandler.GetAllGroupConfigs).Methods("GET")
+	clusterRouter.HandleFunc("/config/rule_groups/validate", rulesHandler.ValidateGroupBundles).Methods("POST")
 
 	clusterRouter.HandleFunc("/config/placement-rule", rulesHandler.GetAllGroupBundles).Methods("GET")
 	clusterRouter.HandleFunc("/config/placement-rule", rulesHandler.SetAllGroupBundles).Methods("POST")
@@ -122,6 +133,9 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 	clusterRouter.HandleFunc("/store/{id}", storeHandler.Get).Methods("GET")
 	clusterRouter.HandleFunc("/store/{id}", storeHandler.Delete).Methods("DELETE")
 	clusterRouter.HandleFunc("/store/{id}/state", storeHandler.SetState).Methods("POST")
+	clusterRouter.HandleFunc("/store/{id}/state-history", storeHandler.GetStateHistory).Methods("GET")
+	clusterRouter.HandleFunc("/store/{id}/key-range-distribution", storeHandler.GetKeyRangeDistribution).Methods("GET")
+	clusterRouter.HandleFunc("/store/{id}/forecast", storeHandler.GetCapacityForecast).Methods("GET")
 	clusterRouter.HandleFunc("/store/{id}/label", storeHandler.SetLabels).Methods("POST")
 	clusterRouter.HandleFunc("/store/{id}/weight", storeHandler.SetWeight).Methods("POST")
 	clusterRouter.HandleFunc("/store/{id}/limit", storeHandler.SetLimit).Methods("POST")
@@ -141,6 +155,7 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 	apiRouter.HandleFunc("/hotspot/regions/write", hotStatusHandler.GetHotWriteRegions).Methods("GET")
 	apiRouter.HandleFunc("/hotspot/regions/read", hotStatusHandler.GetHotReadRegions).Methods("GET")
 	apiRouter.HandleFunc("/hotspot/stores", hotStatusHandler.GetHotStores).Methods("GET")
+	apiRouter.HandleFunc("/hotspot/stores/{id}/timeseries", hotStatusHandler.GetStoreHotPeerCountTimeSeries).Methods("GET")
 
 	regionHandler := newRegionHandler(svr, rd)
 	clusterRouter.HandleFunc("/region/id/{id}", regionHandler.GetRegionByID).Methods("GET")
@@ -172,6 +187,7 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 	clusterRouter.HandleFunc("/regions/sibling/{id}", regionsHandler.GetRegionSiblings).Methods("GET")
 	clusterRouter.HandleFunc("/regions/accelerate-schedule", regionsHandler.AccelerateRegionsScheduleInRange).Methods("POST")
 	clusterRouter.HandleFunc("/regions/scatter", regionsHandler.ScatterRegions).Methods("POST")
+	clusterRouter.HandleFunc("/regions/scatter/dryrun", regionsHandler.ScatterRegionsDryRun).Methods("POST")
 	clusterRouter.HandleFunc("/regions/split", regionsHandler.SplitRegions).Methods("POST")
 
 	apiRouter.Handle("/version", newVersionHandler(rd)).Methods("GET")
@@ -190,6 +206,7 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 
 	statsHandler := newStatsHandler(svr, rd)
 	clusterRouter.HandleFunc("/stats/region", statsHandler.Region).Methods("GET")
+	clusterRouter.HandleFunc("/stats/checker", statsHandler.Checker).Methods("GET")
 
 	trendHandler := newTrendHandler(svr, rd)
 	apiRouter.HandleFunc("/trend", trendHandler.Handle).Methods("GET")
@@ -215,6 +232,7 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 	pluginHandler := newPluginHandler(handler, rd)
 	apiRouter.HandleFunc("/plugin", pluginHandler.LoadPlugin).Methods("POST")
 	apiRouter.HandleFunc("/plugin", pluginHandler.UnloadPlugin).Methods("DELETE")
+	apiRouter.HandleFunc("/plugin/load", pluginHandler.LoadRemotePlugin).Methods("POST")
 
 	apiRouter.Handle("/health", newHealthHandler(svr, rd)).Methods("GET")
 	apiRouter.Handle("/diagnose", newDiagnoseHandler(svr, rd)).Methods("GET")
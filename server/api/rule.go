@@ -16,21 +16,29 @@ package api
 import (
 	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
 
+	"github.com/ghodss/yaml"
 	"github.com/gorilla/mux"
 	"github.com/pingcap/errors"
 	"github.com/tikv/pd/pkg/apiutil"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/schedule/operator"
 	"github.com/tikv/pd/server/schedule/placement"
 	"github.com/unrolled/render"
 )
 
 var errPlacementDisabled = errors.New("placement rules feature is disabled")
 
+const (
+	rulesFormatJSON = "json"
+	rulesFormatYAML = "yaml"
+)
+
 type ruleHandler struct {
 	svr *server.Server
 	rd  *render.Render
@@ -90,6 +98,123 @@ func (h *ruleHandler) SetAll(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, "Update rules successfully.")
 }
 
+// @Tags rule
+// @Summary Export all rules of cluster.
+// @Param format query string false "The format of rules, json or yaml" default(json)
+// @Produce json
+// @Produce application/x-yaml
+// @Success 200 {array} placement.Rule
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 412 {string} string "Placement rules feature is disabled."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /config/rules/export [get]
+func (h *ruleHandler) Export(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if !cluster.GetOpts().IsPlacementRulesEnabled() {
+		h.rd.JSON(w, http.StatusPreconditionFailed, errPlacementDisabled.Error())
+		return
+	}
+	rules := cluster.GetRuleManager().GetAllRules()
+	switch format := r.URL.Query().Get("format"); format {
+	case "", rulesFormatJSON:
+		h.rd.JSON(w, http.StatusOK, rules)
+	case rulesFormatYAML:
+		data, err := yaml.Marshal(rules)
+		if err != nil {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-yaml")
+		h.rd.Data(w, http.StatusOK, data)
+	default:
+		h.rd.JSON(w, http.StatusBadRequest, fmt.Sprintf("unsupported format %s", format))
+	}
+}
+
+// @Tags rule
+// @Summary Replace all rules of cluster with the given ones. The rules which are not given are deleted. Either all rules are replaced or nothing is changed in memory.
+// @Param format query string false "The format of rules, json or yaml" default(json)
+// @Param rules body []placement.Rule true "Parameters of rules"
+// @Produce json
+// @Success 200 {string} string "Import rules successfully."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 412 {string} string "Placement rules feature is disabled."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /config/rules/import [post]
+func (h *ruleHandler) Import(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if !cluster.GetOpts().IsPlacementRulesEnabled() {
+		h.rd.JSON(w, http.StatusPreconditionFailed, errPlacementDisabled.Error())
+		return
+	}
+	var rules []*placement.Rule
+	switch format := r.URL.Query().Get("format"); format {
+	case "", rulesFormatJSON:
+		if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &rules); err != nil {
+			return
+		}
+	case rulesFormatYAML:
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if err := yaml.Unmarshal(data, &rules); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	default:
+		h.rd.JSON(w, http.StatusBadRequest, fmt.Sprintf("unsupported format %s", format))
+		return
+	}
+	if err := cluster.GetRuleManager().SetKeyType(h.svr.GetConfig().PDServerCfg.KeyType).
+		ReplaceRules(rules); err != nil {
+		if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) || errs.ErrBuildRuleList.Equal(err) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "Import rules successfully.")
+}
+
+// @Tags rule
+// @Summary Check the conflicts of the label constraints of rules merged into the current rules, without applying them.
+// @Accept json
+// @Param rules body []placement.Rule true "Parameters of rules"
+// @Produce json
+// @Success 200 {array} placement.RuleConflict
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 412 {string} string "Placement rules feature is disabled."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /config/rules/validate [post]
+func (h *ruleHandler) Validate(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if !cluster.GetOpts().IsPlacementRulesEnabled() {
+		h.rd.JSON(w, http.StatusPreconditionFailed, errPlacementDisabled.Error())
+		return
+	}
+	var rules []*placement.Rule
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &rules); err != nil {
+		return
+	}
+	conflicts, err := cluster.GetRuleManager().SetKeyType(h.svr.GetConfig().PDServerCfg.KeyType).
+		CheckRuleConflicts(rules, cluster.GetStores())
+	if err != nil {
+		if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	if conflicts == nil {
+		conflicts = []placement.RuleConflict{}
+	}
+	h.rd.JSON(w, http.StatusOK, conflicts)
+}
+
 // @Tags rule
 // @Summary List all rules of cluster by group.
 // @Param group path string true "The name of group"
@@ -138,6 +263,45 @@ func (h *ruleHandler) GetAllByRegion(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, rules)
 }
 
+// ruleSimulation is the result of simulating the rule checker on a region.
+type ruleSimulation struct {
+	Operators []*operator.Operator `json:"operators"`
+	Fit       *placement.RegionFit `json:"fit"`
+}
+
+// @Tags rule
+// @Summary Simulate the rule checker on a region without creating any operator.
+// @Param region_id query integer true "The id of region"
+// @Produce json
+// @Success 200 {object} ruleSimulation
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The region does not exist."
+// @Failure 412 {string} string "Placement rules feature is disabled."
+// @Router /checker/rule/simulate [get]
+func (h *ruleHandler) Simulate(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if !cluster.GetOpts().IsPlacementRulesEnabled() {
+		h.rd.JSON(w, http.StatusPreconditionFailed, errPlacementDisabled.Error())
+		return
+	}
+	regionID, err := strconv.ParseUint(r.URL.Query().Get("region_id"), 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid region id")
+		return
+	}
+	region := cluster.GetRegion(regionID)
+	if region == nil {
+		h.rd.JSON(w, http.StatusNotFound, server.ErrRegionNotFound(regionID).Error())
+		return
+	}
+	ops, fit, err := cluster.GetRuleChecker().Simulate(region)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, &ruleSimulation{Operators: ops, Fit: fit})
+}
+
 // @Tags rule
 // @Summary List all rules of cluster by key.
 // @Param key path string true "The name of key"
@@ -425,6 +589,40 @@ func (h *ruleHandler) SetAllGroupBundles(w http.ResponseWriter, r *http.Request)
 	h.rd.JSON(w, http.StatusOK, "Update rules and groups successfully.")
 }
 
+// ValidationResult is the result of validating the rules and groups configuration.
+type ValidationResult struct {
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// @Tags rule
+// @Summary Validate all rules and groups configuration without applying it.
+// @Accept json
+// @Param body body []placement.GroupBundle true "Parameters of all rules and groups"
+// @Produce json
+// @Success 200 {object} ValidationResult
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 412 {string} string "Placement rules feature is disabled."
+// @Router /config/rule_groups/validate [post]
+func (h *ruleHandler) ValidateGroupBundles(w http.ResponseWriter, r *http.Request) {
+	cluster := h.svr.GetRaftCluster()
+	if !cluster.GetOpts().IsPlacementRulesEnabled() {
+		h.rd.JSON(w, http.StatusPreconditionFailed, errPlacementDisabled.Error())
+		return
+	}
+	var groups []placement.GroupBundle
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &groups); err != nil {
+		return
+	}
+	problems := cluster.GetRuleManager().SetKeyType(h.svr.GetConfig().PDServerCfg.KeyType).
+		ValidateGroupBundles(groups)
+	result := ValidationResult{Valid: len(problems) == 0}
+	for _, err := range problems {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	h.rd.JSON(w, http.StatusOK, result)
+}
+
 // @Tags rule
 // @Summary Get group config and all rules belong to the group.
 // @Param group path string true "The name of group"
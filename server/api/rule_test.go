@@ -14,9 +14,11 @@
 package api
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
@@ -297,6 +299,113 @@ func (s *testRuleSuite) TestSetAll(c *C) {
 	}
 }
 
+func (s *testRuleSuite) TestValidate(c *C) {
+	def := placement.Rule{GroupID: "pd", ID: "default", StartKeyHex: "5555", Role: "voter", Count: 3}
+	rule1 := placement.Rule{GroupID: "a", ID: "1", StartKeyHex: "1111", EndKeyHex: "3333", Role: "voter", Count: 1}
+	rule2 := placement.Rule{GroupID: "b", ID: "2", StartKeyHex: "2222", EndKeyHex: "4444", Role: "voter", Count: 1}
+	rule3 := placement.Rule{GroupID: "b", ID: "3", StartKeyHex: "3333", EndKeyHex: "4444", Role: "voter", Count: 1}
+	rule4 := placement.Rule{GroupID: "a", ID: "4", StartKeyHex: "XXXX", EndKeyHex: "3333", Role: "voter", Count: 1}
+	for _, rule := range []placement.Rule{def, rule1} {
+		data, err := json.Marshal(rule)
+		c.Assert(err, IsNil)
+		c.Assert(postJSON(testDialClient, s.urlPrefix+"/rule", data), IsNil)
+	}
+
+	// there is only one store, so the new rule conflicts with the overlapped rule saved before.
+	data, err := json.Marshal([]*placement.Rule{&rule2})
+	c.Assert(err, IsNil)
+	var conflicts []placement.RuleConflict
+	err = postJSON(testDialClient, s.urlPrefix+"/rules/validate", data, func(res []byte, code int) {
+		c.Assert(json.Unmarshal(res, &conflicts), IsNil)
+	})
+	c.Assert(err, IsNil)
+	c.Assert(conflicts, HasLen, 1)
+	c.Assert(conflicts[0].Rules[0].ID, Equals, "1")
+	c.Assert(conflicts[0].Rules[1].ID, Equals, "2")
+	c.Assert(conflicts[0].EligibleStores, Equals, 1)
+	c.Assert(conflicts[0].RequiredStores, Equals, 2)
+	// the rules are not saved.
+	c.Assert(s.svr.GetRaftCluster().GetRuleManager().GetRule("b", "2"), IsNil)
+
+	data, err = json.Marshal([]*placement.Rule{&rule3})
+	c.Assert(err, IsNil)
+	err = postJSON(testDialClient, s.urlPrefix+"/rules/validate", data, func(res []byte, code int) {
+		c.Assert(json.Unmarshal(res, &conflicts), IsNil)
+	})
+	c.Assert(err, IsNil)
+	c.Assert(conflicts, HasLen, 0)
+
+	// the rule in the batch replaces the saved one.
+	rule1.EndKeyHex = "2222"
+	data, err = json.Marshal([]*placement.Rule{&rule1, &rule2})
+	c.Assert(err, IsNil)
+	err = postJSON(testDialClient, s.urlPrefix+"/rules/validate", data, func(res []byte, code int) {
+		c.Assert(json.Unmarshal(res, &conflicts), IsNil)
+	})
+	c.Assert(err, IsNil)
+	c.Assert(conflicts, HasLen, 0)
+
+	data, err = json.Marshal([]*placement.Rule{&rule4})
+	c.Assert(err, IsNil)
+	err = postJSON(testDialClient, s.urlPrefix+"/rules/validate", data)
+	c.Assert(err, NotNil)
+}
+
+func (s *testRuleSuite) TestExportImport(c *C) {
+	var rules []*placement.Rule
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/rules/export", &rules), IsNil)
+	c.Assert(rules, HasLen, 1)
+	c.Assert(rules[0].Key(), Equals, [2]string{"pd", "default"})
+
+	export := func() string {
+		resp, err := testDialClient.Get(s.urlPrefix + "/rules/export?format=yaml")
+		c.Assert(err, IsNil)
+		defer resp.Body.Close()
+		c.Assert(resp.StatusCode, Equals, http.StatusOK)
+		c.Assert(resp.Header.Get("Content-Type"), Equals, "application/x-yaml")
+		data, err := ioutil.ReadAll(resp.Body)
+		c.Assert(err, IsNil)
+		return string(data)
+	}
+	c.Assert(export(), Matches, "(?s).*group_id: pd.*id: default.*")
+
+	// the rules which are not imported are deleted.
+	data := `
+- group_id: foo
+  id: "1"
+  role: voter
+  count: 3
+- group_id: foo
+  id: "2"
+  start_key: "1111"
+  end_key: "2222"
+  role: learner
+  count: 1
+`
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/rules/import?format=yaml", []byte(data)), IsNil)
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/rules/export", &rules), IsNil)
+	c.Assert(rules, HasLen, 2)
+	c.Assert(rules[0].Key(), Equals, [2]string{"foo", "1"})
+	c.Assert(rules[1].Key(), Equals, [2]string{"foo", "2"})
+	c.Assert(rules[1].Role, Equals, placement.Learner)
+	c.Assert(export(), Matches, "(?s).*group_id: foo.*")
+
+	// the exported rules can be imported again.
+	rules[1].Count = 2
+	jsonData, err := json.Marshal(rules)
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/rules/import", jsonData), IsNil)
+	c.Assert(s.svr.GetRaftCluster().GetRuleManager().GetRule("foo", "2").Count, Equals, 2)
+
+	// nothing is changed if some range has no rules.
+	data = `[{"group_id": "foo", "id": "2", "start_key": "1111", "end_key": "2222", "role": "voter", "count": 1}]`
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/rules/import", []byte(data)), NotNil)
+	c.Assert(s.svr.GetRaftCluster().GetRuleManager().GetAllRules(), HasLen, 2)
+
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/rules/import?format=xml", jsonData), NotNil)
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/rules/export?format=xml", &rules), NotNil)
+}
+
 func (s *testRuleSuite) TestGetAllByGroup(c *C) {
 	rule := placement.Rule{GroupID: "c", ID: "20", StartKeyHex: "1111", EndKeyHex: "3333", Role: "voter", Count: 1}
 	data, err := json.Marshal(rule)
@@ -394,6 +503,26 @@ func (s *testRuleSuite) TestGetAllByRegion(c *C) {
 	}
 }
 
+func (s *testRuleSuite) TestSimulate(c *C) {
+	r := newTestRegionInfo(6, 1, []byte{0x44, 0x44}, []byte{0x55, 0x55})
+	mustRegionHeartbeat(c, s.svr, r)
+
+	url := fmt.Sprintf("%s%s/api/v1/checker/rule/simulate?region_id=6", s.svr.GetAddr(), apiPrefix)
+	var resp ruleSimulation
+	c.Assert(readJSON(testDialClient, url, &resp), IsNil)
+	c.Assert(resp.Fit, NotNil)
+	c.Assert(resp.Fit.RuleFits, HasLen, 1)
+	// there is only one store to place the peers.
+	c.Assert(resp.Operators, HasLen, 0)
+
+	url = fmt.Sprintf("%s%s/api/v1/checker/rule/simulate?region_id=abc", s.svr.GetAddr(), apiPrefix)
+	err := readJSON(testDialClient, url, &resp)
+	c.Assert(strings.HasSuffix(err.Error(), "400"), IsTrue)
+	url = fmt.Sprintf("%s%s/api/v1/checker/rule/simulate?region_id=100", s.svr.GetAddr(), apiPrefix)
+	err = readJSON(testDialClient, url, &resp)
+	c.Assert(strings.HasSuffix(err.Error(), "404"), IsTrue)
+}
+
 func (s *testRuleSuite) TestGetAllByKey(c *C) {
 	rule := placement.Rule{GroupID: "f", ID: "40", StartKeyHex: "8888", EndKeyHex: "9111", Role: "voter", Count: 1}
 	data, err := json.Marshal(rule)
@@ -761,6 +890,30 @@ func (s *testRuleSuite) TestBundle(c *C) {
 
 }
 
+func (s *testRuleSuite) TestValidateBundles(c *C) {
+	validate := func(data string) ValidationResult {
+		resp, err := testDialClient.Post(s.urlPrefix+"/rule_groups/validate", "application/json", bytes.NewBufferString(data))
+		c.Assert(err, IsNil)
+		defer resp.Body.Close()
+		c.Assert(resp.StatusCode, Equals, http.StatusOK)
+		var result ValidationResult
+		c.Assert(json.NewDecoder(resp.Body).Decode(&result), IsNil)
+		return result
+	}
+	result := validate(`[{"group_id":"foo", "rules": [{"group_id":"foo", "id":"baz", "role":"voter", "count":1}]}]`)
+	c.Assert(result.Valid, IsTrue)
+	c.Assert(result.Errors, HasLen, 0)
+	result = validate(`[{"group_id":"foo", "rules": [{"group_id":"bar", "id":"baz", "role":"voter", "count":1}]}, {"group_id":"foo"}]`)
+	c.Assert(result.Valid, IsFalse)
+	c.Assert(result.Errors, HasLen, 2)
+
+	// the rules are not changed.
+	var bundles []placement.GroupBundle
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/placement-rule", &bundles), IsNil)
+	c.Assert(bundles, HasLen, 1)
+	c.Assert(bundles[0].ID, Equals, "pd")
+}
+
 func (s *testRuleSuite) TestBundleBadRequest(c *C) {
 	testCases := []struct {
 		uri  string
@@ -16,6 +16,7 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
 
@@ -93,6 +94,21 @@ func (h *schedulerHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// @Tags scheduler
+// @Summary Get the statistics of all schedulers.
+// @Produce json
+// @Success 200 {object} map[string]cluster.SchedulerStats
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /schedulers/stats [get]
+func (h *schedulerHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.GetSchedulerStats()
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, stats)
+}
+
 // FIXME: details of input json body params
 // @Tags scheduler
 // @Summary Create a scheduler.
@@ -118,22 +134,22 @@ func (h *schedulerHandler) Post(w http.ResponseWriter, r *http.Request) {
 	switch name {
 	case schedulers.BalanceLeaderName:
 		if err := h.AddBalanceLeaderScheduler(); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.handleErr(w, err)
 			return
 		}
 	case schedulers.HotRegionName:
 		if err := h.AddBalanceHotRegionScheduler(); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.handleErr(w, err)
 			return
 		}
 	case schedulers.BalanceRegionName:
 		if err := h.AddBalanceRegionScheduler(); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.handleErr(w, err)
 			return
 		}
 	case schedulers.LabelName:
 		if err := h.AddLabelScheduler(); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.handleErr(w, err)
 			return
 		}
 	case schedulers.ScatterRangeName:
@@ -157,7 +173,7 @@ func (h *schedulerHandler) Post(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if err := h.AddScatterRangeScheduler(args...); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.handleErr(w, err)
 			return
 		}
 
@@ -175,7 +191,7 @@ func (h *schedulerHandler) Post(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 		if err != nil && !errors.ErrorEqual(err, errs.ErrSchedulerExisted.FastGenByArgs()) {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.handleErr(w, err)
 			return
 		}
 	case schedulers.EvictLeaderName:
@@ -192,22 +208,48 @@ func (h *schedulerHandler) Post(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 		if err != nil && !errors.ErrorEqual(err, errs.ErrSchedulerExisted.FastGenByArgs()) {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.handleErr(w, err)
 			return
 		}
 	case schedulers.ShuffleLeaderName:
 		if err := h.AddShuffleLeaderScheduler(); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.handleErr(w, err)
 			return
 		}
 	case schedulers.ShuffleRegionName:
 		if err := h.AddShuffleRegionScheduler(); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.handleErr(w, err)
 			return
 		}
 	case schedulers.RandomMergeName:
 		if err := h.AddRandomMergeScheduler(); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.handleErr(w, err)
+			return
+		}
+	case schedulers.RegionGroupSizeBalancerName:
+		// group_ranges is a list of {"start_key": "", "end_key": ""}, one for each group.
+		groups, ok := input["group_ranges"].([]interface{})
+		if !ok || len(groups) == 0 {
+			h.r.JSON(w, http.StatusBadRequest, "missing group ranges")
+			return
+		}
+		var args []string
+		for _, g := range groups {
+			group, ok := g.(map[string]interface{})
+			if !ok {
+				h.r.JSON(w, http.StatusBadRequest, "bad format group ranges")
+				return
+			}
+			startKey, ok1 := group["start_key"].(string)
+			endKey, ok2 := group["end_key"].(string)
+			if !ok1 || !ok2 {
+				h.r.JSON(w, http.StatusBadRequest, "missing the start key or end key of a group")
+				return
+			}
+			args = append(args, startKey, endKey)
+		}
+		if err := h.AddRegionGroupSizeBalancer(args...); err != nil {
+			h.handleErr(w, err)
 			return
 		}
 	case schedulers.ShuffleHotRegionName:
@@ -217,7 +259,7 @@ func (h *schedulerHandler) Post(w http.ResponseWriter, r *http.Request) {
 			limit = uint64(l)
 		}
 		if err := h.AddShuffleHotRegionScheduler(limit); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.handleErr(w, err)
 			return
 		}
 	default:
@@ -273,6 +315,8 @@ func (h *schedulerHandler) Delete(w http.ResponseWriter, r *http.Request) {
 func (h *schedulerHandler) handleErr(w http.ResponseWriter, err error) {
 	if errors.ErrorEqual(err, errs.ErrSchedulerNotFound.FastGenByArgs()) {
 		h.r.JSON(w, http.StatusNotFound, err.Error())
+	} else if errors.ErrorEqual(err, errs.ErrSchedulerInvalid.FastGenByArgs()) {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
 	} else {
 		h.r.JSON(w, http.StatusInternalServerError, err.Error())
 	}
@@ -292,6 +336,37 @@ func (h *schedulerHandler) redirectSchedulerDelete(name, schedulerName string) e
 	return nil
 }
 
+// SchedulerPauseStatus is the pause status of a scheduler.
+type SchedulerPauseStatus struct {
+	Name                  string `json:"name"`
+	Paused                bool   `json:"paused"`
+	PauseRemainingSeconds int64  `json:"pause_remaining_seconds"`
+}
+
+// @Tags scheduler
+// @Summary Get the pause status of a scheduler.
+// @Param name path string true "The name of the scheduler."
+// @Produce json
+// @Success 200 {object} SchedulerPauseStatus
+// @Failure 404 {string} string "The scheduler is not found."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /schedulers/{name} [get]
+func (h *schedulerHandler) Get(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	remaining, err := h.GetSchedulerPauseTimeRemaining(name)
+	if err != nil {
+		h.handleErr(w, err)
+		return
+	}
+	// round up, so a paused scheduler never shows 0 seconds remaining.
+	seconds := int64(math.Ceil(remaining.Seconds()))
+	h.r.JSON(w, http.StatusOK, SchedulerPauseStatus{
+		Name:                  name,
+		Paused:                seconds > 0,
+		PauseRemainingSeconds: seconds,
+	})
+}
+
 // FIXME: details of input json body params
 // @Tags scheduler
 // @Summary Pause or resume a scheduler.
@@ -21,6 +21,7 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/cluster"
 	"github.com/tikv/pd/server/config"
 	_ "github.com/tikv/pd/server/schedulers"
 )
@@ -143,6 +144,13 @@ func (s *testScheduleSuite) TestAPI(c *C) {
 		{name: "balance-region-scheduler"},
 		{name: "shuffle-leader-scheduler"},
 		{name: "shuffle-region-scheduler"},
+		{
+			name: "region-group-size-balancer",
+			args: []arg{{"group_ranges", []interface{}{
+				map[string]interface{}{"start_key": "", "end_key": "m"},
+				map[string]interface{}{"start_key": "m", "end_key": ""},
+			}}},
+		},
 		{
 			name:        "grant-leader-scheduler",
 			createdName: "grant-leader-scheduler",
@@ -378,6 +386,19 @@ func (s *testScheduleSuite) TestDisable(c *C) {
 	s.deleteScheduler(name, c)
 }
 
+func (s *testScheduleSuite) TestStats(c *C) {
+	body, err := json.Marshal(map[string]interface{}{"name": "balance-region-scheduler"})
+	c.Assert(err, IsNil)
+	s.addScheduler("balance-region-scheduler", "", body, nil, c)
+	defer s.deleteScheduler("balance-region-scheduler", c)
+
+	var stats map[string]cluster.SchedulerStats
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/stats", &stats), IsNil)
+	c.Assert(stats, HasKey, "balance-region-scheduler")
+	stat := stats["balance-region-scheduler"]
+	c.Assert(stat.NoOpCount <= stat.RunCount, IsTrue)
+}
+
 func (s *testScheduleSuite) addScheduler(name, createdName string, body []byte, extraTest func(string, *C), c *C) {
 	if createdName == "" {
 		createdName = name
@@ -417,6 +438,12 @@ func (s *testScheduleSuite) testPauseOrResume(name, createdName string, body []b
 	isPaused, err := handler.IsSchedulerPaused(createdName)
 	c.Assert(err, IsNil)
 	c.Assert(isPaused, Equals, true)
+	var status SchedulerPauseStatus
+	err = readJSON(testDialClient, s.urlPrefix+"/"+createdName, &status)
+	c.Assert(err, IsNil)
+	c.Assert(status.Paused, IsTrue)
+	c.Assert(status.PauseRemainingSeconds, Greater, int64(0))
+	c.Assert(status.PauseRemainingSeconds <= 30, IsTrue)
 	input["delay"] = 1
 	pauseArgs, err = json.Marshal(input)
 	c.Assert(err, IsNil)
@@ -442,6 +469,10 @@ func (s *testScheduleSuite) testPauseOrResume(name, createdName string, body []b
 	isPaused, err = handler.IsSchedulerPaused(createdName)
 	c.Assert(err, IsNil)
 	c.Assert(isPaused, Equals, false)
+	status = SchedulerPauseStatus{}
+	err = readJSON(testDialClient, s.urlPrefix+"/"+createdName, &status)
+	c.Assert(err, IsNil)
+	c.Assert(status, DeepEquals, SchedulerPauseStatus{Name: createdName})
 
 	if extraTest != nil {
 		extraTest(createdName, c)
@@ -45,3 +45,19 @@ func (h *statsHandler) Region(w http.ResponseWriter, r *http.Request) {
 	stats := rc.GetRegionStats([]byte(startKey), []byte(endKey))
 	h.rd.JSON(w, http.StatusOK, stats)
 }
+
+// checkerStats is the statistics of the checkers.
+type checkerStats struct {
+	// HitRate is the fraction of the latest checks which result in an operator of each checker.
+	HitRate map[string]float64 `json:"hit_rate"`
+}
+
+// @Tags stats
+// @Summary Get the statistics of the checkers.
+// @Produce json
+// @Success 200 {object} checkerStats
+// @Router /stats/checker [get]
+func (h *statsHandler) Checker(w http.ResponseWriter, r *http.Request) {
+	rc := h.svr.GetRaftCluster()
+	h.rd.JSON(w, http.StatusOK, &checkerStats{HitRate: rc.GetCheckerHitRate()})
+}
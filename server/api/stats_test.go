@@ -175,3 +175,10 @@ func (s *testStatsSuite) TestRegionStats(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(stats, DeepEquals, stats23)
 }
+
+func (s *testStatsSuite) TestCheckerStats(c *C) {
+	var stats checkerStats
+	err := readJSON(testDialClient, s.urlPrefix+"/stats/checker", &stats)
+	c.Assert(err, IsNil)
+	c.Assert(stats.HitRate, NotNil)
+}
@@ -14,9 +14,12 @@
 package api
 
 import (
+	"bytes"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -29,6 +32,7 @@ import (
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/cluster"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/core/storelimit"
@@ -167,12 +171,218 @@ func (h *storeHandler) Get(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, storeInfo)
 }
 
+// StoreStateEvent is a state transition of a store.
+type StoreStateEvent struct {
+	PreviousState string    `json:"previous_state"`
+	NewState      string    `json:"new_state"`
+	At            time.Time `json:"at"`
+	Reason        string    `json:"reason"`
+}
+
+// @Tags store
+// @Summary Get the recent state transitions of a store, the oldest first.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {array} StoreStateEvent
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Router /store/{id}/state-history [get]
+func (h *storeHandler) GetStateHistory(w http.ResponseWriter, r *http.Request) {
+	rc, _ := h.GetRaftCluster()
+	vars := mux.Vars(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(vars, "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	store := rc.GetStore(storeID)
+	if store == nil {
+		h.rd.JSON(w, http.StatusNotFound, server.ErrStoreNotFound(storeID).Error())
+		return
+	}
+
+	history := store.GetStateHistory()
+	events := make([]StoreStateEvent, 0, len(history))
+	for _, e := range history {
+		events = append(events, StoreStateEvent{
+			PreviousState: e.PreviousState.String(),
+			NewState:      e.NewState.String(),
+			At:            e.At,
+			Reason:        e.Reason,
+		})
+	}
+	h.rd.JSON(w, http.StatusOK, events)
+}
+
+const defaultForecastWindow = 7 * 24 * time.Hour
+
+// @Tags store
+// @Summary Forecast when the store will be full according to the growth of its used size.
+// @Param id path integer true "Store Id"
+// @Param window query string false "The duration of the samples to use, 168h by default"
+// @Produce json
+// @Success 200 {object} statistics.CapacityForecast
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist or there are not enough samples."
+// @Router /store/{id}/forecast [get]
+func (h *storeHandler) GetCapacityForecast(w http.ResponseWriter, r *http.Request) {
+	rc, _ := h.GetRaftCluster()
+	vars := mux.Vars(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(vars, "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	window := defaultForecastWindow
+	if v := r.URL.Query().Get("window"); v != "" {
+		var err error
+		if window, err = time.ParseDuration(v); err != nil || window <= 0 {
+			h.rd.JSON(w, http.StatusBadRequest, "invalid window")
+			return
+		}
+	}
+
+	if rc.GetStore(storeID) == nil {
+		h.rd.JSON(w, http.StatusNotFound, server.ErrStoreNotFound(storeID).Error())
+		return
+	}
+	forecast := rc.GetStoresStats().StoreCapacityForecast(storeID, window)
+	if forecast == nil {
+		h.rd.JSON(w, http.StatusNotFound, "not enough samples to forecast")
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, forecast)
+}
+
+const (
+	defaultKeyRangeBuckets = 10
+	maxKeyRangeBuckets     = 1000
+)
+
+// KeyRangeBucket is a segment of the key space and the regions of a store in it.
+type KeyRangeBucket struct {
+	StartKey    string `json:"start_key"`
+	EndKey      string `json:"end_key"`
+	RegionCount int    `json:"region_count"`
+	TotalBytes  int64  `json:"total_bytes"`
+}
+
+// @Tags store
+// @Summary Get the distribution of the regions of a store over the key space.
+// @Param id path integer true "Store Id"
+// @Param buckets query integer false "The number of equal-width segments the key range of the store is divided into" default(10)
+// @Produce json
+// @Success 200 {array} KeyRangeBucket
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Router /store/{id}/key-range-distribution [get]
+func (h *storeHandler) GetKeyRangeDistribution(w http.ResponseWriter, r *http.Request) {
+	rc, _ := h.GetRaftCluster()
+	vars := mux.Vars(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(vars, "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	buckets := defaultKeyRangeBuckets
+	if bucketsStr := r.URL.Query().Get("buckets"); bucketsStr != "" {
+		var err error
+		buckets, err = strconv.Atoi(bucketsStr)
+		if err != nil || buckets <= 0 {
+			h.rd.JSON(w, http.StatusBadRequest, "invalid buckets")
+			return
+		}
+	}
+	if buckets > maxKeyRangeBuckets {
+		buckets = maxKeyRangeBuckets
+	}
+
+	if rc.GetStore(storeID) == nil {
+		h.rd.JSON(w, http.StatusNotFound, server.ErrStoreNotFound(storeID).Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, keyRangeDistribution(rc.GetStoreRegions(storeID), buckets))
+}
+
+// keyRangeDistribution divides the key range covered by the regions into
+// equal-width segments and counts the regions by their start keys. Keys are
+// treated as big-endian integers padded to the same length.
+func keyRangeDistribution(regions []*core.RegionInfo, buckets int) []KeyRangeBucket {
+	if len(regions) == 0 {
+		return []KeyRangeBucket{}
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		return bytes.Compare(regions[i].GetStartKey(), regions[j].GetStartKey()) < 0
+	})
+	startKey, endKey := regions[0].GetStartKey(), regions[len(regions)-1].GetEndKey()
+	keyLen := len(startKey)
+	for _, region := range regions {
+		if l := len(region.GetStartKey()); l > keyLen {
+			keyLen = l
+		}
+	}
+	if len(endKey) > keyLen {
+		keyLen = len(endKey)
+	}
+	toInt := func(key []byte) *big.Int {
+		padded := make([]byte, keyLen)
+		copy(padded, key)
+		return new(big.Int).SetBytes(padded)
+	}
+	toKey := func(n *big.Int) []byte {
+		b := n.Bytes()
+		key := make([]byte, keyLen)
+		copy(key[keyLen-len(b):], b)
+		return bytes.TrimRight(key, "\x00")
+	}
+	lo, hi := toInt(startKey), toInt(endKey)
+	if len(endKey) == 0 {
+		// the end of the key space.
+		hi = new(big.Int).Lsh(big.NewInt(1), uint(8*keyLen))
+	}
+	// width = ceil((hi - lo) / buckets)
+	width := new(big.Int).Sub(hi, lo)
+	width.Add(width, big.NewInt(int64(buckets-1)))
+	width.Div(width, big.NewInt(int64(buckets)))
+	if width.Sign() == 0 {
+		width.SetInt64(1)
+	}
+
+	bounds := make([]string, buckets+1)
+	bounds[0], bounds[buckets] = core.HexRegionKeyStr(startKey), core.HexRegionKeyStr(endKey)
+	for i := 1; i < buckets; i++ {
+		bound := new(big.Int).Mul(width, big.NewInt(int64(i)))
+		if bound.Add(bound, lo).Cmp(hi) >= 0 {
+			bounds[i] = bounds[buckets]
+			continue
+		}
+		bounds[i] = core.HexRegionKeyStr(toKey(bound))
+	}
+	result := make([]KeyRangeBucket, 0, buckets)
+	for i := 0; i < buckets; i++ {
+		result = append(result, KeyRangeBucket{StartKey: bounds[i], EndKey: bounds[i+1]})
+	}
+	for _, region := range regions {
+		offset := new(big.Int).Sub(toInt(region.GetStartKey()), lo)
+		i := int(offset.Div(offset, width).Int64())
+		if i >= buckets {
+			i = buckets - 1
+		}
+		result[i].RegionCount++
+		result[i].TotalBytes += region.GetApproximateSize() << 20
+	}
+	return result
+}
+
 // @Tags store
 // @Summary Take down a store from the cluster.
 // @Param id path integer true "Store Id"
 // @Param force query string true "force" Enums(true, false), when force is true it means the store is physically destroyed and can never up gain
+// @Param token query string false "The confirmation token returned by the previous request"
 // @Produce json
 // @Success 200 {string} string "The store is set as Offline."
+// @Success 202 {object} StoreRemovalConfirmation "The removal needs to be confirmed with the token."
 // @Failure 400 {string} string "The input is invalid."
 // @Failure 404 {string} string "The store does not exist."
 // @Failure 410 {string} string "The store has already been removed."
@@ -188,22 +398,45 @@ func (h *storeHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	_, force := r.URL.Query()["force"]
-	err := rc.RemoveStore(storeID, force)
+	token, err := removeStore(rc, r, storeID, force)
 
 	if err != nil {
 		h.responseStoreErr(w, err, storeID)
 		return
 	}
+	if token != "" {
+		h.rd.JSON(w, http.StatusAccepted, StoreRemovalConfirmation{ConfirmationToken: token})
+		return
+	}
 
 	h.rd.JSON(w, http.StatusOK, "The store is set as Offline.")
 }
 
+// StoreRemovalConfirmation is returned when removing a store needs to be confirmed.
+type StoreRemovalConfirmation struct {
+	// ConfirmationToken should be passed as the token parameter in another
+	// request to remove the store within store-removal-confirmation-timeout.
+	ConfirmationToken string `json:"confirmation_token"`
+}
+
+// removeStore confirms the store removal if the request carries a token, or requests
+// to remove the store otherwise. The returned token is not empty if the removal needs
+// to be confirmed.
+func removeStore(rc *cluster.RaftCluster, r *http.Request, storeID uint64, force bool) (string, error) {
+	if token := r.URL.Query().Get("token"); token != "" {
+		return "", rc.ConfirmStoreRemoval(storeID, token)
+	}
+	return rc.RequestStoreRemoval(storeID, force)
+}
+
 // @Tags store
 // @Summary Set the store's state.
 // @Param id path integer true "Store Id"
 // @Param state query string true "state" Enums(Up, Offline)
+// @Param token query string false "The confirmation token returned by the previous request to set the store Offline"
 // @Produce json
 // @Success 200 {string} string "The store's state is updated."
+// @Success 202 {object} StoreRemovalConfirmation "Setting the store Offline needs to be confirmed with the token."
 // @Failure 400 {string} string "The input is invalid."
 // @Failure 404 {string} string "The store does not exist."
 // @Failure 500 {string} string "PD server failed to proceed the request."
@@ -218,11 +451,14 @@ func (h *storeHandler) SetState(w http.ResponseWriter, r *http.Request) {
 	}
 
 	stateStr := r.URL.Query().Get("state")
-	var err error
+	var (
+		token string
+		err   error
+	)
 	if strings.EqualFold(stateStr, metapb.StoreState_Up.String()) {
 		err = rc.UpStore(storeID)
 	} else if strings.EqualFold(stateStr, metapb.StoreState_Offline.String()) {
-		err = rc.RemoveStore(storeID, false)
+		token, err = removeStore(rc, r, storeID, false)
 	} else {
 		err = errors.Errorf("invalid state %v", stateStr)
 	}
@@ -231,6 +467,10 @@ func (h *storeHandler) SetState(w http.ResponseWriter, r *http.Request) {
 		h.responseStoreErr(w, err, storeID)
 		return
 	}
+	if token != "" {
+		h.rd.JSON(w, http.StatusAccepted, StoreRemovalConfirmation{ConfirmationToken: token})
+		return
+	}
 
 	h.rd.JSON(w, http.StatusOK, "The store's state is updated.")
 }
@@ -28,9 +28,11 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/statistics"
 )
 
 var _ = Suite(&testStoreSuite{})
@@ -290,12 +292,50 @@ func (s *testStoreSuite) TestStoreDelete(c *C) {
 	s.SetUpSuite(c)
 }
 
+func (s *testStoreSuite) TestStoreDeleteConfirmation(c *C) {
+	cfg := s.svr.GetScheduleConfig()
+	cfg.StoreRemovalConfirmationTimeout = typeutil.NewDuration(time.Minute)
+	c.Assert(s.svr.SetScheduleConfig(*cfg), IsNil)
+
+	url := fmt.Sprintf("%s/store/4", s.urlPrefix)
+	status, body := requestStatusBody(c, testDialClient, http.MethodDelete, url)
+	c.Assert(status, Equals, http.StatusAccepted)
+	var confirmation StoreRemovalConfirmation
+	c.Assert(json.Unmarshal(body, &confirmation), IsNil)
+	c.Assert(confirmation.ConfirmationToken, Not(Equals), "")
+	store := new(StoreInfo)
+	c.Assert(readJSON(testDialClient, url, store), IsNil)
+	c.Assert(store.Store.State, Equals, metapb.StoreState_Up)
+
+	// the removal is not confirmed with a wrong token.
+	status, _ = requestStatusBody(c, testDialClient, http.MethodDelete, url+"?token=foo")
+	c.Assert(status, Equals, http.StatusBadRequest)
+	c.Assert(readJSON(testDialClient, url, store), IsNil)
+	c.Assert(store.Store.State, Equals, metapb.StoreState_Up)
+
+	status, _ = requestStatusBody(c, testDialClient, http.MethodDelete, url+"?token="+confirmation.ConfirmationToken)
+	c.Assert(status, Equals, http.StatusOK)
+	c.Assert(readJSON(testDialClient, url, store), IsNil)
+	c.Assert(store.Store.State, Equals, metapb.StoreState_Offline)
+	// the token can only be used once.
+	status, _ = requestStatusBody(c, testDialClient, http.MethodDelete, url+"?token="+confirmation.ConfirmationToken)
+	c.Assert(status, Equals, http.StatusBadRequest)
+
+	// reset store 4 and the config
+	s.cleanup()
+	s.SetUpSuite(c)
+}
+
 func (s *testStoreSuite) TestStoreSetState(c *C) {
 	url := fmt.Sprintf("%s/store/1", s.urlPrefix)
 	info := StoreInfo{}
 	err := readJSON(testDialClient, url, &info)
 	c.Assert(err, IsNil)
 	c.Assert(info.Store.State, Equals, metapb.StoreState_Up)
+	var history []StoreStateEvent
+	err = readJSON(testDialClient, url+"/state-history", &history)
+	c.Assert(err, IsNil)
+	historyLen := len(history)
 
 	// Set to Offline.
 	info = StoreInfo{}
@@ -328,6 +368,76 @@ func (s *testStoreSuite) TestStoreSetState(c *C) {
 	err = readJSON(testDialClient, url, &info)
 	c.Assert(err, IsNil)
 	c.Assert(info.Store.State, Equals, metapb.StoreState_Up)
+
+	// Only the valid transitions are recorded.
+	history = nil
+	err = readJSON(testDialClient, url+"/state-history", &history)
+	c.Assert(err, IsNil)
+	c.Assert(history, HasLen, historyLen+2)
+	c.Assert(history[historyLen].PreviousState, Equals, metapb.StoreState_Up.String())
+	c.Assert(history[historyLen].NewState, Equals, metapb.StoreState_Offline.String())
+	c.Assert(history[historyLen+1].PreviousState, Equals, metapb.StoreState_Offline.String())
+	c.Assert(history[historyLen+1].NewState, Equals, metapb.StoreState_Up.String())
+	err = readJSON(testDialClient, s.urlPrefix+"/store/10086/state-history", &history)
+	c.Assert(err, NotNil)
+}
+
+func (s *testStoreSuite) TestCapacityForecast(c *C) {
+	url := fmt.Sprintf("%s/store/4/forecast", s.urlPrefix)
+	var forecast statistics.CapacityForecast
+	// not enough samples.
+	c.Assert(readJSON(testDialClient, url, &forecast), NotNil)
+
+	start := time.Now().Add(-time.Hour)
+	stats := s.svr.GetRaftCluster().GetStoresStats()
+	for i := 0; i < 3; i++ {
+		end := uint64(start.Add(time.Duration(i) * 30 * time.Minute).Unix())
+		stats.Observe(4, &pdpb.StoreStats{
+			StoreId:   4,
+			UsedSize:  uint64(i) << 30,
+			Available: 10 << 30,
+			Interval:  &pdpb.TimeInterval{StartTimestamp: end - 10, EndTimestamp: end},
+		})
+	}
+	c.Assert(readJSON(testDialClient, url, &forecast), IsNil)
+	c.Assert(forecast.DailyGrowthBytes, Equals, int64(48<<30))
+	c.Assert(forecast.EstimatedFullAt, NotNil)
+
+	c.Assert(readJSON(testDialClient, url+"?window=abc", &forecast), NotNil)
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/store/10086/forecast", &forecast), NotNil)
+}
+
+func (s *testStoreSuite) TestKeyRangeDistribution(c *C) {
+	keys := [][]byte{[]byte("a"), []byte("b"), []byte("c"), {0xf0}, {}}
+	var regions []*core.RegionInfo
+	for i := len(keys) - 2; i >= 0; i-- {
+		meta := &metapb.Region{Id: uint64(i + 1), StartKey: keys[i], EndKey: keys[i+1]}
+		regions = append(regions, core.NewRegionInfo(meta, nil, core.SetApproximateSize(int64(i+1))))
+	}
+
+	buckets := keyRangeDistribution(regions, 2)
+	c.Assert(buckets, DeepEquals, []KeyRangeBucket{
+		{StartKey: core.HexRegionKeyStr([]byte("a")), EndKey: core.HexRegionKeyStr([]byte{0xb1}), RegionCount: 3, TotalBytes: 6 << 20},
+		{StartKey: core.HexRegionKeyStr([]byte{0xb1}), EndKey: "", RegionCount: 1, TotalBytes: 4 << 20},
+	})
+
+	// the buckets are more than the width of the key range.
+	buckets = keyRangeDistribution(regions, 300)
+	c.Assert(buckets, HasLen, 300)
+	var count int
+	for _, b := range buckets {
+		count += b.RegionCount
+	}
+	c.Assert(count, Equals, 4)
+	c.Assert(buckets[299].StartKey, Equals, "")
+
+	c.Assert(keyRangeDistribution(nil, 2), HasLen, 0)
+	url := fmt.Sprintf("%s/store/1/key-range-distribution", s.urlPrefix)
+	c.Assert(readJSON(testDialClient, url+"?buckets=0", &buckets), NotNil)
+	// the bootstrapped region covers the whole key space.
+	c.Assert(readJSON(testDialClient, url+"?buckets=5", &buckets), IsNil)
+	c.Assert(buckets, HasLen, 5)
+	c.Assert(buckets[0].RegionCount, Equals, 1)
 }
 
 func (s *testStoreSuite) TestUrlStoreFilter(c *C) {
@@ -15,10 +15,13 @@ package cluster
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/coreos/go-semver/semver"
@@ -54,6 +57,10 @@ import (
 
 var backgroundJobInterval = 10 * time.Second
 
+// hotCacheSaveInterval is the interval to save the hot cache, so that the
+// next leader can warm up with a recent one.
+var hotCacheSaveInterval = time.Minute
+
 const (
 	clientTimeout              = 3 * time.Second
 	defaultChangedRegionsLimit = 10000
@@ -61,6 +68,10 @@ const (
 	// since the once the store is add or remove, we shouldn't return an error even if the store limit is failed to persist.
 	persistLimitRetryTimes = 5
 	persistLimitWaitTime   = 100 * time.Millisecond
+	// suspectRegionsSlackRatio is the ratio of the max suspect regions which
+	// can be exceeded before shrinking, so the suspect regions are not sorted
+	// on every heartbeat once the limit is reached.
+	suspectRegionsSlackRatio = 0.1
 )
 
 // Server is the interface for cluster.
@@ -100,6 +111,9 @@ type RaftCluster struct {
 	limiter *StoreLimiter
 
 	prepareChecker *prepareChecker
+	// bootstrapMode is 1 if the cluster is in bootstrap mode. It is updated by
+	// the background jobs, so that it can be read without the cluster lock.
+	bootstrapMode  int32
 	changedRegions chan *core.RegionInfo
 
 	labelLevelStats *statistics.LabelStatistics
@@ -123,6 +137,17 @@ type RaftCluster struct {
 
 	// It's used to manage components.
 	componentManager *component.Manager
+
+	// removalConfirmations records the store removals waiting for confirmation.
+	removalMu            sync.Mutex
+	removalConfirmations map[uint64]*storeRemovalConfirmation
+}
+
+// storeRemovalConfirmation is a store removal requested but not confirmed yet.
+type storeRemovalConfirmation struct {
+	token               string
+	physicallyDestroyed bool
+	deadline            time.Time
 }
 
 // Status saves some state information.
@@ -206,6 +231,7 @@ func (c *RaftCluster) InitCluster(id id.Allocator, opt *config.PersistOptions, s
 	c.id = id
 	c.labelLevelStats = statistics.NewLabelStatistics()
 	c.hotStat = statistics.NewHotStat()
+	c.updateHotStatConfig()
 	c.prepareChecker = newPrepareChecker()
 	c.changedRegions = make(chan *core.RegionInfo, defaultChangedRegionsLimit)
 	c.suspectRegions = cache.NewIDTTL(c.ctx, time.Minute, 3*time.Minute)
@@ -231,6 +257,8 @@ func (c *RaftCluster) Start(s Server) error {
 	if cluster == nil {
 		return nil
 	}
+	c.loadHotCache()
+	c.setBootstrapMode(c.isInBootstrapModeLocked())
 
 	c.ruleManager = placement.NewRuleManager(c.storage, c)
 	if c.opt.IsPlacementRulesEnabled() {
@@ -311,6 +339,8 @@ func (c *RaftCluster) runBackgroundJobs(interval time.Duration) {
 
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
+	saveTicker := time.NewTicker(hotCacheSaveInterval)
+	defer saveTicker.Stop()
 
 	for {
 		select {
@@ -321,8 +351,12 @@ func (c *RaftCluster) runBackgroundJobs(interval time.Duration) {
 			return
 		case <-ticker.C:
 			c.checkStores()
+			c.updateBootstrapMode()
+			c.updateHotStatConfig()
 			c.collectMetrics()
 			c.coordinator.opController.PruneHistory()
+		case <-saveTicker.C:
+			c.saveHotCache()
 		}
 	}
 }
@@ -354,7 +388,17 @@ func (c *RaftCluster) runReplicationMode() {
 // Stop stops the cluster.
 func (c *RaftCluster) Stop() {
 	c.Lock()
+	if !c.running {
+		c.Unlock()
+		return
+	}
+	c.Unlock()
+
+	// Save the latest hot cache for the next leader. It is best-effort, since
+	// the cache is also saved periodically, and it is done outside the lock.
+	c.saveHotCache()
 
+	c.Lock()
 	if !c.running {
 		c.Unlock()
 		return
@@ -367,6 +411,47 @@ func (c *RaftCluster) Stop() {
 	c.wg.Wait()
 }
 
+// updateHotStatConfig applies the options of the hot statistics to the hot
+// cache. It is called by the background jobs rather than on each heartbeat, so
+// the changed options take effect within backgroundJobInterval.
+func (c *RaftCluster) updateHotStatConfig() {
+	c.hotStat.SetDenoiseWindowSize(c.opt.GetHeartbeatDenoiseWindowSize())
+	c.hotStat.SetThresholdRecalibrationInterval(c.opt.GetHotThresholdRecalibrationInterval())
+	c.hotStat.SetSamplingRate(c.opt.GetHotCheckSamplingRate())
+}
+
+// saveHotCache persists the hot cache so that the next leader can warm up with it.
+// It is saved periodically while the cluster is running and once more when it
+// stops. The lock is only held to export the cache rather than during the write
+// to the storage.
+func (c *RaftCluster) saveHotCache() {
+	c.RLock()
+	data, err := c.hotStat.HotCache.Export()
+	c.RUnlock()
+	if err != nil {
+		log.Warn("failed to export hot cache", errs.ZapError(err))
+		return
+	}
+	if err := c.storage.SaveHotCache(data); err != nil {
+		log.Warn("failed to save hot cache", errs.ZapError(err))
+	}
+}
+
+// loadHotCache restores the hot cache saved by the previous leader.
+func (c *RaftCluster) loadHotCache() {
+	data, err := c.storage.LoadHotCache()
+	if err != nil {
+		log.Warn("failed to load hot cache", errs.ZapError(err))
+		return
+	}
+	if data == "" {
+		return
+	}
+	if err := c.hotStat.HotCache.Import([]byte(data)); err != nil {
+		log.Warn("failed to import hot cache", errs.ZapError(err))
+	}
+}
+
 // IsRunning return if the cluster is running.
 func (c *RaftCluster) IsRunning() bool {
 	c.RLock()
@@ -449,6 +534,12 @@ func (c *RaftCluster) AddSuspectRegions(regionIDs ...uint64) {
 	for _, regionID := range regionIDs {
 		c.suspectRegions.Put(regionID, nil)
 	}
+	limit := int(c.opt.GetMaxSuspectRegions())
+	if limit > 0 && c.suspectRegions.Len() > limit+int(float64(limit)*suspectRegionsSlackRatio) {
+		if evicted := c.suspectRegions.Shrink(limit); evicted > 0 {
+			suspectRegionsEvictedCounter.Add(float64(evicted))
+		}
+	}
 }
 
 // GetSuspectRegions gets all suspect regions.
@@ -554,6 +645,8 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 	// Save to cache if meta or leader is updated, or contains any down/pending peer.
 	// Mark isNew if the region in cache does not have leader.
 	var saveKV, saveCache, isNew, needSync bool
+	// oldLeaderStoreID is set when the leader moves to another store.
+	var oldLeaderStoreID uint64
 	if origin == nil {
 		log.Debug("insert new region",
 			zap.Uint64("region-id", region.GetID()),
@@ -589,6 +682,7 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 					zap.Uint64("from", origin.GetLeader().GetStoreId()),
 					zap.Uint64("to", region.GetLeader().GetStoreId()),
 				)
+				oldLeaderStoreID = origin.GetLeader().GetStoreId()
 			}
 			saveCache, needSync = true, true
 		}
@@ -689,6 +783,14 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 	for _, readItem := range readItems {
 		c.hotStat.Update(readItem)
 	}
+	// The write heat should follow the leader if the old leader is down.
+	if oldLeaderStoreID != 0 {
+		oldLeader := origin.GetLeader()
+		if store := c.core.GetStore(oldLeaderStoreID); region.GetDownPeer(oldLeader.GetId()) != nil ||
+			(store != nil && store.IsDisconnected()) {
+			c.hotStat.TransferHeat(oldLeaderStoreID, region.GetLeader().GetStoreId(), region.GetID())
+		}
+	}
 	c.Unlock()
 
 	// If there are concurrent heartbeats from the same region, the last write will win even if
@@ -1040,7 +1142,14 @@ func (c *RaftCluster) RemoveStore(storeID uint64, physicallyDestroyed bool) erro
 		return errs.ErrStoreDestroyed.FastGenByArgs(storeID)
 	}
 
-	newStore := store.Clone(core.OfflineStore(physicallyDestroyed))
+	reason := "store is removed"
+	if physicallyDestroyed {
+		reason = "store is removed and physically destroyed"
+	}
+	newStore := store.Clone(
+		core.OfflineStore(physicallyDestroyed),
+		core.RecordStateTransition(store.GetState(), reason),
+	)
 	log.Warn("store has been offline",
 		zap.Uint64("store-id", newStore.GetID()),
 		zap.String("store-address", newStore.GetAddress()),
@@ -1054,6 +1163,64 @@ func (c *RaftCluster) RemoveStore(storeID uint64, physicallyDestroyed bool) erro
 	return err
 }
 
+// RequestStoreRemoval requests to remove a store. If store-removal-confirmation-timeout
+// is set, the store is not removed until ConfirmStoreRemoval is called with the
+// returned token within the timeout. Otherwise, the store is removed directly and
+// the returned token is empty.
+func (c *RaftCluster) RequestStoreRemoval(storeID uint64, physicallyDestroyed bool) (string, error) {
+	timeout := c.opt.GetStoreRemovalConfirmationTimeout()
+	if timeout <= 0 {
+		return "", c.RemoveStore(storeID, physicallyDestroyed)
+	}
+	store := c.GetStore(storeID)
+	if store == nil {
+		return "", errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	if store.IsTombstone() {
+		return "", errs.ErrStoreTombstone.FastGenByArgs(storeID)
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.WithStack(err)
+	}
+	token := hex.EncodeToString(b)
+
+	c.removalMu.Lock()
+	defer c.removalMu.Unlock()
+	if c.removalConfirmations == nil {
+		c.removalConfirmations = make(map[uint64]*storeRemovalConfirmation)
+	}
+	c.removalConfirmations[storeID] = &storeRemovalConfirmation{
+		token:               token,
+		physicallyDestroyed: physicallyDestroyed,
+		deadline:            time.Now().Add(timeout),
+	}
+	log.Info("store removal is waiting for confirmation",
+		zap.Uint64("store-id", storeID),
+		zap.Bool("physically-destroyed", physicallyDestroyed),
+		zap.Duration("timeout", timeout))
+	return token, nil
+}
+
+// ConfirmStoreRemoval removes the store requested by RequestStoreRemoval if the
+// token matches and has not expired.
+func (c *RaftCluster) ConfirmStoreRemoval(storeID uint64, token string) error {
+	c.removalMu.Lock()
+	confirmation, ok := c.removalConfirmations[storeID]
+	if ok && time.Now().After(confirmation.deadline) {
+		delete(c.removalConfirmations, storeID)
+		ok = false
+	}
+	if !ok || confirmation.token != token {
+		c.removalMu.Unlock()
+		return errs.ErrStoreRemovalNotConfirmed.FastGenByArgs(storeID)
+	}
+	delete(c.removalConfirmations, storeID)
+	c.removalMu.Unlock()
+
+	return c.RemoveStore(storeID, confirmation.physicallyDestroyed)
+}
+
 // buryStore marks a store as tombstone in cluster.
 // The store should be empty before calling this func
 // State transition: Offline -> Tombstone.
@@ -1075,7 +1242,10 @@ func (c *RaftCluster) buryStore(storeID uint64) error {
 		return errs.ErrStoreIsUp.FastGenByArgs()
 	}
 
-	newStore := store.Clone(core.TombstoneStore())
+	newStore := store.Clone(
+		core.TombstoneStore(),
+		core.RecordStateTransition(store.GetState(), "all regions have been moved out"),
+	)
 	log.Warn("store has been Tombstone",
 		zap.Uint64("store-id", newStore.GetID()),
 		zap.String("store-address", newStore.GetAddress()),
@@ -1127,7 +1297,10 @@ func (c *RaftCluster) UpStore(storeID uint64) error {
 		return nil
 	}
 
-	newStore := store.Clone(core.UpStore())
+	newStore := store.Clone(
+		core.UpStore(),
+		core.RecordStateTransition(store.GetState(), "store is set up"),
+	)
 	log.Warn("store has been up",
 		zap.Uint64("store-id", storeID),
 		zap.String("store-address", newStore.GetAddress()))
@@ -1257,6 +1430,7 @@ func (c *RaftCluster) collectMetrics() {
 	statsMap.Collect()
 
 	c.coordinator.collectSchedulerMetrics()
+	c.coordinator.collectCheckerMetrics()
 	c.coordinator.collectHotSpotMetrics()
 	c.collectClusterMetrics()
 	c.collectHealthStatus()
@@ -1267,6 +1441,7 @@ func (c *RaftCluster) resetMetrics() {
 	statsMap.Reset()
 
 	c.coordinator.resetSchedulerMetrics()
+	c.coordinator.resetCheckerMetrics()
 	c.coordinator.resetHotSpotMetrics()
 	c.resetClusterMetrics()
 	c.resetHealthStatus()
@@ -1325,6 +1500,31 @@ func (c *RaftCluster) GetRegionStatsByType(typ statistics.RegionStatisticType) [
 	return c.regionStats.GetRegionStatsByType(typ)
 }
 
+// GetMissingPeerRegions returns the regions which have fewer peers than the
+// replication factor. It uses the index of the region statistics if it is
+// collected, otherwise it scans all regions.
+func (c *RaftCluster) GetMissingPeerRegions() []*core.RegionInfo {
+	c.RLock()
+	defer c.RUnlock()
+	if c.regionStats != nil {
+		return c.regionStats.GetRegionStatsByType(statistics.MissPeer)
+	}
+	var regions []*core.RegionInfo
+	for _, region := range c.core.GetRegions() {
+		desiredReplicas := c.opt.GetMaxReplicas()
+		if c.opt.IsPlacementRulesEnabled() {
+			desiredReplicas = 0
+			for _, rule := range c.ruleManager.GetRulesForApplyRegion(region) {
+				desiredReplicas += rule.Count
+			}
+		}
+		if len(region.GetPeers()) < desiredReplicas {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
 // GetOfflineRegionStatsByType gets the status of the offline region by types.
 func (c *RaftCluster) GetOfflineRegionStatsByType(typ statistics.RegionStatisticType) []*core.RegionInfo {
 	c.RLock()
@@ -1433,6 +1633,13 @@ func (c *RaftCluster) PutConfig(meta *metapb.Cluster) error {
 	return c.putMetaLocked(proto.Clone(meta).(*metapb.Cluster))
 }
 
+// GetRuleChecker returns rule checker.
+func (c *RaftCluster) GetRuleChecker() *checker.RuleChecker {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.checkers.GetRuleChecker()
+}
+
 // GetMergeChecker returns merge checker.
 func (c *RaftCluster) GetMergeChecker() *checker.MergeChecker {
 	c.RLock()
@@ -1440,6 +1647,13 @@ func (c *RaftCluster) GetMergeChecker() *checker.MergeChecker {
 	return c.coordinator.checkers.GetMergeChecker()
 }
 
+// GetCheckerHitRate returns the fraction of the latest checks which result in an operator of each checker.
+func (c *RaftCluster) GetCheckerHitRate() map[string]float64 {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.checkers.CheckerHitRate()
+}
+
 // GetComponentManager returns component manager.
 func (c *RaftCluster) GetComponentManager() *component.Manager {
 	c.RLock()
@@ -1454,6 +1668,39 @@ func (c *RaftCluster) isPrepared() bool {
 	return c.prepareChecker.check(c)
 }
 
+// isInBootstrapMode returns true if the cluster was found in bootstrap mode by
+// the last update. It does not take the cluster lock, so it is safe to call
+// with the coordinator lock held.
+func (c *RaftCluster) isInBootstrapMode() bool {
+	return atomic.LoadInt32(&c.bootstrapMode) == 1
+}
+
+func (c *RaftCluster) setBootstrapMode(bootstrapMode bool) {
+	var v int32
+	if bootstrapMode {
+		v = 1
+	}
+	atomic.StoreInt32(&c.bootstrapMode, v)
+}
+
+// updateBootstrapMode checks if the cluster is in bootstrap mode.
+func (c *RaftCluster) updateBootstrapMode() {
+	c.RLock()
+	defer c.RUnlock()
+	c.setBootstrapMode(c.isInBootstrapModeLocked())
+}
+
+// isInBootstrapModeLocked returns true if the cluster has too few regions,
+// which means it is just bootstrapped and the regions are growing rapidly.
+// The bootstrap mode lasts at most collectTimeout, so a small cluster can
+// still be scheduled.
+func (c *RaftCluster) isInBootstrapModeLocked() bool {
+	if time.Since(c.prepareChecker.start) > collectTimeout {
+		return false
+	}
+	return uint64(c.core.GetRegionCount()) < c.opt.GetMinRegionsForScheduling()
+}
+
 // GetStoresLoads returns load stats of all stores.
 func (c *RaftCluster) GetStoresLoads() map[uint64][]float64 {
 	c.RLock()
@@ -1564,6 +1811,12 @@ func (c *RaftCluster) GetHotWriteRegions() *statistics.StoreHotPeersInfos {
 	return co.getHotWriteRegions()
 }
 
+// GetStoreHotPeerCountTimeSeries gets the number of hot peers of the store
+// sampled in the window.
+func (c *RaftCluster) GetStoreHotPeerCountTimeSeries(storeID uint64, kind statistics.FlowKind, window time.Duration) []statistics.TimeSeriesPoint {
+	return c.hotStat.GetStoreHotPeerCountTimeSeries(storeID, kind, window)
+}
+
 // GetHotReadRegions gets hot read regions' info.
 func (c *RaftCluster) GetHotReadRegions() *statistics.StoreHotPeersInfos {
 	c.RLock()
@@ -1579,6 +1832,13 @@ func (c *RaftCluster) GetSchedulers() []string {
 	return c.coordinator.getSchedulers()
 }
 
+// GetSchedulerStats returns the statistics of all schedulers.
+func (c *RaftCluster) GetSchedulerStats() map[string]SchedulerStats {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.getSchedulerStats()
+}
+
 // GetSchedulerHandlers gets all scheduler handlers.
 func (c *RaftCluster) GetSchedulerHandlers() map[string]http.Handler {
 	c.RLock()
@@ -1607,6 +1867,20 @@ func (c *RaftCluster) PauseOrResumeScheduler(name string, t int64) error {
 	return c.coordinator.pauseOrResumeScheduler(name, t)
 }
 
+// PauseOrResumeChecker pauses or resumes a checker.
+func (c *RaftCluster) PauseOrResumeChecker(name string, t int64) error {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.pauseOrResumeChecker(name, t)
+}
+
+// GetCheckerPauseUntil returns the unix time until which the checker is paused.
+func (c *RaftCluster) GetCheckerPauseUntil(name string) (int64, error) {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.checkers.GetCheckerPauseUntil(name)
+}
+
 // IsSchedulerPaused checks if a scheduler is paused.
 func (c *RaftCluster) IsSchedulerPaused(name string) (bool, error) {
 	c.RLock()
@@ -1614,6 +1888,13 @@ func (c *RaftCluster) IsSchedulerPaused(name string) (bool, error) {
 	return c.coordinator.isSchedulerPaused(name)
 }
 
+// GetSchedulerPauseTimeRemaining returns how long a scheduler is still paused.
+func (c *RaftCluster) GetSchedulerPauseTimeRemaining(name string) (time.Duration, error) {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.GetSchedulerPauseTimeRemaining(name)
+}
+
 // IsSchedulerDisabled checks if a scheduler is disabled.
 func (c *RaftCluster) IsSchedulerDisabled(name string) (bool, error) {
 	c.RLock()
@@ -1621,6 +1902,20 @@ func (c *RaftCluster) IsSchedulerDisabled(name string) (bool, error) {
 	return c.coordinator.isSchedulerDisabled(name)
 }
 
+// ValidateScheduler checks if a scheduler can be added without adding it.
+func (c *RaftCluster) ValidateScheduler(schedulerType string, args []string) error {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.ValidateScheduler(schedulerType, args)
+}
+
+// GetSchedulerType returns the type of a running scheduler.
+func (c *RaftCluster) GetSchedulerType(name string) (string, error) {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.GetSchedulerType(name)
+}
+
 // GetStoreLimiter returns the dynamic adjusting limiter
 func (c *RaftCluster) GetStoreLimiter() *StoreLimiter {
 	return c.limiter
@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 	"testing"
 	"time"
@@ -95,6 +96,35 @@ func (s *testClusterInfoSuite) TestStoreHeartbeat(c *C) {
 	}
 }
 
+func (s *testClusterInfoSuite) TestSuspectRegionsLimit(c *C) {
+	cfg, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	cfg.MaxSuspectRegions = 3
+	cluster := newTestRaftCluster(mockid.NewIDAllocator(), opt, core.NewStorage(kv.NewMemoryKV()), core.NewBasicCluster())
+
+	cluster.AddSuspectRegions(1, 2)
+	cluster.AddSuspectRegions(3, 4)
+	c.Assert(cluster.GetSuspectRegions(), HasLen, 3)
+	cluster.AddSuspectRegions(5)
+	suspects := cluster.GetSuspectRegions()
+	sort.Slice(suspects, func(i, j int) bool { return suspects[i] < suspects[j] })
+	c.Assert(suspects, DeepEquals, []uint64{3, 4, 5})
+
+	// 0 means no limit.
+	cfg.MaxSuspectRegions = 0
+	cluster.AddSuspectRegions(6, 7)
+	c.Assert(cluster.GetSuspectRegions(), HasLen, 5)
+
+	// the regions are evicted once the limit is exceeded by 10%.
+	cfg.MaxSuspectRegions = 20
+	for i := uint64(8); i <= 24; i++ {
+		cluster.AddSuspectRegions(i)
+	}
+	c.Assert(cluster.GetSuspectRegions(), HasLen, 22)
+	cluster.AddSuspectRegions(25)
+	c.Assert(cluster.GetSuspectRegions(), HasLen, 20)
+}
+
 func (s *testClusterInfoSuite) TestFilterUnhealthyStore(c *C) {
 	_, opt, err := newTestScheduleConfig()
 	c.Assert(err, IsNil)
@@ -928,6 +958,7 @@ func newTestScheduleConfig() (*config.ScheduleConfig, *config.PersistOptions, er
 	if err := cfg.Adjust(nil, false); err != nil {
 		return nil, nil, err
 	}
+	cfg.Schedule.MinRegionsForScheduling = 0
 	opt := config.NewPersistOptions(cfg)
 	opt.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.Version2_0))
 	return &cfg.Schedule, opt, nil
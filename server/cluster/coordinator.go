@@ -17,7 +17,9 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math/rand"
 	"net/http"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -45,7 +47,23 @@ const (
 	maxScheduleRetries        = 10
 	maxLoadConfigRetries      = 10
 
-	patrolScanRegionLimit = 128 // It takes about 14 minutes to iterate 1 million regions.
+	// The number of regions scanned from a suspect key range each time is
+	// 1/suspectScanRegionRatio of the regions, bounded by the min and max limit.
+	minSuspectScanRegionLimit = 64
+	maxSuspectScanRegionLimit = 1024
+	suspectScanRegionRatio    = 1000
+
+	defaultNumQuietCycles   = 3
+	quiescenceCheckInterval = 100 * time.Millisecond
+
+	defaultMaxOperatorsPerScheduleCall = 10
+	// The delay between the retries of a scheduler starts from
+	// defaultScheduleRetryBaseDelay and doubles after each failure.
+	defaultScheduleRetryBaseDelay = time.Millisecond
+	defaultScheduleRetryMaxDelay  = 20 * time.Millisecond
+	// workStealingIdleCycles is the number of rounds without operators after
+	// which a scheduler starts to submit the pending operators of others.
+	workStealingIdleCycles = 3
 	// PluginLoad means action for load plugin
 	PluginLoad = "PluginLoad"
 	// PluginUnload means action for unload plugin
@@ -67,6 +85,9 @@ type coordinator struct {
 	opController    *schedule.OperatorController
 	hbStreams       *hbstream.HeartbeatStreams
 	pluginInterface *schedule.PluginInterface
+	// numQuietCycles is the number of consecutive rounds without operators
+	// which a scheduler needs to be considered quiescent.
+	numQuietCycles int64
 }
 
 // newCoordinator creates a new coordinator.
@@ -78,12 +99,13 @@ func newCoordinator(ctx context.Context, cluster *RaftCluster, hbStreams *hbstre
 		cancel:          cancel,
 		cluster:         cluster,
 		checkers:        schedule.NewCheckerController(ctx, cluster, cluster.ruleManager, opController),
-		regionScatterer: schedule.NewRegionScatterer(ctx, cluster),
+		regionScatterer: schedule.NewRegionScatterer(ctx, cluster, time.Now().UnixNano()),
 		regionSplitter:  schedule.NewRegionSplitter(cluster, schedule.NewSplitRegionsHandler(cluster, opController)),
 		schedulers:      make(map[string]*scheduleController),
 		opController:    opController,
 		hbStreams:       hbStreams,
 		pluginInterface: schedule.NewPluginInterface(),
+		numQuietCycles:  defaultNumQuietCycles,
 	}
 }
 
@@ -93,21 +115,25 @@ func (c *coordinator) patrolRegions() {
 	defer logutil.LogPanic()
 
 	defer c.wg.Done()
-	timer := time.NewTimer(c.cluster.GetOpts().GetPatrolRegionInterval())
+	timer := time.NewTimer(c.patrolRegionInterval())
 	defer timer.Stop()
 
 	log.Info("coordinator starts patrol regions")
 	start := time.Now()
-	var key []byte
+	var (
+		key         []byte
+		orphanPeers int
+	)
 	for {
 		select {
 		case <-timer.C:
-			timer.Reset(c.cluster.GetOpts().GetPatrolRegionInterval())
+			timer.Reset(c.patrolRegionInterval())
 		case <-c.ctx.Done():
 			log.Info("patrol regions has been stopped")
 			return
 		}
 
+		c.checkers.ResetOrphanPeerFixes()
 		// Check suspect regions first.
 		c.checkSuspectRegions()
 		// Check suspect key ranges
@@ -115,7 +141,7 @@ func (c *coordinator) patrolRegions() {
 		// Check regions in the waiting list
 		c.checkWaitingRegions()
 
-		regions := c.cluster.ScanRegions(key, nil, patrolScanRegionLimit)
+		regions := c.cluster.ScanRegions(key, nil, c.cluster.GetOpts().GetPatrolRegionBatchSize())
 		if len(regions) == 0 {
 			// Resets the scan key.
 			key = nil
@@ -123,6 +149,7 @@ func (c *coordinator) patrolRegions() {
 		}
 
 		for _, region := range regions {
+			orphanPeers += c.checkers.GetOrphanPeerCount(region)
 			// Skips the region if there is already a pending operator.
 			if c.opController.GetOperator(region.GetID()) != nil {
 				continue
@@ -147,7 +174,9 @@ func (c *coordinator) patrolRegions() {
 		c.cluster.updateRegionsLabelLevelStats(regions)
 		if len(key) == 0 {
 			patrolCheckRegionsGauge.Set(time.Since(start).Seconds())
+			ruleCheckerOrphanPeersGauge.Set(float64(orphanPeers))
 			start = time.Now()
+			orphanPeers = 0
 		}
 		failpoint.Inject("break-patrol", func() {
 			failpoint.Break()
@@ -155,6 +184,19 @@ func (c *coordinator) patrolRegions() {
 	}
 }
 
+// patrolRegionInterval returns the patrol interval with a random jitter added.
+func (c *coordinator) patrolRegionInterval() time.Duration {
+	return addJitter(c.cluster.GetOpts().GetPatrolRegionInterval(), c.cluster.GetOpts().GetPatrolRegionIntervalJitter())
+}
+
+// addJitter adds a random duration in [0, jitter) to the interval.
+func addJitter(interval, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Int63n(int64(jitter)))
+}
+
 func (c *coordinator) checkSuspectRegions() {
 	for _, id := range c.cluster.GetSuspectRegions() {
 		region := c.cluster.GetRegion(id)
@@ -186,7 +228,7 @@ func (c *coordinator) checkSuspectKeyRanges() {
 	if !success {
 		return
 	}
-	limit := 1024
+	limit := suspectScanRegionLimit(c.cluster.GetRegionCount())
 	regions := c.cluster.ScanRegions(keyRange[0], keyRange[1], limit)
 	if len(regions) == 0 {
 		return
@@ -205,9 +247,21 @@ func (c *coordinator) checkSuspectKeyRanges() {
 	c.cluster.AddSuspectRegions(regionIDList...)
 }
 
+// suspectScanRegionLimit returns the number of regions to scan from a suspect
+// key range according to the number of regions in the cluster.
+func suspectScanRegionLimit(regionCount int) int {
+	limit := regionCount / suspectScanRegionRatio
+	if limit < minSuspectScanRegionLimit {
+		return minSuspectScanRegionLimit
+	}
+	if limit > maxSuspectScanRegionLimit {
+		return maxSuspectScanRegionLimit
+	}
+	return limit
+}
+
 func (c *coordinator) checkWaitingRegions() {
 	items := c.checkers.GetWaitingRegions()
-	regionWaitingListGauge.Set(float64(len(items)))
 	for _, item := range items {
 		id := item.Key
 		region := c.cluster.GetRegion(id)
@@ -255,7 +309,7 @@ func (c *coordinator) run() {
 	defer ticker.Stop()
 	log.Info("coordinator starts to collect cluster information")
 	for {
-		if c.shouldRun() {
+		if c.cluster.isPrepared() {
 			log.Info("coordinator has finished cluster information preparation")
 			break
 		}
@@ -353,6 +407,8 @@ func (c *coordinator) run() {
 		log.Error("cannot persist schedule config", errs.ZapError(err))
 	}
 
+	c.loadCheckerPauses()
+
 	c.wg.Add(2)
 	// Starts to patrol regions.
 	go c.patrolRegions()
@@ -395,11 +451,21 @@ func (c *coordinator) LoadPlugin(pluginPath string, ch chan string) {
 func (c *coordinator) waitPluginUnload(pluginPath, schedulerName string, ch chan string) {
 	defer logutil.LogPanic()
 	defer c.wg.Done()
+	// unhealthy is set after the scheduler is removed because the plugin fails the
+	// health check. It keeps waiting for the signal so that unloading the plugin
+	// by the user does not block.
+	unhealthy := false
+	ticker := time.NewTicker(c.cluster.GetOpts().GetPluginHealthCheckInterval())
+	defer ticker.Stop()
 	// Get signal from channel which means user unload the plugin
 	for {
 		select {
 		case action := <-ch:
 			if action == PluginUnload {
+				if unhealthy {
+					log.Info("unload plugin", zap.String("plugin", pluginPath))
+					return
+				}
 				err := c.removeScheduler(schedulerName)
 				if err != nil {
 					log.Error("can not remove scheduler", zap.String("scheduler-name", schedulerName), errs.ZapError(err))
@@ -410,6 +476,19 @@ func (c *coordinator) waitPluginUnload(pluginPath, schedulerName string, ch chan
 			} else {
 				log.Error("unknown action", zap.String("action", action))
 			}
+		case <-ticker.C:
+			if unhealthy {
+				continue
+			}
+			if err := c.pluginInterface.HealthCheck(pluginPath); err != nil {
+				log.Error("plugin health check failed, remove its scheduler",
+					zap.String("plugin", pluginPath), zap.String("scheduler-name", schedulerName), errs.ZapError(err))
+				if err := c.removeScheduler(schedulerName); err != nil {
+					log.Error("can not remove scheduler", zap.String("scheduler-name", schedulerName), errs.ZapError(err))
+					continue
+				}
+				unhealthy = true
+			}
 		case <-c.ctx.Done():
 			log.Info("unload plugin has been stopped")
 			return
@@ -466,6 +545,16 @@ func (c *coordinator) getSchedulers() []string {
 	return names
 }
 
+func (c *coordinator) getSchedulerStats() map[string]SchedulerStats {
+	c.RLock()
+	defer c.RUnlock()
+	stats := make(map[string]SchedulerStats, len(c.schedulers))
+	for name, s := range c.schedulers {
+		stats[name] = s.GetStats()
+	}
+	return stats
+}
+
 func (c *coordinator) getSchedulerHandlers() map[string]http.Handler {
 	c.RLock()
 	defer c.RUnlock()
@@ -487,11 +576,26 @@ func (c *coordinator) collectSchedulerMetrics() {
 			allowScheduler = 1
 		}
 		schedulerStatusGauge.WithLabelValues(s.GetName(), "allow").Set(allowScheduler)
+		schedulerStatusGauge.WithLabelValues(s.GetName(), "retry_delay").Set(s.GetRetryDelay().Seconds())
+	}
+	if c.cluster.isInBootstrapMode() {
+		bootstrapModeGauge.Set(1)
+	} else {
+		bootstrapModeGauge.Set(0)
 	}
 }
 
 func (c *coordinator) resetSchedulerMetrics() {
 	schedulerStatusGauge.Reset()
+	bootstrapModeGauge.Set(0)
+}
+
+func (c *coordinator) collectCheckerMetrics() {
+	regionWaitingListGauge.Set(float64(c.checkers.GetRegionWaitingListSize()))
+}
+
+func (c *coordinator) resetCheckerMetrics() {
+	regionWaitingListGauge.Set(0)
 }
 
 func (c *coordinator) collectHotSpotMetrics() {
@@ -568,12 +672,22 @@ func (c *coordinator) resetHotSpotMetrics() {
 	hotSpotStatusGauge.Reset()
 }
 
+// shouldRun returns false if the schedulers should skip their rounds, which
+// happens while the cluster is in bootstrap mode since the operators would
+// soon be stale. The checkers keep running.
 func (c *coordinator) shouldRun() bool {
-	return c.cluster.isPrepared()
+	return !c.cluster.isInBootstrapMode()
 }
 
-func (c *coordinator) addScheduler(scheduler schedule.Scheduler, args ...string) error {
+// lockForScheduler acquires the lock and records the time waiting for it.
+func (c *coordinator) lockForScheduler(typ string) {
+	start := time.Now()
 	c.Lock()
+	schedulerLockWaitHistogram.WithLabelValues(typ).Observe(time.Since(start).Seconds())
+}
+
+func (c *coordinator) addScheduler(scheduler schedule.Scheduler, args ...string) error {
+	c.lockForScheduler("add")
 	defer c.Unlock()
 
 	if _, ok := c.schedulers[scheduler.GetName()]; ok {
@@ -592,8 +706,29 @@ func (c *coordinator) addScheduler(scheduler schedule.Scheduler, args ...string)
 	return nil
 }
 
+// ValidateScheduler checks if a scheduler of the type can be created with the
+// arguments and is not added yet. The scheduler is only created and decoded,
+// it is neither prepared, persisted nor run.
+func (c *coordinator) ValidateScheduler(schedulerType string, args []string) error {
+	// use a memory storage to keep the scheduler config from being saved.
+	storage := core.NewStorage(kv.NewMemoryKV())
+	s, err := schedule.CreateScheduler(schedulerType, c.opController, storage, schedule.ConfigSliceDecoder(schedulerType, args))
+	if err != nil {
+		return err
+	}
+	c.RLock()
+	defer c.RUnlock()
+	if c.cluster == nil {
+		return errs.ErrNotBootstrapped.FastGenByArgs()
+	}
+	if _, ok := c.schedulers[s.GetName()]; ok {
+		return errs.ErrSchedulerExisted.FastGenByArgs()
+	}
+	return nil
+}
+
 func (c *coordinator) removeScheduler(name string) error {
-	c.Lock()
+	c.lockForScheduler("remove")
 	defer c.Unlock()
 	if c.cluster == nil {
 		return errs.ErrNotBootstrapped.FastGenByArgs()
@@ -605,6 +740,7 @@ func (c *coordinator) removeScheduler(name string) error {
 
 	s.Stop()
 	schedulerStatusGauge.WithLabelValues(name, "allow").Set(0)
+	schedulerStatusGauge.DeleteLabelValues(name, "retry_delay")
 	delete(c.schedulers, name)
 
 	var err error
@@ -652,7 +788,7 @@ func (c *coordinator) removeOptScheduler(o *config.PersistOptions, name string)
 }
 
 func (c *coordinator) pauseOrResumeScheduler(name string, t int64) error {
-	c.Lock()
+	c.lockForScheduler("pause")
 	defer c.Unlock()
 	if c.cluster == nil {
 		return errs.ErrNotBootstrapped.FastGenByArgs()
@@ -693,6 +829,94 @@ func (c *coordinator) isSchedulerPaused(name string) (bool, error) {
 	return s.IsPaused(), nil
 }
 
+// pauseOrResumeChecker pauses the checker for t seconds, or resumes it if t
+// is not positive. The pause is persisted, so it survives the leader change.
+func (c *coordinator) pauseOrResumeChecker(name string, t int64) error {
+	if c.cluster == nil {
+		return errs.ErrNotBootstrapped.FastGenByArgs()
+	}
+	var err error
+	if t > 0 {
+		err = c.checkers.PauseChecker(name, t)
+	} else {
+		err = c.checkers.ResumeChecker(name)
+	}
+	if err != nil {
+		return err
+	}
+	pauseUntil, err := c.checkers.GetCheckerPauseUntil(name)
+	if err != nil {
+		return err
+	}
+	return c.cluster.storage.SaveCheckerPauseUntil(name, pauseUntil)
+}
+
+// loadCheckerPauses restores the persisted pauses of the checkers.
+func (c *coordinator) loadCheckerPauses() {
+	pauses, err := c.cluster.storage.LoadCheckerPauseUntil()
+	if err != nil {
+		log.Error("cannot load the pauses of checkers", errs.ZapError(err))
+		return
+	}
+	for name, pauseUntil := range pauses {
+		if err := c.checkers.PauseCheckerUntil(name, pauseUntil); err != nil {
+			log.Warn("cannot restore the pause of checker", zap.String("checker-name", name), errs.ZapError(err))
+		}
+	}
+}
+
+// GetSchedulerPauseTimeRemaining returns how long the scheduler is still paused.
+// It returns 0 if the scheduler is not paused.
+func (c *coordinator) GetSchedulerPauseTimeRemaining(name string) (time.Duration, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if c.cluster == nil {
+		return 0, errs.ErrNotBootstrapped.FastGenByArgs()
+	}
+	s, ok := c.schedulers[name]
+	if !ok {
+		return 0, errs.ErrSchedulerNotFound.FastGenByArgs()
+	}
+	return s.GetPauseTimeRemaining(), nil
+}
+
+// The allowed status of a scheduler returned by GetSchedulerAllowedStatus.
+const (
+	schedulerAllowed         = "true"
+	schedulerPausedStatus    = "false-paused"
+	schedulerBootstrapStatus = "false-bootstrap"
+	schedulerLimitedStatus   = "false-limited"
+)
+
+// GetSchedulerAllowedStatus returns whether each scheduler is allowed to
+// schedule now. A disallowed scheduler is paused, held by the bootstrap mode,
+// or limited by the operator count, in the order of precedence.
+func (c *coordinator) GetSchedulerAllowedStatus() map[string]string {
+	c.RLock()
+	schedulers := make(map[string]*scheduleController, len(c.schedulers))
+	for name, s := range c.schedulers {
+		schedulers[name] = s
+	}
+	c.RUnlock()
+
+	// The limits are checked without the coordinator lock held.
+	bootstrap := c.cluster.isInBootstrapMode()
+	status := make(map[string]string, len(schedulers))
+	for name, s := range schedulers {
+		switch {
+		case s.IsPaused():
+			status[name] = schedulerPausedStatus
+		case bootstrap:
+			status[name] = schedulerBootstrapStatus
+		case !s.Scheduler.IsScheduleAllowed(s.cluster):
+			status[name] = schedulerLimitedStatus
+		default:
+			status[name] = schedulerAllowed
+		}
+	}
+	return status
+}
+
 func (c *coordinator) isSchedulerDisabled(name string) (bool, error) {
 	c.RLock()
 	defer c.RUnlock()
@@ -713,6 +937,56 @@ func (c *coordinator) isSchedulerDisabled(name string) (bool, error) {
 	return false, nil
 }
 
+// GetSchedulerType returns the type of the running scheduler with the given name.
+func (c *coordinator) GetSchedulerType(name string) (string, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if c.cluster == nil {
+		return "", errs.ErrNotBootstrapped.FastGenByArgs()
+	}
+	s, ok := c.schedulers[name]
+	if !ok {
+		return "", errs.ErrSchedulerNotFound.FastGenByArgs()
+	}
+	return s.GetType(), nil
+}
+
+// WaitForQuiescence waits until all the running schedulers have generated no
+// operator for numQuietCycles consecutive rounds. It returns an error on timeout.
+// It is used by tests to wait for the scheduling to become stable.
+func (c *coordinator) WaitForQuiescence(timeout time.Duration) error {
+	ticker := time.NewTicker(quiescenceCheckInterval)
+	defer ticker.Stop()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		if c.isQuiescent() {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			return errors.Errorf("schedulers are still generating operators after %v", timeout)
+		case <-c.ctx.Done():
+			return errors.New("coordinator has been stopped")
+		}
+	}
+}
+
+func (c *coordinator) isQuiescent() bool {
+	c.RLock()
+	defer c.RUnlock()
+	for _, s := range c.schedulers {
+		if s.IsPaused() {
+			continue
+		}
+		if atomic.LoadInt64(&s.quietCycles) < c.numQuietCycles {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *coordinator) runScheduler(s *scheduleController) {
 	defer logutil.LogPanic()
 	defer c.wg.Done()
@@ -725,12 +999,8 @@ func (c *coordinator) runScheduler(s *scheduleController) {
 		select {
 		case <-timer.C:
 			timer.Reset(s.GetInterval())
-			if !s.AllowSchedule() {
-				continue
-			}
-			if op := s.Schedule(); op != nil {
-				added := c.opController.AddWaitingOperator(op...)
-				log.Debug("add operator", zap.Int("added", added), zap.Int("total", len(op)), zap.String("scheduler", s.GetName()))
+			if !c.scheduleRound(s) {
+				return
 			}
 
 		case <-s.Ctx().Done():
@@ -742,6 +1012,97 @@ func (c *coordinator) runScheduler(s *scheduleController) {
 	}
 }
 
+// scheduleRound runs one round of the scheduler. The panics of the scheduler
+// are recovered unless max-scheduler-panics is 0. It returns false if the
+// scheduler panics too many times and has been removed.
+func (c *coordinator) scheduleRound(s *scheduleController) (ok bool) {
+	if c.cluster.GetOpts().GetMaxSchedulerPanics() > 0 {
+		defer func() {
+			if r := recover(); r != nil {
+				ok = c.handleSchedulerPanic(s, r)
+			}
+		}()
+	}
+	if !c.shouldRun() || !s.AllowSchedule() {
+		// a skipped round generates no operator either.
+		atomic.AddInt64(&s.quietCycles, 1)
+		return true
+	}
+	op := s.nextOperators()
+	s.recordRun(time.Now(), len(op))
+	if len(op) > 0 {
+		atomic.StoreInt64(&s.quietCycles, 0)
+		added := c.opController.AddWaitingOperator(op...)
+		log.Debug("add operator", zap.Int("added", added), zap.Int("total", len(op)), zap.String("scheduler", s.GetName()))
+	} else if atomic.AddInt64(&s.quietCycles, 1) >= workStealingIdleCycles && c.cluster.GetOpts().IsWorkStealingEnabled() {
+		// The stolen operators are not counted in the stats of the idle scheduler.
+		if stolen := c.stealOperators(s); len(stolen) > 0 {
+			added := c.opController.AddWaitingOperator(stolen...)
+			log.Debug("add operator for busy scheduler", zap.Int("added", added), zap.Int("total", len(stolen)), zap.String("scheduler", s.GetName()))
+		}
+	}
+	return true
+}
+
+// handleSchedulerPanic records a recovered panic of the scheduler. If the
+// scheduler panics more than max-scheduler-panics times within
+// panic-window-duration, it is removed and false is returned.
+func (c *coordinator) handleSchedulerPanic(s *scheduleController, r interface{}) bool {
+	name := s.GetName()
+	log.Error("scheduler panicked", zap.String("scheduler-name", name), zap.Reflect("recover", r), zap.Stack("stack"))
+	schedulerPanicCounter.WithLabelValues(name, "panic").Inc()
+
+	opts := c.cluster.GetOpts()
+	if !s.recordPanic(time.Now(), opts.GetPanicWindowDuration(), opts.GetMaxSchedulerPanics()) {
+		return true
+	}
+	log.Error("scheduler panics too many times, remove it",
+		zap.String("scheduler-name", name),
+		zap.Uint64("max-scheduler-panics", opts.GetMaxSchedulerPanics()),
+		zap.Duration("panic-window-duration", opts.GetPanicWindowDuration()))
+	schedulerPanicCounter.WithLabelValues(name, "remove").Inc()
+	if err := c.removeScheduler(name); err != nil {
+		log.Error("can not remove the panicking scheduler", zap.String("scheduler-name", name), errs.ZapError(err))
+	}
+	return false
+}
+
+// stealOperators lets the idle scheduler generate the operators of the busiest
+// scheduler's type, so the work of the busy scheduler is done faster. See
+// busyBacklog for what a busy scheduler is. The busy scheduler must be allowed
+// to schedule, so the schedule limits are still respected, and no more
+// operators than the idle scheduler can submit in one round are returned.
+func (c *coordinator) stealOperators(idle *scheduleController) []*operator.Operator {
+	type candidate struct {
+		s       *scheduleController
+		backlog int
+	}
+	var busy []candidate
+	c.RLock()
+	for _, s := range c.schedulers {
+		if s == idle || s.IsPaused() {
+			continue
+		}
+		if backlog := s.busyBacklog(); backlog > 0 {
+			busy = append(busy, candidate{s: s, backlog: backlog})
+		}
+	}
+	c.RUnlock()
+
+	sort.Slice(busy, func(i, j int) bool { return busy[i].backlog > busy[j].backlog })
+	for _, b := range busy {
+		if !b.s.AllowSchedule() {
+			continue
+		}
+		ops := b.s.scheduleOnce()
+		if limit := idle.MaxOperatorsPerScheduleCall; limit > 0 && len(ops) > limit {
+			ops = ops[:limit]
+		}
+		return ops
+	}
+	return nil
+}
+
 // scheduleController is used to manage a scheduler to schedule.
 type scheduleController struct {
 	schedule.Scheduler
@@ -751,6 +1112,53 @@ type scheduleController struct {
 	ctx          context.Context
 	cancel       context.CancelFunc
 	delayUntil   int64
+	// quietCycles is the number of consecutive rounds generating no operator,
+	// including the rounds skipped because scheduling is not allowed.
+	quietCycles int64
+	// MaxOperatorsPerScheduleCall limits the number of operators submitted in
+	// one round, the rest are kept in pendingOps for the next rounds.
+	// 0 means no limit.
+	MaxOperatorsPerScheduleCall int
+	// pendingMu protects pendingOps, which may be read by other schedulers
+	// when work stealing is enabled.
+	pendingMu  sync.Mutex
+	pendingOps []*operator.Operator
+	// scheduleMu serializes the calls to the scheduler, which may also be
+	// called by other schedulers when work stealing is enabled.
+	scheduleMu sync.Mutex
+	// RetryBaseDelay and RetryMaxDelay bound the delay between the retries
+	// when the scheduler generates no operator. 0 means retry without waiting.
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// retryDelay is the delay in nanoseconds before the next retry, 0 means
+	// starting from RetryBaseDelay. It is reset at the start of each round, so
+	// the backoff only applies to the retries within a round.
+	retryDelay int64
+	// MaxConsecutiveNoOps is the number of consecutive rounds without
+	// operators before the schedule interval starts to increase.
+	MaxConsecutiveNoOps int
+	// noOpCycles is the number of consecutive rounds without operators. It
+	// is only accessed by the goroutine running the scheduler.
+	noOpCycles int
+	// panicTimes records the recent panics of the scheduler. It is only
+	// accessed by the goroutine running the scheduler.
+	panicTimes []time.Time
+
+	statsMu        sync.RWMutex
+	stats          SchedulerStats
+	totalOperators uint64
+}
+
+// SchedulerStats is the statistics of the rounds in which a scheduler is
+// allowed to schedule.
+type SchedulerStats struct {
+	RunCount  uint64 `json:"run_count"`
+	NoOpCount uint64 `json:"no_op_count"`
+	// NoOpRate is NoOpCount/RunCount. A high rate may indicate that the
+	// scheduler is misconfigured and runs without effect.
+	NoOpRate           float64   `json:"no_op_rate"`
+	LastRunTime        time.Time `json:"last_run_time"`
+	AvgOperatorsPerRun float64   `json:"avg_operators_per_run"`
 }
 
 // newScheduleController creates a new scheduleController.
@@ -763,6 +1171,11 @@ func newScheduleController(c *coordinator, s schedule.Scheduler) *scheduleContro
 		nextInterval: s.GetMinInterval(),
 		ctx:          ctx,
 		cancel:       cancel,
+
+		MaxOperatorsPerScheduleCall: defaultMaxOperatorsPerScheduleCall,
+		RetryBaseDelay:              defaultScheduleRetryBaseDelay,
+		RetryMaxDelay:               defaultScheduleRetryMaxDelay,
+		MaxConsecutiveNoOps:         maxScheduleRetries,
 	}
 }
 
@@ -775,17 +1188,110 @@ func (s *scheduleController) Stop() {
 }
 
 func (s *scheduleController) Schedule() []*operator.Operator {
+	atomic.StoreInt64(&s.retryDelay, 0)
 	for i := 0; i < maxScheduleRetries; i++ {
 		// If we have schedule, reset interval to the minimal interval.
-		if op := s.Scheduler.Schedule(s.cluster); op != nil {
+		if op := s.scheduleOnce(); op != nil {
 			s.nextInterval = s.Scheduler.GetMinInterval()
+			s.noOpCycles = 0
+			atomic.StoreInt64(&s.retryDelay, 0)
 			return op
 		}
+		if i == maxScheduleRetries-1 {
+			break
+		}
+		if delay := s.nextRetryDelay(); delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-s.ctx.Done():
+				return nil
+			}
+		}
+	}
+	// The interval is only increased after consecutive rounds without
+	// operators, so that a scheduler which finds work occasionally keeps
+	// checking at a short interval.
+	if s.noOpCycles++; s.noOpCycles >= s.MaxConsecutiveNoOps {
+		s.nextInterval = s.Scheduler.GetNextInterval(s.nextInterval)
 	}
-	s.nextInterval = s.Scheduler.GetNextInterval(s.nextInterval)
 	return nil
 }
 
+// scheduleOnce calls the scheduler once without retrying.
+func (s *scheduleController) scheduleOnce() []*operator.Operator {
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+	return s.Scheduler.Schedule(s.cluster)
+}
+
+// nextRetryDelay returns the delay before the next retry and doubles the
+// delay for the retry after it, bounded by RetryMaxDelay.
+func (s *scheduleController) nextRetryDelay() time.Duration {
+	delay := time.Duration(atomic.LoadInt64(&s.retryDelay))
+	if delay == 0 {
+		delay = s.RetryBaseDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	next := delay * 2
+	if next > s.RetryMaxDelay {
+		next = s.RetryMaxDelay
+	}
+	atomic.StoreInt64(&s.retryDelay, int64(next))
+	return delay
+}
+
+// GetRetryDelay returns the delay before the next retry, 0 means the
+// scheduler is not backing off.
+func (s *scheduleController) GetRetryDelay() time.Duration {
+	return time.Duration(atomic.LoadInt64(&s.retryDelay))
+}
+
+// nextOperators returns the operators to submit in this round. The operators
+// left by the last round are submitted before scheduling again.
+func (s *scheduleController) nextOperators() []*operator.Operator {
+	s.pendingMu.Lock()
+	empty := len(s.pendingOps) == 0
+	s.pendingMu.Unlock()
+	if empty {
+		ops := s.Schedule()
+		s.pendingMu.Lock()
+		s.pendingOps = ops
+		s.pendingMu.Unlock()
+	}
+	return s.takePendingOperators(s.MaxOperatorsPerScheduleCall)
+}
+
+// takePendingOperators removes at most limit operators from the front of the
+// pending queue and returns them. 0 means no limit.
+func (s *scheduleController) takePendingOperators(limit int) []*operator.Operator {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	ops := s.pendingOps
+	if limit > 0 && len(ops) > limit {
+		ops = ops[:limit]
+	}
+	s.pendingOps = s.pendingOps[len(ops):]
+	return ops
+}
+
+// busyBacklog returns the backlog of the scheduler, which is the number of the
+// pending operators it cannot submit in its next round because of
+// MaxOperatorsPerScheduleCall. A scheduler is busy if it has a backlog. A
+// scheduler without the limit is never busy.
+func (s *scheduleController) busyBacklog() int {
+	if s.MaxOperatorsPerScheduleCall <= 0 {
+		return 0
+	}
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	if backlog := len(s.pendingOps) - s.MaxOperatorsPerScheduleCall; backlog > 0 {
+		return backlog
+	}
+	return 0
+}
+
 // GetInterval returns the interval of scheduling for a scheduler.
 func (s *scheduleController) GetInterval() time.Duration {
 	return s.nextInterval
@@ -801,3 +1307,47 @@ func (s *scheduleController) IsPaused() bool {
 	delayUntil := atomic.LoadInt64(&s.delayUntil)
 	return time.Now().Unix() < delayUntil
 }
+
+// GetPauseTimeRemaining returns how long the scheduler is still paused, 0 if
+// it is not paused.
+func (s *scheduleController) GetPauseTimeRemaining() time.Duration {
+	delayUntil := atomic.LoadInt64(&s.delayUntil)
+	if remaining := time.Until(time.Unix(delayUntil, 0)); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// recordPanic records a panic happened at now and drops the ones out of the
+// window. It returns true if there are more than maxPanics panics in the window.
+func (s *scheduleController) recordPanic(now time.Time, window time.Duration, maxPanics uint64) bool {
+	recent := s.panicTimes[:0]
+	for _, t := range s.panicTimes {
+		if now.Sub(t) < window {
+			recent = append(recent, t)
+		}
+	}
+	s.panicTimes = append(recent, now)
+	return uint64(len(s.panicTimes)) > maxPanics
+}
+
+// recordRun records a round which generates opCount operators.
+func (s *scheduleController) recordRun(now time.Time, opCount int) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	s.stats.RunCount++
+	if opCount == 0 {
+		s.stats.NoOpCount++
+	}
+	s.stats.LastRunTime = now
+	s.totalOperators += uint64(opCount)
+	s.stats.NoOpRate = float64(s.stats.NoOpCount) / float64(s.stats.RunCount)
+	s.stats.AvgOperatorsPerRun = float64(s.totalOperators) / float64(s.stats.RunCount)
+}
+
+// GetStats returns the statistics of the scheduler.
+func (s *scheduleController) GetStats() SchedulerStats {
+	s.statsMu.RLock()
+	defer s.statsMu.RUnlock()
+	return s.stats
+}
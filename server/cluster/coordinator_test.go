@@ -15,16 +15,20 @@ package cluster
 
 import (
 	"context"
+	"math"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/eraftpb"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/mock/mockhbstream"
 	"github.com/tikv/pd/pkg/testutil"
 	"github.com/tikv/pd/pkg/typeutil"
@@ -466,6 +470,7 @@ func (s *testCoordinatorSuite) TestCheckCache(c *C) {
 	co.wg.Add(1)
 	co.patrolRegions()
 	c.Assert(len(co.checkers.GetWaitingRegions()), Equals, 1)
+	c.Assert(co.checkers.GetRegionWaitingListSize(), Equals, 1)
 
 	// cancel the replica-schedule-limit restriction
 	opt := tc.GetOpts()
@@ -477,6 +482,7 @@ func (s *testCoordinatorSuite) TestCheckCache(c *C) {
 	oc := co.opController
 	c.Assert(len(oc.GetOperators()), Equals, 1)
 	c.Assert(len(co.checkers.GetWaitingRegions()), Equals, 0)
+	c.Assert(co.checkers.GetRegionWaitingListSize(), Equals, 0)
 
 	// case 2: operator cannot be created due to store limit restriction
 	oc.RemoveOperator(oc.GetOperator(1))
@@ -625,6 +631,46 @@ func (s *testCoordinatorSuite) TestShouldRunWithNonLeaderRegions(c *C) {
 	c.Assert(tc.GetRegion(10).GetLeader().GetStoreId(), Equals, uint64(0))
 }
 
+func (s *testCoordinatorSuite) TestBootstrapMode(c *C) {
+	tc, co, cleanup := prepare(func(cfg *config.ScheduleConfig) {
+		cfg.MinRegionsForScheduling = 3
+	}, nil, nil, c)
+	defer cleanup()
+
+	sl, err := schedule.CreateScheduler(schedulers.ShuffleLeaderType, co.opController, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(schedulers.ShuffleLeaderType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	mb := &mockBatchScheduler{Scheduler: sl}
+	sc := newScheduleController(co, mb)
+	sc.RetryBaseDelay = 0
+
+	// The schedulers skip their rounds in bootstrap mode.
+	regions := newTestRegions(3, 3)
+	c.Assert(tc.processRegionHeartbeat(regions[0]), IsNil)
+	c.Assert(tc.processRegionHeartbeat(regions[1]), IsNil)
+	tc.updateBootstrapMode()
+	c.Assert(tc.isInBootstrapMode(), IsTrue)
+	c.Assert(co.shouldRun(), IsFalse)
+	c.Assert(co.scheduleRound(sc), IsTrue)
+	c.Assert(mb.calls, Equals, 0)
+
+	c.Assert(tc.processRegionHeartbeat(regions[2]), IsNil)
+	tc.updateBootstrapMode()
+	c.Assert(co.shouldRun(), IsTrue)
+	c.Assert(co.scheduleRound(sc), IsTrue)
+	c.Assert(mb.calls, Not(Equals), 0)
+
+	// The mode is updated continuously, so it comes back if the option is raised.
+	cfg := tc.opt.GetScheduleConfig().Clone()
+	cfg.MinRegionsForScheduling = 10
+	tc.opt.SetScheduleConfig(cfg)
+	tc.updateBootstrapMode()
+	c.Assert(co.shouldRun(), IsFalse)
+	// The bootstrap mode ends after collectTimeout even if the cluster is small.
+	tc.prepareChecker.start = time.Now().Add(-collectTimeout - time.Second)
+	tc.updateBootstrapMode()
+	c.Assert(co.shouldRun(), IsTrue)
+}
+
 func (s *testCoordinatorSuite) TestAddScheduler(c *C) {
 	tc, co, cleanup := prepare(nil, nil, func(co *coordinator) { co.run() }, c)
 	defer cleanup()
@@ -675,6 +721,41 @@ func (s *testCoordinatorSuite) TestAddScheduler(c *C) {
 	waitNoResponse(c, stream)
 }
 
+func (s *testCoordinatorSuite) TestWaitForQuiescence(c *C) {
+	_, co, cleanup := prepare(nil, nil, func(co *coordinator) { co.run() }, c)
+	defer cleanup()
+
+	// There is no region to schedule.
+	c.Assert(co.WaitForQuiescence(10*time.Second), IsNil)
+
+	co.Lock()
+	co.numQuietCycles = math.MaxInt64
+	co.Unlock()
+	c.Assert(co.WaitForQuiescence(200*time.Millisecond), NotNil)
+
+	// Paused schedulers are ignored.
+	c.Assert(co.pauseOrResumeScheduler("all", 60), IsNil)
+	c.Assert(co.WaitForQuiescence(10*time.Second), IsNil)
+}
+
+func (s *testCoordinatorSuite) TestQuietCyclesOfSkippedRounds(c *C) {
+	tc, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+
+	scheduler, err := schedule.CreateScheduler(schedulers.BalanceLeaderType, co.opController, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(schedulers.BalanceLeaderType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	mb := &mockBatchScheduler{Scheduler: scheduler}
+	sc := newScheduleController(co, mb)
+
+	// The rounds skipped in bootstrap mode are quiet.
+	tc.setBootstrapMode(true)
+	for i := 0; i < defaultNumQuietCycles; i++ {
+		c.Assert(co.scheduleRound(sc), IsTrue)
+	}
+	c.Assert(mb.calls, Equals, 0)
+	c.Assert(atomic.LoadInt64(&sc.quietCycles), Equals, int64(defaultNumQuietCycles))
+}
+
 func (s *testCoordinatorSuite) TestPersistScheduler(c *C) {
 	tc, co, cleanup := prepare(nil, nil, func(co *coordinator) { co.run() }, c)
 	hbStreams := co.hbStreams
@@ -825,6 +906,159 @@ func (s *testCoordinatorSuite) TestRemoveScheduler(c *C) {
 	co.wg.Wait()
 }
 
+func (s *testCoordinatorSuite) TestGetSchedulerType(c *C) {
+	tc, co, cleanup := prepare(nil, nil, func(co *coordinator) { co.run() }, c)
+	defer cleanup()
+	c.Assert(tc.addLeaderStore(1, 1), IsNil)
+
+	typ, err := co.GetSchedulerType(schedulers.BalanceLeaderName)
+	c.Assert(err, IsNil)
+	c.Assert(typ, Equals, schedulers.BalanceLeaderType)
+
+	gls, err := schedule.CreateScheduler(schedulers.GrantLeaderType, co.opController, tc.storage, schedule.ConfigSliceDecoder(schedulers.GrantLeaderType, []string{"1"}))
+	c.Assert(err, IsNil)
+	c.Assert(co.addScheduler(gls, "1"), IsNil)
+	typ, err = tc.GetSchedulerType(schedulers.GrantLeaderName)
+	c.Assert(err, IsNil)
+	c.Assert(typ, Equals, schedulers.GrantLeaderType)
+
+	_, err = co.GetSchedulerType("not-exist")
+	c.Assert(err, NotNil)
+}
+
+func (s *testCoordinatorSuite) TestGetSchedulerPauseTimeRemaining(c *C) {
+	_, co, cleanup := prepare(nil, nil, func(co *coordinator) { co.run() }, c)
+	defer cleanup()
+
+	remaining, err := co.GetSchedulerPauseTimeRemaining(schedulers.BalanceLeaderName)
+	c.Assert(err, IsNil)
+	c.Assert(remaining, Equals, time.Duration(0))
+	_, err = co.GetSchedulerPauseTimeRemaining("not-exist")
+	c.Assert(errors.ErrorEqual(err, errs.ErrSchedulerNotFound.FastGenByArgs()), IsTrue)
+
+	c.Assert(co.pauseOrResumeScheduler(schedulers.BalanceLeaderName, 1), IsNil)
+	// query it until the pause expires, the remaining time never goes negative
+	// or up, and it is 0 once the scheduler is not paused.
+	last := time.Second
+	for {
+		remaining, err = co.GetSchedulerPauseTimeRemaining(schedulers.BalanceLeaderName)
+		c.Assert(err, IsNil)
+		c.Assert(remaining >= 0 && remaining <= last, IsTrue)
+		last = remaining
+		if remaining == 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	paused, err := co.isSchedulerPaused(schedulers.BalanceLeaderName)
+	c.Assert(err, IsNil)
+	c.Assert(paused, IsFalse)
+}
+
+func (s *testCoordinatorSuite) TestGetSchedulerAllowedStatus(c *C) {
+	tc, co, cleanup := prepare(nil, nil, func(co *coordinator) { co.run() }, c)
+	defer cleanup()
+
+	status := co.GetSchedulerAllowedStatus()
+	c.Assert(status[schedulers.BalanceLeaderName], Equals, schedulerAllowed)
+	c.Assert(status[schedulers.BalanceRegionName], Equals, schedulerAllowed)
+
+	c.Assert(co.pauseOrResumeScheduler(schedulers.BalanceLeaderName, 60), IsNil)
+	cfg := tc.GetOpts().GetScheduleConfig().Clone()
+	cfg.RegionScheduleLimit = 0
+	tc.GetOpts().SetScheduleConfig(cfg)
+	status = co.GetSchedulerAllowedStatus()
+	c.Assert(status[schedulers.BalanceLeaderName], Equals, schedulerPausedStatus)
+	c.Assert(status[schedulers.BalanceRegionName], Equals, schedulerLimitedStatus)
+
+	// the bootstrap mode holds the schedulers which are not paused.
+	tc.setBootstrapMode(true)
+	status = co.GetSchedulerAllowedStatus()
+	c.Assert(status[schedulers.BalanceLeaderName], Equals, schedulerPausedStatus)
+	c.Assert(status[schedulers.BalanceRegionName], Equals, schedulerBootstrapStatus)
+	tc.setBootstrapMode(false)
+	status = co.GetSchedulerAllowedStatus()
+	c.Assert(status[schedulers.BalanceRegionName], Equals, schedulerLimitedStatus)
+}
+
+func (s *testCoordinatorSuite) TestPauseChecker(c *C) {
+	tc, co, cleanup := prepare(nil, nil, func(co *coordinator) { co.run() }, c)
+	defer cleanup()
+
+	c.Assert(tc.addRegionStore(1, 1), IsNil)
+	c.Assert(tc.addRegionStore(2, 2), IsNil)
+	c.Assert(tc.addRegionStore(3, 3), IsNil)
+	c.Assert(tc.addLeaderRegion(1, 2, 3), IsNil)
+	region := tc.GetRegion(1)
+	c.Assert(co.checkers.CheckRegion(region), HasLen, 1)
+
+	checkers := []string{"replica-checker", "rule-checker"}
+	for _, name := range checkers {
+		c.Assert(co.pauseOrResumeChecker(name, 60), IsNil)
+	}
+	c.Assert(co.checkers.CheckRegion(region), HasLen, 0)
+	err := co.pauseOrResumeChecker("not-exist", 60)
+	c.Assert(errors.ErrorEqual(err, errs.ErrCheckerNotFound.FastGenByArgs()), IsTrue)
+
+	// the pauses are restored by a new coordinator.
+	pauses, err := tc.storage.LoadCheckerPauseUntil()
+	c.Assert(err, IsNil)
+	c.Assert(pauses, HasLen, 2)
+	co2 := newCoordinator(s.ctx, tc.RaftCluster, co.hbStreams)
+	co2.loadCheckerPauses()
+	for _, name := range checkers {
+		pauseUntil, err := co2.checkers.GetCheckerPauseUntil(name)
+		c.Assert(err, IsNil)
+		c.Assert(pauseUntil, Equals, pauses[name])
+	}
+	c.Assert(co2.checkers.CheckRegion(region), HasLen, 0)
+
+	for _, name := range checkers {
+		c.Assert(co.pauseOrResumeChecker(name, 0), IsNil)
+	}
+	c.Assert(co.checkers.CheckRegion(region), HasLen, 1)
+	pauses, err = tc.storage.LoadCheckerPauseUntil()
+	c.Assert(err, IsNil)
+	c.Assert(pauses, DeepEquals, map[string]int64{"replica-checker": 0, "rule-checker": 0})
+}
+
+func (s *testCoordinatorSuite) TestSuspectScanRegionLimit(c *C) {
+	c.Assert(suspectScanRegionLimit(0), Equals, minSuspectScanRegionLimit)
+	c.Assert(suspectScanRegionLimit(64000), Equals, 64)
+	c.Assert(suspectScanRegionLimit(100000), Equals, 100)
+	c.Assert(suspectScanRegionLimit(10000000), Equals, maxSuspectScanRegionLimit)
+}
+
+func (s *testCoordinatorSuite) TestAddJitter(c *C) {
+	c.Assert(addJitter(time.Second, 0), Equals, time.Second)
+	for i := 0; i < 100; i++ {
+		interval := addJitter(time.Second, 100*time.Millisecond)
+		c.Assert(interval >= time.Second, IsTrue)
+		c.Assert(interval < time.Second+100*time.Millisecond, IsTrue)
+	}
+}
+
+func (s *testCoordinatorSuite) TestValidateScheduler(c *C) {
+	tc, co, cleanup := prepare(nil, nil, func(co *coordinator) { co.run() }, c)
+	defer cleanup()
+	c.Assert(tc.addLeaderStore(1, 1), IsNil)
+	schedulerCount := len(co.schedulers)
+
+	c.Assert(tc.ValidateScheduler(schedulers.GrantLeaderType, []string{"1"}), IsNil)
+	// the scheduler is neither added, prepared nor persisted.
+	c.Assert(co.schedulers, HasLen, schedulerCount)
+	data, err := tc.storage.LoadScheduleConfig(schedulers.GrantLeaderName)
+	c.Assert(err, IsNil)
+	c.Assert(data, Equals, "")
+	c.Assert(tc.GetStore(1).AllowLeaderTransfer(), IsTrue)
+
+	// failed to decode the arguments.
+	c.Assert(co.ValidateScheduler(schedulers.GrantLeaderType, []string{"abc"}), NotNil)
+	c.Assert(co.ValidateScheduler("not-exist", nil), NotNil)
+	err = co.ValidateScheduler(schedulers.BalanceLeaderType, nil)
+	c.Assert(errors.ErrorEqual(err, errs.ErrSchedulerExisted.FastGenByArgs()), IsTrue)
+}
+
 func (s *testCoordinatorSuite) TestRestart(c *C) {
 	tc, co, cleanup := prepare(func(cfg *config.ScheduleConfig) {
 		// Turn off balance, we test add replica only.
@@ -1077,6 +1311,8 @@ func (s *testScheduleControllerSuite) TestController(c *C) {
 	}
 
 	sc := newScheduleController(co, lb)
+	sc.RetryBaseDelay = 0
+	sc.MaxConsecutiveNoOps = 1
 
 	for i := schedulers.MinScheduleInterval; sc.GetInterval() != schedulers.MaxScheduleInterval; i = sc.GetNextInterval(i) {
 		c.Assert(sc.GetInterval(), Equals, i)
@@ -1155,6 +1391,8 @@ func (s *testScheduleControllerSuite) TestInterval(c *C) {
 	lb, err := schedule.CreateScheduler(schedulers.BalanceLeaderType, co.opController, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(schedulers.BalanceLeaderType, []string{"", ""}))
 	c.Assert(err, IsNil)
 	sc := newScheduleController(co, lb)
+	sc.RetryBaseDelay = 0
+	sc.MaxConsecutiveNoOps = 1
 
 	// If no operator for x seconds, the next check should be in x/2 seconds.
 	idleSeconds := []int{5, 10, 20, 30, 60}
@@ -1167,6 +1405,267 @@ func (s *testScheduleControllerSuite) TestInterval(c *C) {
 	}
 }
 
+func (s *testScheduleControllerSuite) TestMaxConsecutiveNoOps(c *C) {
+	_, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+
+	scheduler, err := schedule.CreateScheduler(schedulers.BalanceLeaderType, co.opController, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(schedulers.BalanceLeaderType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	mb := &mockBatchScheduler{Scheduler: scheduler}
+	sc := newScheduleController(co, mb)
+	sc.RetryBaseDelay = 0
+	sc.MaxConsecutiveNoOps = 3
+	c.Assert(sc.GetInterval(), Equals, schedulers.MinScheduleInterval)
+
+	// the interval increases since the 3rd round without operators.
+	c.Assert(sc.Schedule(), IsNil)
+	c.Assert(sc.Schedule(), IsNil)
+	c.Assert(sc.GetInterval(), Equals, schedulers.MinScheduleInterval)
+	c.Assert(sc.Schedule(), IsNil)
+	next := sc.GetNextInterval(schedulers.MinScheduleInterval)
+	c.Assert(sc.GetInterval(), Equals, next)
+	c.Assert(sc.Schedule(), IsNil)
+	c.Assert(sc.GetInterval(), Equals, sc.GetNextInterval(next))
+
+	// finding work resets the interval and the count.
+	mb.ops = []*operator.Operator{newTestOperator(1, &metapb.RegionEpoch{}, operator.OpLeader)}
+	c.Assert(sc.Schedule(), HasLen, 1)
+	c.Assert(sc.GetInterval(), Equals, schedulers.MinScheduleInterval)
+	mb.ops = nil
+	c.Assert(sc.Schedule(), IsNil)
+	c.Assert(sc.Schedule(), IsNil)
+	c.Assert(sc.GetInterval(), Equals, schedulers.MinScheduleInterval)
+}
+
+type mockBatchScheduler struct {
+	schedule.Scheduler
+	ops   []*operator.Operator
+	calls int
+}
+
+func (s *mockBatchScheduler) Schedule(cluster opt.Cluster) []*operator.Operator {
+	s.calls++
+	return s.ops
+}
+
+func (s *testScheduleControllerSuite) TestMaxOperatorsPerScheduleCall(c *C) {
+	_, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+
+	scheduler, err := schedule.CreateScheduler(schedulers.BalanceLeaderType, co.opController, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(schedulers.BalanceLeaderType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	mb := &mockBatchScheduler{Scheduler: scheduler}
+	for i := uint64(1); i <= 25; i++ {
+		mb.ops = append(mb.ops, newTestOperator(i, &metapb.RegionEpoch{}, operator.OpLeader))
+	}
+	sc := newScheduleController(co, mb)
+	c.Assert(sc.MaxOperatorsPerScheduleCall, Equals, defaultMaxOperatorsPerScheduleCall)
+
+	// The rest operators are submitted in the next rounds without scheduling again.
+	c.Assert(sc.nextOperators(), DeepEquals, mb.ops[:10])
+	c.Assert(sc.nextOperators(), DeepEquals, mb.ops[10:20])
+	c.Assert(sc.nextOperators(), DeepEquals, mb.ops[20:])
+	c.Assert(mb.calls, Equals, 1)
+	c.Assert(sc.nextOperators(), HasLen, 10)
+	c.Assert(mb.calls, Equals, 2)
+
+	// 0 means no limit.
+	sc.pendingOps = nil
+	sc.MaxOperatorsPerScheduleCall = 0
+	c.Assert(sc.nextOperators(), HasLen, 25)
+	c.Assert(mb.calls, Equals, 3)
+}
+
+func (s *testScheduleControllerSuite) TestRetryBackoff(c *C) {
+	_, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+
+	scheduler, err := schedule.CreateScheduler(schedulers.BalanceLeaderType, co.opController, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(schedulers.BalanceLeaderType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	mb := &mockBatchScheduler{Scheduler: scheduler}
+	sc := newScheduleController(co, mb)
+	sc.RetryBaseDelay = time.Millisecond
+	sc.RetryMaxDelay = 4 * time.Millisecond
+	c.Assert(sc.GetRetryDelay(), Equals, time.Duration(0))
+
+	// The delay doubles after each failed retry and is bounded by the max delay.
+	start := time.Now()
+	c.Assert(sc.Schedule(), IsNil)
+	c.Assert(mb.calls, Equals, maxScheduleRetries)
+	c.Assert(time.Since(start) >= (1+2+4*7)*time.Millisecond, IsTrue)
+	c.Assert(sc.GetRetryDelay(), Equals, 4*time.Millisecond)
+
+	// The delay is reset once the scheduler generates operators.
+	mb.ops = []*operator.Operator{newTestOperator(1, &metapb.RegionEpoch{}, operator.OpLeader)}
+	c.Assert(sc.Schedule(), HasLen, 1)
+	c.Assert(sc.GetRetryDelay(), Equals, time.Duration(0))
+
+	// Stop retrying once the scheduler is stopped.
+	mb.ops, mb.calls = nil, 0
+	c.Assert(sc.Schedule(), IsNil)
+	c.Assert(sc.GetRetryDelay(), Equals, 4*time.Millisecond)
+	mb.calls = 0
+	sc.RetryBaseDelay, sc.RetryMaxDelay = time.Hour, time.Hour
+	sc.Stop()
+	c.Assert(sc.Schedule(), IsNil)
+	c.Assert(mb.calls, Equals, 1)
+	// The round starts from the base delay rather than the delay left by the last round.
+	c.Assert(sc.GetRetryDelay(), Equals, time.Hour)
+}
+
+func (s *testScheduleControllerSuite) TestStealOperators(c *C) {
+	_, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+
+	scheduler, err := schedule.CreateScheduler(schedulers.BalanceLeaderType, co.opController, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(schedulers.BalanceLeaderType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	busy := &mockBatchScheduler{Scheduler: scheduler}
+	for i := uint64(1); i <= 25; i++ {
+		busy.ops = append(busy.ops, newTestOperator(i, &metapb.RegionEpoch{}, operator.OpLeader))
+	}
+	busySc := newScheduleController(co, busy)
+	idle := &mockBatchScheduler{Scheduler: scheduler}
+	idleSc := newScheduleController(co, idle)
+	co.schedulers["busy"] = busySc
+	co.schedulers["idle"] = idleSc
+
+	// nothing is generated before the busy scheduler has a backlog.
+	c.Assert(co.stealOperators(idleSc), HasLen, 0)
+	c.Assert(busy.calls, Equals, 0)
+
+	// the operators of the busy scheduler's type are generated, and no more than
+	// the limit of the idle scheduler are returned.
+	c.Assert(busySc.nextOperators(), DeepEquals, busy.ops[:10])
+	c.Assert(busy.calls, Equals, 1)
+	c.Assert(co.stealOperators(idleSc), DeepEquals, busy.ops[:10])
+	c.Assert(busy.calls, Equals, 2)
+	c.Assert(idle.calls, Equals, 0)
+	idleSc.MaxOperatorsPerScheduleCall = 3
+	c.Assert(co.stealOperators(idleSc), DeepEquals, busy.ops[:3])
+	// the pending operators of the busy scheduler are kept.
+	c.Assert(busySc.busyBacklog(), Equals, 5)
+	c.Assert(busySc.nextOperators(), DeepEquals, busy.ops[10:20])
+
+	// the paused scheduler is skipped.
+	busySc.pendingOps = append([]*operator.Operator(nil), busy.ops...)
+	c.Assert(co.pauseOrResumeScheduler("busy", 60), IsNil)
+	busy.calls = 0
+	c.Assert(co.stealOperators(idleSc), HasLen, 0)
+	c.Assert(busy.calls, Equals, 0)
+}
+
+func (s *testScheduleControllerSuite) TestStealOperatorsInScheduleRound(c *C) {
+	_, co, cleanup := prepare(func(cfg *config.ScheduleConfig) {
+		cfg.EnableWorkStealing = true
+	}, nil, nil, c)
+	defer cleanup()
+
+	scheduler, err := schedule.CreateScheduler(schedulers.BalanceLeaderType, co.opController, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(schedulers.BalanceLeaderType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	busy := &mockBatchScheduler{Scheduler: scheduler}
+	for i := uint64(1); i <= 25; i++ {
+		busy.ops = append(busy.ops, newTestOperator(i, &metapb.RegionEpoch{}, operator.OpLeader))
+	}
+	busySc := newScheduleController(co, busy)
+	idle := &mockBatchScheduler{Scheduler: scheduler}
+	idleSc := newScheduleController(co, idle)
+	idleSc.RetryBaseDelay = 0
+	co.schedulers["busy"] = busySc
+	co.schedulers["idle"] = idleSc
+	c.Assert(busySc.nextOperators(), HasLen, 10)
+
+	// the idle scheduler steals only after workStealingIdleCycles quiet rounds.
+	for i := 1; i < workStealingIdleCycles; i++ {
+		c.Assert(co.scheduleRound(idleSc), IsTrue)
+		c.Assert(busy.calls, Equals, 1)
+	}
+	c.Assert(co.scheduleRound(idleSc), IsTrue)
+	c.Assert(busy.calls, Equals, 2)
+	c.Assert(busySc.busyBacklog(), Equals, 5)
+	// the generated operators are not counted in the stats of the idle scheduler.
+	stats := idleSc.GetStats()
+	c.Assert(stats.RunCount, Equals, uint64(workStealingIdleCycles))
+	c.Assert(stats.NoOpCount, Equals, uint64(workStealingIdleCycles))
+
+	// nothing is generated when work stealing is disabled.
+	cfg := co.cluster.opt.GetScheduleConfig().Clone()
+	cfg.EnableWorkStealing = false
+	co.cluster.opt.SetScheduleConfig(cfg)
+	c.Assert(co.scheduleRound(idleSc), IsTrue)
+	c.Assert(busy.calls, Equals, 2)
+}
+
+func (s *testScheduleControllerSuite) TestSchedulerStats(c *C) {
+	_, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+
+	scheduler, err := schedule.CreateScheduler(schedulers.BalanceLeaderType, co.opController, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(schedulers.BalanceLeaderType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	mb := &mockBatchScheduler{Scheduler: scheduler}
+	for i := uint64(1); i <= 4; i++ {
+		mb.ops = append(mb.ops, newTestOperator(i, &metapb.RegionEpoch{}, operator.OpLeader))
+	}
+	sc := newScheduleController(co, mb)
+	sc.RetryBaseDelay, sc.RetryMaxDelay = 0, 0
+	co.schedulers[sc.GetName()] = sc
+	c.Assert(sc.GetStats(), DeepEquals, SchedulerStats{})
+
+	c.Assert(co.scheduleRound(sc), IsTrue)
+	mb.ops = nil
+	c.Assert(co.scheduleRound(sc), IsTrue)
+	stats := co.getSchedulerStats()[sc.GetName()]
+	c.Assert(stats.RunCount, Equals, uint64(2))
+	c.Assert(stats.NoOpCount, Equals, uint64(1))
+	c.Assert(stats.NoOpRate, Equals, 0.5)
+	c.Assert(stats.AvgOperatorsPerRun, Equals, 2.0)
+	c.Assert(stats.LastRunTime.IsZero(), IsFalse)
+
+	// the rounds in which the scheduler is paused are not counted.
+	c.Assert(co.pauseOrResumeScheduler(sc.GetName(), 60), IsNil)
+	c.Assert(co.scheduleRound(sc), IsTrue)
+	c.Assert(sc.GetStats().RunCount, Equals, uint64(2))
+}
+
+type mockPanicScheduler struct {
+	schedule.Scheduler
+}
+
+func (s *mockPanicScheduler) IsScheduleAllowed(cluster opt.Cluster) bool {
+	return true
+}
+
+func (s *mockPanicScheduler) Schedule(cluster opt.Cluster) []*operator.Operator {
+	panic("mock panic")
+}
+
+func (s *testScheduleControllerSuite) TestSchedulerPanic(c *C) {
+	_, co, cleanup := prepare(func(cfg *config.ScheduleConfig) {
+		cfg.MaxSchedulerPanics = 2
+	}, nil, nil, c)
+	defer cleanup()
+
+	scheduler, err := schedule.CreateScheduler(schedulers.BalanceLeaderType, co.opController, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(schedulers.BalanceLeaderType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	sc := newScheduleController(co, &mockPanicScheduler{Scheduler: scheduler})
+	co.schedulers[sc.GetName()] = sc
+
+	// the panics are recovered until there are more than 2 in the window.
+	c.Assert(co.scheduleRound(sc), IsTrue)
+	c.Assert(co.scheduleRound(sc), IsTrue)
+	c.Assert(co.scheduleRound(sc), IsFalse)
+	c.Assert(co.schedulers, Not(HasKey), sc.GetName())
+	c.Assert(sc.Ctx().Err(), NotNil)
+
+	// the panics out of the window are not counted.
+	now := time.Now()
+	sc = newScheduleController(co, scheduler)
+	c.Assert(sc.recordPanic(now, time.Minute, 2), IsFalse)
+	c.Assert(sc.recordPanic(now.Add(30*time.Second), time.Minute, 2), IsFalse)
+	c.Assert(sc.recordPanic(now.Add(90*time.Second), time.Minute, 2), IsFalse)
+	c.Assert(sc.panicTimes, HasLen, 2)
+	c.Assert(sc.recordPanic(now.Add(100*time.Second), time.Minute, 2), IsTrue)
+}
+
 func waitAddLearner(c *C, stream mockhbstream.HeartbeatStream, region *core.RegionInfo, storeID uint64) *core.RegionInfo {
 	var res *pdpb.RegionHeartbeatResponse
 	testutil.WaitUntil(c, func(c *C) bool {
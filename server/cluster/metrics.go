@@ -56,6 +56,14 @@ var (
 			Help:      "Time spent of patrol checks region.",
 		})
 
+	ruleCheckerOrphanPeersGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "checker",
+			Name:      "rule_checker_orphan_peers",
+			Help:      "Number of orphan peers found in the last patrol round.",
+		})
+
 	clusterStateCPUGauge = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Namespace: "pd",
@@ -78,6 +86,38 @@ var (
 			Name:      "region_waiting_list",
 			Help:      "Number of region in waiting list",
 		})
+
+	suspectRegionsEvictedCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "checker",
+			Name:      "suspect_regions_evicted_total",
+			Help:      "Counter of the suspect regions evicted because there are too many.",
+		})
+
+	bootstrapModeGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Name:      "bootstrap_mode_active",
+			Help:      "Whether the schedulers are suspended because the cluster is bootstrapping.",
+		})
+
+	schedulerPanicCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "scheduler",
+			Name:      "panics_total",
+			Help:      "Counter of the recovered panics of the schedulers and the removals caused by them.",
+		}, []string{"type", "event"})
+
+	schedulerLockWaitHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "pd",
+			Subsystem: "scheduler",
+			Name:      "lock_wait_duration_seconds",
+			Help:      "Bucketed histogram of time (s) waiting for the coordinator lock of scheduler management.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 16),
+		}, []string{"type"})
 )
 
 func init() {
@@ -86,7 +126,12 @@ func init() {
 	prometheus.MustRegister(schedulerStatusGauge)
 	prometheus.MustRegister(hotSpotStatusGauge)
 	prometheus.MustRegister(patrolCheckRegionsGauge)
+	prometheus.MustRegister(ruleCheckerOrphanPeersGauge)
 	prometheus.MustRegister(clusterStateCPUGauge)
 	prometheus.MustRegister(clusterStateCurrent)
 	prometheus.MustRegister(regionWaitingListGauge)
+	prometheus.MustRegister(schedulerLockWaitHistogram)
+	prometheus.MustRegister(schedulerPanicCounter)
+	prometheus.MustRegister(bootstrapModeGauge)
+	prometheus.MustRegister(suspectRegionsEvictedCounter)
 }
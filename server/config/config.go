@@ -162,6 +162,8 @@ type Config struct {
 	Dashboard DashboardConfig `toml:"dashboard" json:"dashboard"`
 
 	ReplicationMode ReplicationModeConfig `toml:"replication-mode" json:"replication-mode"`
+
+	RemotePlugin RemotePluginConfig `toml:"remote-plugin" json:"remote-plugin"`
 }
 
 // NewConfig creates a new config.
@@ -204,6 +206,7 @@ const (
 	defaultCompactionMode          = "periodic"
 	defaultAutoCompactionRetention = "1h"
 	defaultQuotaBackendBytes       = typeutil.ByteSize(8 * 1024 * 1024 * 1024) // 8GB
+	defaultMaxRemotePluginSize     = typeutil.ByteSize(128 * 1024 * 1024)      // 128MiB
 
 	defaultName                = "pd"
 	defaultClientUrls          = "http://127.0.0.1:2379"
@@ -581,6 +584,8 @@ func (c *Config) Adjust(meta *toml.MetaData, reloading bool) error {
 
 	c.ReplicationMode.adjust(configMetaData.Child("replication-mode"))
 
+	c.RemotePlugin.adjust(configMetaData.Child("remote-plugin"))
+
 	c.Security.Encryption.Adjust()
 
 	return nil
@@ -618,6 +623,25 @@ type ScheduleConfig struct {
 	// it will never be used as a source or target store.
 	MaxSnapshotCount    uint64 `toml:"max-snapshot-count" json:"max-snapshot-count"`
 	MaxPendingPeerCount uint64 `toml:"max-pending-peer-count" json:"max-pending-peer-count"`
+	// MaxOrphanPeerFixesPerCycle is the max number of orphan peers the rule
+	// checker removes in one round of patrol. 0 means no limit.
+	MaxOrphanPeerFixesPerCycle uint64 `toml:"max-orphan-peer-fixes-per-cycle" json:"max-orphan-peer-fixes-per-cycle"`
+	// PeerRoleConversionCooldown is the min interval for the rule checker to convert
+	// the role of the same peer again. 0 means no cooldown.
+	PeerRoleConversionCooldown typeutil.Duration `toml:"peer-role-conversion-cooldown" json:"peer-role-conversion-cooldown"`
+	// PendingPeerGracePeriod is how long a peer can be pending before the rule
+	// checker treats it as unhealthy. 0 means a pending peer is always unhealthy.
+	PendingPeerGracePeriod typeutil.Duration `toml:"pending-peer-grace-period" json:"pending-peer-grace-period"`
+	// MaxOperatorWaitTime is the max running time of an operator which moves regions, after
+	// which the operator is considered timeout and removed, so a stuck operator cannot hold
+	// the region forever.
+	MaxOperatorWaitTime typeutil.Duration `toml:"max-operator-wait-time" json:"max-operator-wait-time"`
+	// OperatorSurgeThresholdRatio is the ratio of the operator generation rate to its rolling
+	// average above which the rate is regarded as a surge.
+	OperatorSurgeThresholdRatio float64 `toml:"operator-surge-threshold-ratio" json:"operator-surge-threshold-ratio"`
+	// OperatorSurgeWindow is the duration a surge of the operator generation needs to last
+	// before it is reported.
+	OperatorSurgeWindow typeutil.Duration `toml:"operator-surge-window" json:"operator-surge-window"`
 	// If both the size of region is smaller than MaxMergeRegionSize
 	// and the number of rows in region is smaller than MaxMergeRegionKeys,
 	// it will try to merge with adjacent regions.
@@ -632,9 +656,36 @@ type ScheduleConfig struct {
 	EnableCrossTableMerge bool `toml:"enable-cross-table-merge" json:"enable-cross-table-merge,string"`
 	// PatrolRegionInterval is the interval for scanning region during patrol.
 	PatrolRegionInterval typeutil.Duration `toml:"patrol-region-interval" json:"patrol-region-interval"`
+	// PatrolRegionIntervalJitter is the max random duration added to PatrolRegionInterval,
+	// so the patrol load is distributed more evenly. 0 means no jitter.
+	PatrolRegionIntervalJitter typeutil.Duration `toml:"patrol-region-interval-jitter" json:"patrol-region-interval-jitter"`
+	// PatrolRegionBatchSize is the number of regions scanned in each patrol.
+	PatrolRegionBatchSize int `toml:"patrol-region-batch-size" json:"patrol-region-batch-size"`
+	// MinRegionsForScheduling is the number of regions below which the cluster is considered
+	// to be bootstrapping, and the schedulers skip their rounds until it grows larger.
+	MinRegionsForScheduling uint64 `toml:"min-regions-for-scheduling" json:"min-regions-for-scheduling"`
+	// MaxSuspectRegions is the max number of suspect regions waiting to be checked, the
+	// ones added earliest are evicted once it is exceeded by 10%. 0 means no limit.
+	MaxSuspectRegions uint64 `toml:"max-suspect-regions" json:"max-suspect-regions"`
+	// OperatorPoolSize is the capacity of the operator slices reused by the operator controller
+	// when promoting the waiting operators. 0 means not reusing them.
+	OperatorPoolSize int `toml:"operator-pool-size" json:"operator-pool-size"`
+	// MaxSchedulerPanics is the max number of panics of a scheduler within PanicWindowDuration.
+	// The panics are recovered, and the scheduler is removed once it panics more.
+	// 0 means the panics are not recovered.
+	MaxSchedulerPanics uint64 `toml:"max-scheduler-panics" json:"max-scheduler-panics"`
+	// PanicWindowDuration is the duration in which the panics of a scheduler are counted.
+	PanicWindowDuration typeutil.Duration `toml:"panic-window-duration" json:"panic-window-duration"`
+	// PluginHealthCheckInterval is the interval to check if the loaded scheduler plugins
+	// are still usable. The scheduler of an unhealthy plugin is unloaded.
+	PluginHealthCheckInterval typeutil.Duration `toml:"plugin-health-check-interval" json:"plugin-health-check-interval"`
 	// MaxStoreDownTime is the max duration after which
 	// a store will be considered to be down if it hasn't reported heartbeats.
 	MaxStoreDownTime typeutil.Duration `toml:"max-store-down-time" json:"max-store-down-time"`
+	// StoreRemovalConfirmationTimeout is the duration in which a store removal requested
+	// by the API needs to be confirmed, otherwise it is abandoned. 0 means the store is
+	// removed without confirmation.
+	StoreRemovalConfirmationTimeout typeutil.Duration `toml:"store-removal-confirmation-timeout" json:"store-removal-confirmation-timeout"`
 	// LeaderScheduleLimit is the max coexist leader schedules.
 	LeaderScheduleLimit uint64 `toml:"leader-schedule-limit" json:"leader-schedule-limit"`
 	// LeaderSchedulePolicy is the option to balance leader, there are some policies supported: ["count", "size"], default: "count"
@@ -647,10 +698,40 @@ type ScheduleConfig struct {
 	MergeScheduleLimit uint64 `toml:"merge-schedule-limit" json:"merge-schedule-limit"`
 	// HotRegionScheduleLimit is the max coexist hot region schedules.
 	HotRegionScheduleLimit uint64 `toml:"hot-region-schedule-limit" json:"hot-region-schedule-limit"`
+	// HotRegionScheduleLimitPerStore scales the hot region schedule limit with the cluster size.
+	// The effective limit is the larger one of HotRegionScheduleLimit and
+	// HotRegionScheduleLimitPerStore multiplied by the store count. It is not applied
+	// when HotRegionScheduleLimit is 0, which disables the hot region scheduling.
+	HotRegionScheduleLimitPerStore float64 `toml:"hot-region-schedule-limit-per-store" json:"hot-region-schedule-limit-per-store"`
+	// EnableWorkStealing is the option to let an idle scheduler generate the
+	// operators of a busy scheduler's type, so the work can be done faster. A
+	// scheduler is busy if it has more pending operators than it can submit in one round.
+	EnableWorkStealing bool `toml:"enable-work-stealing" json:"enable-work-stealing,string"`
 	// HotRegionCacheHitThreshold is the cache hits threshold of the hot region.
 	// If the number of times a region hits the hot cache is greater than this
 	// threshold, it is considered a hot region.
 	HotRegionCacheHitsThreshold uint64 `toml:"hot-region-cache-hits-threshold" json:"hot-region-cache-hits-threshold"`
+	// HotRegionCooldownDuration is the duration after a hot region is moved by the hot region
+	// scheduler, during which it is not scheduled again in the same read or write direction.
+	HotRegionCooldownDuration typeutil.Duration `toml:"hot-region-cooldown-duration" json:"hot-region-cooldown-duration"`
+	// ScatterPartitionLabel is the label dividing the stores into partitions. If it is set, the
+	// region scatterer keeps each replica within the partition of its current store, and only
+	// crosses partitions when the placement rules or the lack of stores force it to.
+	ScatterPartitionLabel string `toml:"scatter-partition-label" json:"scatter-partition-label"`
+	// WriteAmplificationFactor is multiplied into the byte rate of the pending influence of
+	// moving a hot write peer, to model the disk cost caused by the compaction of RocksDB.
+	// Users running heavy write workloads should set it to their measured write amplification.
+	WriteAmplificationFactor float64 `toml:"write-amplification-factor" json:"write-amplification-factor"`
+	// HeartbeatDenoiseWindowSize is the min interval in seconds of a flow sample of the hot cache.
+	// The region heartbeats with shorter intervals are accumulated until the window is filled.
+	HeartbeatDenoiseWindowSize uint64 `toml:"heartbeat-denoise-window-size" json:"heartbeat-denoise-window-size"`
+	// HotThresholdRecalibrationInterval is the min interval between two calculations of the
+	// hot thresholds of a store. 0 means calculating them on every region heartbeat.
+	HotThresholdRecalibrationInterval typeutil.Duration `toml:"hot-threshold-recalibration-interval" json:"hot-threshold-recalibration-interval"`
+	// HotCheckSamplingRate is the ratio of the region heartbeats of the cold regions checked
+	// by the hot cache, which is in [0, 1]. A region is cold if it has no hot peer and its
+	// byte rate is below the min hot threshold. The others are always checked.
+	HotCheckSamplingRate float64 `toml:"hot-check-sampling-rate" json:"hot-check-sampling-rate"`
 	// StoreBalanceRate is the maximum of balance rate for each store.
 	// WARN: StoreBalanceRate is deprecated.
 	StoreBalanceRate float64 `toml:"store-balance-rate" json:"store-balance-rate,omitempty"`
@@ -658,6 +739,17 @@ type ScheduleConfig struct {
 	StoreLimit map[uint64]StoreLimitConfig `toml:"store-limit" json:"store-limit"`
 	// TolerantSizeRatio is the ratio of buffer size for balance scheduler.
 	TolerantSizeRatio float64 `toml:"tolerant-size-ratio" json:"tolerant-size-ratio"`
+	// BalanceHysteresisRatio is the noise band of the balance schedulers. When it is positive,
+	// a region or leader is moved only if the source score is larger than the target score
+	// multiplied by (1 + BalanceHysteresisRatio).
+	BalanceHysteresisRatio float64 `toml:"balance-hysteresis-ratio" json:"balance-hysteresis-ratio"`
+	// StoreLabelWeights is used to normalize the region scores of the stores on heterogeneous
+	// hardware. The key is a store label in the form of "key=value", e.g. "tier=hdd". The
+	// region score of a store is multiplied by the weights of all its matched labels.
+	StoreLabelWeights map[string]float64 `toml:"store-label-weights" json:"store-label-weights"`
+	// PreferredLeaderLabels restricts the leaders transferred by the rule checker to the
+	// stores matching all the labels, e.g. {"zone": "us-east"}. Empty means no restriction.
+	PreferredLeaderLabels map[string]string `toml:"preferred-leader-labels" json:"preferred-leader-labels"`
 	//
 	//      high space stage         transition stage           low space stage
 	//   |--------------------|-----------------------------|-------------------------|
@@ -670,10 +762,25 @@ type ScheduleConfig struct {
 	// HighSpaceRatio is the highest usage ratio of store which regraded as high space.
 	// High space means there is a lot of spare capacity, and store region score varies directly with used size.
 	HighSpaceRatio float64 `toml:"high-space-ratio" json:"high-space-ratio"`
+	// MinAvailableStorageBytes is the hard floor of the available space of a store.
+	// A store whose available space is below it will never be the target of adding peers.
+	MinAvailableStorageBytes typeutil.ByteSize `toml:"min-available-storage-bytes" json:"min-available-storage-bytes"`
+	// MaxRegionsPerStore is the max number of regions of a store. A store which reaches it
+	// will not be selected to add replicas, no matter how much space it has. 0 means no limit.
+	MaxRegionsPerStore uint64 `toml:"max-regions-per-store" json:"max-regions-per-store"`
+	// CompactionOverheadRatio is the estimated ratio of the compaction writes in the
+	// write bandwidth reported by stores. The key is the engine type, e.g. "tikv" or "tiflash".
+	CompactionOverheadRatio map[string]float64 `toml:"compaction-overhead-ratio" json:"compaction-overhead-ratio"`
 	// RegionScoreFormulaVersion is used to control the formula used to calculate region score.
 	RegionScoreFormulaVersion string `toml:"region-score-formula-version" json:"region-score-formula-version"`
 	// SchedulerMaxWaitingOperator is the max coexist operators for each scheduler.
 	SchedulerMaxWaitingOperator uint64 `toml:"scheduler-max-waiting-operator" json:"scheduler-max-waiting-operator"`
+	// MaxTotalOperators is the max number of the running and waiting operators
+	// in the whole cluster. 0 means no limit.
+	MaxTotalOperators uint64 `toml:"max-total-operators" json:"max-total-operators"`
+	// MaxWaitingOperatorsPerKind is the max number of the waiting operators of each
+	// kind, e.g. "merge" or "replica". A kind which is not set has no limit.
+	MaxWaitingOperatorsPerKind map[string]uint64 `toml:"max-waiting-operators-per-kind" json:"max-waiting-operators-per-kind"`
 	// WARN: DisableLearner is deprecated.
 	// DisableLearner is the option to disable using AddLearnerNode instead of AddNode.
 	DisableLearner bool `toml:"disable-raft-learner" json:"disable-raft-learner,string,omitempty"`
@@ -738,8 +845,40 @@ func (c *ScheduleConfig) Clone() *ScheduleConfig {
 			storeLimit[k] = v
 		}
 	}
+	var compactionOverheadRatio map[string]float64
+	if c.CompactionOverheadRatio != nil {
+		compactionOverheadRatio = make(map[string]float64, len(c.CompactionOverheadRatio))
+		for k, v := range c.CompactionOverheadRatio {
+			compactionOverheadRatio[k] = v
+		}
+	}
+	var storeLabelWeights map[string]float64
+	if c.StoreLabelWeights != nil {
+		storeLabelWeights = make(map[string]float64, len(c.StoreLabelWeights))
+		for k, v := range c.StoreLabelWeights {
+			storeLabelWeights[k] = v
+		}
+	}
+	var preferredLeaderLabels map[string]string
+	if c.PreferredLeaderLabels != nil {
+		preferredLeaderLabels = make(map[string]string, len(c.PreferredLeaderLabels))
+		for k, v := range c.PreferredLeaderLabels {
+			preferredLeaderLabels[k] = v
+		}
+	}
+	var maxWaitingOperatorsPerKind map[string]uint64
+	if c.MaxWaitingOperatorsPerKind != nil {
+		maxWaitingOperatorsPerKind = make(map[string]uint64, len(c.MaxWaitingOperatorsPerKind))
+		for k, v := range c.MaxWaitingOperatorsPerKind {
+			maxWaitingOperatorsPerKind[k] = v
+		}
+	}
 	cfg := *c
 	cfg.StoreLimit = storeLimit
+	cfg.CompactionOverheadRatio = compactionOverheadRatio
+	cfg.StoreLabelWeights = storeLabelWeights
+	cfg.PreferredLeaderLabels = preferredLeaderLabels
+	cfg.MaxWaitingOperatorsPerKind = maxWaitingOperatorsPerKind
 	cfg.Schedulers = schedulers
 	cfg.SchedulersPayload = nil
 	return &cfg
@@ -749,6 +888,7 @@ const (
 	defaultMaxReplicas               = 3
 	defaultMaxSnapshotCount          = 3
 	defaultMaxPendingPeerCount       = 16
+	defaultMaxOrphanPeerFixes        = 10
 	defaultMaxMergeRegionSize        = 20
 	defaultMaxMergeRegionKeys        = 200000
 	defaultSplitMergeInterval        = 1 * time.Hour
@@ -762,15 +902,47 @@ const (
 	defaultTolerantSizeRatio         = 0
 	defaultLowSpaceRatio             = 0.8
 	defaultHighSpaceRatio            = 0.7
+	defaultMinAvailableStorageBytes  = typeutil.ByteSize(5 * 1024 * 1024 * 1024) // 5GiB
 	defaultRegionScoreFormulaVersion = "v2"
 	// defaultHotRegionCacheHitsThreshold is the low hit number threshold of the
 	// hot region.
 	defaultHotRegionCacheHitsThreshold = 3
+	// defaultHeartbeatDenoiseWindowSize is the same as statistics.HotRegionReportMinInterval.
+	defaultHeartbeatDenoiseWindowSize  = 3
 	defaultSchedulerMaxWaitingOperator = 5
 	defaultLeaderSchedulePolicy        = "count"
 	defaultStoreLimitMode              = "manual"
 	defaultEnableJointConsensus        = true
 	defaultEnableCrossTableMerge       = true
+	// defaultHotRegionScheduleLimitPerStore makes the hot region schedule limit
+	// larger than the default one when there are more than 40 stores.
+	defaultHotRegionScheduleLimitPerStore = 0.1
+	defaultPeerRoleConversionCooldown     = 30 * time.Second
+	defaultMaxTotalOperators              = 512
+	defaultPendingPeerGracePeriod         = 5 * time.Minute
+	defaultHotRegionCooldownDuration      = 30 * time.Second
+	defaultWriteAmplificationFactor       = 1.0
+	defaultHotCheckSamplingRate           = 1.0
+	// defaultMaxOperatorWaitTime is the same as operator.SlowOperatorWaitTime.
+	defaultMaxOperatorWaitTime = 10 * time.Minute
+
+	defaultOperatorSurgeThresholdRatio = 3.0
+	defaultOperatorSurgeWindow         = 10 * time.Second
+	// It takes about 14 minutes to iterate 1 million regions with the default
+	// batch size and patrol interval.
+	defaultPatrolRegionBatchSize = 128
+	minPatrolRegionBatchSize     = 10
+	maxPatrolRegionBatchSize     = 4096
+
+	defaultMinRegionsForScheduling = 100
+	defaultMaxSuspectRegions       = 10000
+	// defaultOperatorPoolSize is enough for a pair of merge operators.
+	defaultOperatorPoolSize = 2
+
+	defaultMaxSchedulerPanics  = 3
+	defaultPanicWindowDuration = 10 * time.Minute
+
+	defaultPluginHealthCheckInterval = time.Minute
 )
 
 func (c *ScheduleConfig) adjust(meta *configMetaData, reloading bool) error {
@@ -780,6 +952,20 @@ func (c *ScheduleConfig) adjust(meta *configMetaData, reloading bool) error {
 	if !meta.IsDefined("max-pending-peer-count") {
 		adjustUint64(&c.MaxPendingPeerCount, defaultMaxPendingPeerCount)
 	}
+	if !meta.IsDefined("max-orphan-peer-fixes-per-cycle") {
+		adjustUint64(&c.MaxOrphanPeerFixesPerCycle, defaultMaxOrphanPeerFixes)
+	}
+	if !meta.IsDefined("peer-role-conversion-cooldown") {
+		adjustDuration(&c.PeerRoleConversionCooldown, defaultPeerRoleConversionCooldown)
+	}
+	if !meta.IsDefined("pending-peer-grace-period") {
+		adjustDuration(&c.PendingPeerGracePeriod, defaultPendingPeerGracePeriod)
+	}
+	if !meta.IsDefined("max-operator-wait-time") {
+		adjustDuration(&c.MaxOperatorWaitTime, defaultMaxOperatorWaitTime)
+	}
+	adjustFloat64(&c.OperatorSurgeThresholdRatio, defaultOperatorSurgeThresholdRatio)
+	adjustDuration(&c.OperatorSurgeWindow, defaultOperatorSurgeWindow)
 	if !meta.IsDefined("max-merge-region-size") {
 		adjustUint64(&c.MaxMergeRegionSize, defaultMaxMergeRegionSize)
 	}
@@ -788,6 +974,23 @@ func (c *ScheduleConfig) adjust(meta *configMetaData, reloading bool) error {
 	}
 	adjustDuration(&c.SplitMergeInterval, defaultSplitMergeInterval)
 	adjustDuration(&c.PatrolRegionInterval, defaultPatrolRegionInterval)
+	if c.PatrolRegionBatchSize == 0 {
+		c.PatrolRegionBatchSize = defaultPatrolRegionBatchSize
+	}
+	if !meta.IsDefined("operator-pool-size") {
+		c.OperatorPoolSize = defaultOperatorPoolSize
+	}
+	if !meta.IsDefined("max-scheduler-panics") {
+		adjustUint64(&c.MaxSchedulerPanics, defaultMaxSchedulerPanics)
+	}
+	adjustDuration(&c.PanicWindowDuration, defaultPanicWindowDuration)
+	adjustDuration(&c.PluginHealthCheckInterval, defaultPluginHealthCheckInterval)
+	if !meta.IsDefined("min-regions-for-scheduling") {
+		adjustUint64(&c.MinRegionsForScheduling, defaultMinRegionsForScheduling)
+	}
+	if !meta.IsDefined("max-suspect-regions") {
+		adjustUint64(&c.MaxSuspectRegions, defaultMaxSuspectRegions)
+	}
 	adjustDuration(&c.MaxStoreDownTime, defaultMaxStoreDownTime)
 	if !meta.IsDefined("leader-schedule-limit") {
 		adjustUint64(&c.LeaderScheduleLimit, defaultLeaderScheduleLimit)
@@ -804,15 +1007,31 @@ func (c *ScheduleConfig) adjust(meta *configMetaData, reloading bool) error {
 	if !meta.IsDefined("hot-region-schedule-limit") {
 		adjustUint64(&c.HotRegionScheduleLimit, defaultHotRegionScheduleLimit)
 	}
+	if !meta.IsDefined("hot-region-schedule-limit-per-store") {
+		adjustFloat64(&c.HotRegionScheduleLimitPerStore, defaultHotRegionScheduleLimitPerStore)
+	}
 	if !meta.IsDefined("hot-region-cache-hits-threshold") {
 		adjustUint64(&c.HotRegionCacheHitsThreshold, defaultHotRegionCacheHitsThreshold)
 	}
+	if !meta.IsDefined("hot-region-cooldown-duration") {
+		adjustDuration(&c.HotRegionCooldownDuration, defaultHotRegionCooldownDuration)
+	}
+	adjustFloat64(&c.WriteAmplificationFactor, defaultWriteAmplificationFactor)
+	if !meta.IsDefined("heartbeat-denoise-window-size") {
+		adjustUint64(&c.HeartbeatDenoiseWindowSize, defaultHeartbeatDenoiseWindowSize)
+	}
+	if !meta.IsDefined("hot-check-sampling-rate") {
+		c.HotCheckSamplingRate = defaultHotCheckSamplingRate
+	}
 	if !meta.IsDefined("tolerant-size-ratio") {
 		adjustFloat64(&c.TolerantSizeRatio, defaultTolerantSizeRatio)
 	}
 	if !meta.IsDefined("scheduler-max-waiting-operator") {
 		adjustUint64(&c.SchedulerMaxWaitingOperator, defaultSchedulerMaxWaitingOperator)
 	}
+	if !meta.IsDefined("max-total-operators") {
+		adjustUint64(&c.MaxTotalOperators, defaultMaxTotalOperators)
+	}
 	if !meta.IsDefined("leader-schedule-policy") {
 		adjustString(&c.LeaderSchedulePolicy, defaultLeaderSchedulePolicy)
 	}
@@ -827,6 +1046,9 @@ func (c *ScheduleConfig) adjust(meta *configMetaData, reloading bool) error {
 	}
 	adjustFloat64(&c.LowSpaceRatio, defaultLowSpaceRatio)
 	adjustFloat64(&c.HighSpaceRatio, defaultHighSpaceRatio)
+	if !meta.IsDefined("min-available-storage-bytes") && c.MinAvailableStorageBytes == 0 {
+		c.MinAvailableStorageBytes = defaultMinAvailableStorageBytes
+	}
 
 	// new cluster:v2, old cluster:v1
 	if !meta.IsDefined("region-score-formula-version") && !reloading {
@@ -852,6 +1074,14 @@ func (c *ScheduleConfig) adjust(meta *configMetaData, reloading bool) error {
 		c.StoreLimit = make(map[uint64]StoreLimitConfig)
 	}
 
+	if c.CompactionOverheadRatio == nil {
+		c.CompactionOverheadRatio = make(map[string]float64)
+	}
+
+	if c.MaxWaitingOperatorsPerKind == nil {
+		c.MaxWaitingOperatorsPerKind = make(map[string]uint64)
+	}
+
 	return c.Validate()
 }
 
@@ -904,6 +1134,12 @@ func (c *ScheduleConfig) Validate() error {
 	if c.TolerantSizeRatio < 0 {
 		return errors.New("tolerant-size-ratio should be nonnegative")
 	}
+	if c.HotRegionScheduleLimitPerStore < 0 {
+		return errors.New("hot-region-schedule-limit-per-store should be nonnegative")
+	}
+	if c.BalanceHysteresisRatio < 0 {
+		return errors.New("balance-hysteresis-ratio should be nonnegative")
+	}
 	if c.LowSpaceRatio < 0 || c.LowSpaceRatio > 1 {
 		return errors.New("low-space-ratio should between 0 and 1")
 	}
@@ -913,6 +1149,52 @@ func (c *ScheduleConfig) Validate() error {
 	if c.LowSpaceRatio <= c.HighSpaceRatio {
 		return errors.New("low-space-ratio should be larger than high-space-ratio")
 	}
+	if c.OperatorPoolSize < 0 {
+		return errors.New("operator-pool-size should be nonnegative")
+	}
+	if c.WriteAmplificationFactor < 1 {
+		return errors.New("write-amplification-factor should be at least 1")
+	}
+	if c.HotCheckSamplingRate < 0 || c.HotCheckSamplingRate > 1 {
+		return errors.New("hot-check-sampling-rate should be between 0 and 1")
+	}
+	if c.PatrolRegionBatchSize < minPatrolRegionBatchSize || c.PatrolRegionBatchSize > maxPatrolRegionBatchSize {
+		return errors.Errorf("patrol-region-batch-size should be in [%d, %d]", minPatrolRegionBatchSize, maxPatrolRegionBatchSize)
+	}
+	if c.PluginHealthCheckInterval.Duration <= 0 {
+		return errors.New("plugin-health-check-interval should be positive")
+	}
+	if c.OperatorSurgeThresholdRatio <= 1 {
+		return errors.New("operator-surge-threshold-ratio should be larger than 1")
+	}
+	if c.OperatorSurgeWindow.Duration <= 0 {
+		return errors.New("operator-surge-window should be positive")
+	}
+	for engine, ratio := range c.CompactionOverheadRatio {
+		if ratio < 0 || ratio >= 1 {
+			return errors.Errorf("compaction-overhead-ratio of %s should be in [0, 1)", engine)
+		}
+	}
+	for label, weight := range c.StoreLabelWeights {
+		if !strings.Contains(label, "=") {
+			return errors.Errorf("store-label-weights key %s should be in the form of key=value", label)
+		}
+		if weight <= 0 {
+			return errors.Errorf("store-label-weights of %s should be positive", label)
+		}
+	}
+	for kinds := range c.MaxWaitingOperatorsPerKind {
+		for _, kind := range strings.Split(kinds, ",") {
+			if !IsOperatorKindRegistered(kind) {
+				return errors.Errorf("max-waiting-operators-per-kind has an unknown operator kind %s", kind)
+			}
+		}
+	}
+	for key := range c.PreferredLeaderLabels {
+		if key == "" {
+			return errors.New("preferred-leader-labels should not contain an empty key")
+		}
+	}
 	for _, scheduleConfig := range c.Schedulers {
 		if !IsSchedulerRegistered(scheduleConfig.Type) {
 			return errors.Errorf("create func of %v is not registered, maybe misspelled", scheduleConfig.Type)
@@ -1304,6 +1586,35 @@ func (c *DashboardConfig) adjust(meta *configMetaData) {
 	c.EnableTelemetry = c.EnableTelemetry && !c.DisableTelemetry
 }
 
+// RemotePluginConfig is the configuration for loading the plugins from urls.
+type RemotePluginConfig struct {
+	// Enable allows loading the plugins from urls by the API. It is disabled by default.
+	Enable bool `toml:"enable" json:"enable"`
+	// AllowedURLPrefixes are the prefixes of the urls which the plugins can be loaded from.
+	AllowedURLPrefixes []string `toml:"allowed-url-prefixes" json:"allowed-url-prefixes"`
+	// MaxSize is the max size of a plugin to download.
+	MaxSize typeutil.ByteSize `toml:"max-size" json:"max-size"`
+}
+
+func (c *RemotePluginConfig) adjust(meta *configMetaData) {
+	if !meta.IsDefined("max-size") {
+		c.MaxSize = defaultMaxRemotePluginSize
+	}
+}
+
+// IsURLAllowed returns whether the plugin can be loaded from the url.
+func (c *RemotePluginConfig) IsURLAllowed(url string) bool {
+	if !c.Enable {
+		return false
+	}
+	for _, prefix := range c.AllowedURLPrefixes {
+		if prefix != "" && strings.HasPrefix(url, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // ReplicationModeConfig is the configuration for the replication policy.
 type ReplicationModeConfig struct {
 	ReplicationMode string                      `toml:"replication-mode" json:"replication-mode"` // can be 'dr-auto-sync' or 'majority', default value is 'majority'
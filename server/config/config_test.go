@@ -24,6 +24,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/kv"
 )
@@ -154,6 +155,36 @@ func (s *testConfigSuite) TestValidation(c *C) {
 	c.Assert(cfg.Schedule.Validate(), IsNil)
 	cfg.Schedule.TolerantSizeRatio = -0.6
 	c.Assert(cfg.Schedule.Validate(), NotNil)
+	cfg.Schedule.TolerantSizeRatio = 0
+	c.Assert(cfg.Schedule.PatrolRegionBatchSize, Equals, defaultPatrolRegionBatchSize)
+	cfg.Schedule.PatrolRegionBatchSize = 9
+	c.Assert(cfg.Schedule.Validate(), NotNil)
+	cfg.Schedule.PatrolRegionBatchSize = 4097
+	c.Assert(cfg.Schedule.Validate(), NotNil)
+	cfg.Schedule.PatrolRegionBatchSize = 4096
+	c.Assert(cfg.Schedule.Validate(), IsNil)
+	cfg.Schedule.PluginHealthCheckInterval.Duration = -time.Second
+	c.Assert(cfg.Schedule.Validate(), NotNil)
+	cfg.Schedule.PluginHealthCheckInterval.Duration = 0
+	c.Assert(cfg.Schedule.Validate(), NotNil)
+	cfg.Schedule.PluginHealthCheckInterval.Duration = time.Minute
+	c.Assert(cfg.Schedule.Validate(), IsNil)
+	c.Assert(cfg.Schedule.OperatorSurgeThresholdRatio, Equals, defaultOperatorSurgeThresholdRatio)
+	c.Assert(cfg.Schedule.OperatorSurgeWindow.Duration, Equals, defaultOperatorSurgeWindow)
+	cfg.Schedule.OperatorSurgeThresholdRatio = 1
+	c.Assert(cfg.Schedule.Validate(), NotNil)
+	cfg.Schedule.OperatorSurgeThresholdRatio = 2
+	cfg.Schedule.OperatorSurgeWindow.Duration = 0
+	c.Assert(cfg.Schedule.Validate(), NotNil)
+	cfg.Schedule.OperatorSurgeWindow.Duration = time.Minute
+	c.Assert(cfg.Schedule.Validate(), IsNil)
+	RegisterOperatorKind("merge")
+	RegisterOperatorKind("leader")
+	cfg.Schedule.MaxWaitingOperatorsPerKind = map[string]uint64{"merge": 1, "merge,leader": 2}
+	c.Assert(cfg.Schedule.Validate(), IsNil)
+	cfg.Schedule.MaxWaitingOperatorsPerKind["mrege"] = 1
+	c.Assert(cfg.Schedule.Validate(), NotNil)
+	delete(cfg.Schedule.MaxWaitingOperatorsPerKind, "mrege")
 	// check quota
 	c.Assert(cfg.QuotaBackendBytes, Equals, defaultQuotaBackendBytes)
 }
@@ -420,6 +451,31 @@ tidb-cert-path = "/path/client.pem"
 	defaultEnableTelemetry = originalDefaultEnableTelemetry
 }
 
+func (s *testConfigSuite) TestRemotePluginConfig(c *C) {
+	cfg := NewConfig()
+	meta, err := toml.Decode("", &cfg)
+	c.Assert(err, IsNil)
+	c.Assert(cfg.Adjust(&meta, false), IsNil)
+	c.Assert(cfg.RemotePlugin.Enable, IsFalse)
+	c.Assert(cfg.RemotePlugin.MaxSize, Equals, defaultMaxRemotePluginSize)
+	c.Assert(cfg.RemotePlugin.IsURLAllowed("https://example.com/plugin.so"), IsFalse)
+
+	cfgData := `
+[remote-plugin]
+enable = true
+allowed-url-prefixes = ["https://example.com/plugins/"]
+max-size = "1MiB"
+`
+	cfg = NewConfig()
+	meta, err = toml.Decode(cfgData, &cfg)
+	c.Assert(err, IsNil)
+	c.Assert(cfg.Adjust(&meta, false), IsNil)
+	c.Assert(cfg.RemotePlugin.MaxSize, Equals, typeutil.ByteSize(1024*1024))
+	c.Assert(cfg.RemotePlugin.IsURLAllowed("https://example.com/plugins/a.so"), IsTrue)
+	c.Assert(cfg.RemotePlugin.IsURLAllowed("https://example.com/other/a.so"), IsFalse)
+	c.Assert(cfg.RemotePlugin.IsURLAllowed("http://example.com/plugins/a.so"), IsFalse)
+}
+
 func (s *testConfigSuite) TestReplicationMode(c *C) {
 	cfgData := `
 [replication-mode]
@@ -215,6 +215,36 @@ func (o *PersistOptions) GetMaxPendingPeerCount() uint64 {
 	return o.getTTLUintOr(maxPendingPeerCountKey, o.GetScheduleConfig().MaxPendingPeerCount)
 }
 
+// GetMaxOrphanPeerFixesPerCycle returns the max number of orphan peers removed in one patrol round.
+func (o *PersistOptions) GetMaxOrphanPeerFixesPerCycle() uint64 {
+	return o.GetScheduleConfig().MaxOrphanPeerFixesPerCycle
+}
+
+// GetPendingPeerGracePeriod returns how long a peer can be pending before it is treated as unhealthy.
+func (o *PersistOptions) GetPendingPeerGracePeriod() time.Duration {
+	return o.GetScheduleConfig().PendingPeerGracePeriod.Duration
+}
+
+// GetMaxOperatorWaitTime returns the max running time of an operator which moves regions.
+func (o *PersistOptions) GetMaxOperatorWaitTime() time.Duration {
+	return o.GetScheduleConfig().MaxOperatorWaitTime.Duration
+}
+
+// GetOperatorSurgeThresholdRatio returns the ratio of the operator generation rate to its rolling average regarded as a surge.
+func (o *PersistOptions) GetOperatorSurgeThresholdRatio() float64 {
+	return o.GetScheduleConfig().OperatorSurgeThresholdRatio
+}
+
+// GetOperatorSurgeWindow returns the duration a surge of the operator generation needs to last before it is reported.
+func (o *PersistOptions) GetOperatorSurgeWindow() time.Duration {
+	return o.GetScheduleConfig().OperatorSurgeWindow.Duration
+}
+
+// GetPeerRoleConversionCooldown returns the min interval to convert the role of the same peer again.
+func (o *PersistOptions) GetPeerRoleConversionCooldown() time.Duration {
+	return o.GetScheduleConfig().PeerRoleConversionCooldown.Duration
+}
+
 // GetMaxMergeRegionSize returns the max region size.
 func (o *PersistOptions) GetMaxMergeRegionSize() uint64 {
 	return o.getTTLUintOr(maxMergeRegionSizeKey, o.GetScheduleConfig().MaxMergeRegionSize)
@@ -293,16 +323,66 @@ func (o *PersistOptions) IsCrossTableMergeEnabled() bool {
 	return o.GetScheduleConfig().EnableCrossTableMerge
 }
 
+// IsWorkStealingEnabled returns if an idle scheduler can generate the operators of a busy scheduler's type.
+func (o *PersistOptions) IsWorkStealingEnabled() bool {
+	return o.GetScheduleConfig().EnableWorkStealing
+}
+
 // GetPatrolRegionInterval returns the interval of patrolling region.
 func (o *PersistOptions) GetPatrolRegionInterval() time.Duration {
 	return o.GetScheduleConfig().PatrolRegionInterval.Duration
 }
 
+// GetPatrolRegionIntervalJitter returns the max random duration added to the patrol interval.
+func (o *PersistOptions) GetPatrolRegionIntervalJitter() time.Duration {
+	return o.GetScheduleConfig().PatrolRegionIntervalJitter.Duration
+}
+
+// GetPatrolRegionBatchSize returns the number of regions scanned in each patrol.
+func (o *PersistOptions) GetPatrolRegionBatchSize() int {
+	return o.GetScheduleConfig().PatrolRegionBatchSize
+}
+
+// GetMinRegionsForScheduling returns the number of regions below which the cluster is bootstrapping.
+func (o *PersistOptions) GetMinRegionsForScheduling() uint64 {
+	return o.GetScheduleConfig().MinRegionsForScheduling
+}
+
+// GetMaxSuspectRegions returns the max number of suspect regions.
+func (o *PersistOptions) GetMaxSuspectRegions() uint64 {
+	return o.GetScheduleConfig().MaxSuspectRegions
+}
+
+// GetOperatorPoolSize returns the capacity of the operator slices reused by the operator controller.
+func (o *PersistOptions) GetOperatorPoolSize() int {
+	return o.GetScheduleConfig().OperatorPoolSize
+}
+
+// GetMaxSchedulerPanics returns the max number of panics of a scheduler within the panic window.
+func (o *PersistOptions) GetMaxSchedulerPanics() uint64 {
+	return o.GetScheduleConfig().MaxSchedulerPanics
+}
+
+// GetPanicWindowDuration returns the duration in which the panics of a scheduler are counted.
+func (o *PersistOptions) GetPanicWindowDuration() time.Duration {
+	return o.GetScheduleConfig().PanicWindowDuration.Duration
+}
+
+// GetPluginHealthCheckInterval returns the interval to check the loaded scheduler plugins.
+func (o *PersistOptions) GetPluginHealthCheckInterval() time.Duration {
+	return o.GetScheduleConfig().PluginHealthCheckInterval.Duration
+}
+
 // GetMaxStoreDownTime returns the max down time of a store.
 func (o *PersistOptions) GetMaxStoreDownTime() time.Duration {
 	return o.GetScheduleConfig().MaxStoreDownTime.Duration
 }
 
+// GetStoreRemovalConfirmationTimeout returns the duration in which a store removal needs to be confirmed.
+func (o *PersistOptions) GetStoreRemovalConfirmationTimeout() time.Duration {
+	return o.GetScheduleConfig().StoreRemovalConfirmationTimeout.Duration
+}
+
 // GetLeaderScheduleLimit returns the limit for leader schedule.
 func (o *PersistOptions) GetLeaderScheduleLimit() uint64 {
 	return o.getTTLUintOr(leaderScheduleLimitKey, o.GetScheduleConfig().LeaderScheduleLimit)
@@ -323,6 +403,11 @@ func (o *PersistOptions) GetMergeScheduleLimit() uint64 {
 	return o.getTTLUintOr(mergeScheduleLimitKey, o.GetScheduleConfig().MergeScheduleLimit)
 }
 
+// GetHotRegionScheduleLimitPerStore returns the hot region schedule limit for each store.
+func (o *PersistOptions) GetHotRegionScheduleLimitPerStore() float64 {
+	return o.GetScheduleConfig().HotRegionScheduleLimitPerStore
+}
+
 // GetHotRegionScheduleLimit returns the limit for hot region schedule.
 func (o *PersistOptions) GetHotRegionScheduleLimit() uint64 {
 	return o.getTTLUintOr(hotRegionScheduleLimitKey, o.GetScheduleConfig().HotRegionScheduleLimit)
@@ -403,6 +488,38 @@ func (o *PersistOptions) GetTolerantSizeRatio() float64 {
 	return o.GetScheduleConfig().TolerantSizeRatio
 }
 
+// GetBalanceHysteresisRatio returns the noise band ratio of the balance schedulers.
+func (o *PersistOptions) GetBalanceHysteresisRatio() float64 {
+	return o.GetScheduleConfig().BalanceHysteresisRatio
+}
+
+// GetStoreLabelWeights returns the weights of the store labels to normalize the region scores.
+func (o *PersistOptions) GetStoreLabelWeights() map[string]float64 {
+	return o.GetScheduleConfig().StoreLabelWeights
+}
+
+// GetPreferredLeaderLabels returns the labels which the stores of the transferred leaders should match.
+func (o *PersistOptions) GetPreferredLeaderLabels() map[string]string {
+	return o.GetScheduleConfig().PreferredLeaderLabels
+}
+
+// NormalizationFactor returns the product of the weights of the labels matched
+// by the store, which the region and leader scores of the store are multiplied
+// by when balancing.
+func (o *PersistOptions) NormalizationFactor(store *core.StoreInfo) float64 {
+	weights := o.GetStoreLabelWeights()
+	factor := 1.0
+	if len(weights) == 0 {
+		return factor
+	}
+	for _, label := range store.GetLabels() {
+		if weight, ok := weights[label.GetKey()+"="+label.GetValue()]; ok {
+			factor *= weight
+		}
+	}
+	return factor
+}
+
 // GetLowSpaceRatio returns the low space ratio.
 func (o *PersistOptions) GetLowSpaceRatio() float64 {
 	return o.GetScheduleConfig().LowSpaceRatio
@@ -413,6 +530,22 @@ func (o *PersistOptions) GetHighSpaceRatio() float64 {
 	return o.GetScheduleConfig().HighSpaceRatio
 }
 
+// GetCompactionOverheadRatio returns the estimated ratio of the compaction writes
+// in the write bandwidth of the stores with the given engine.
+func (o *PersistOptions) GetCompactionOverheadRatio(engine string) float64 {
+	return o.GetScheduleConfig().CompactionOverheadRatio[engine]
+}
+
+// GetMinAvailableStorageBytes returns the hard floor of the available space of a store.
+func (o *PersistOptions) GetMinAvailableStorageBytes() uint64 {
+	return uint64(o.GetScheduleConfig().MinAvailableStorageBytes)
+}
+
+// GetMaxRegionsPerStore returns the max number of regions of a store.
+func (o *PersistOptions) GetMaxRegionsPerStore() uint64 {
+	return o.GetScheduleConfig().MaxRegionsPerStore
+}
+
 // GetRegionScoreFormulaVersion returns the formula version config.
 func (o *PersistOptions) GetRegionScoreFormulaVersion() string {
 	return o.GetScheduleConfig().RegionScoreFormulaVersion
@@ -423,6 +556,16 @@ func (o *PersistOptions) GetSchedulerMaxWaitingOperator() uint64 {
 	return o.getTTLUintOr(schedulerMaxWaitingOperatorKey, o.GetScheduleConfig().SchedulerMaxWaitingOperator)
 }
 
+// GetMaxWaitingOperatorsPerKind returns the max number of the waiting operators of each kind.
+func (o *PersistOptions) GetMaxWaitingOperatorsPerKind() map[string]uint64 {
+	return o.GetScheduleConfig().MaxWaitingOperatorsPerKind
+}
+
+// GetMaxTotalOperators returns the max number of the running and waiting operators.
+func (o *PersistOptions) GetMaxTotalOperators() uint64 {
+	return o.GetScheduleConfig().MaxTotalOperators
+}
+
 // GetLeaderSchedulePolicy is to get leader schedule policy.
 func (o *PersistOptions) GetLeaderSchedulePolicy() core.SchedulePolicy {
 	return core.StringToSchedulePolicy(o.GetScheduleConfig().LeaderSchedulePolicy)
@@ -495,6 +638,36 @@ func (o *PersistOptions) GetHotRegionCacheHitsThreshold() int {
 	return int(o.GetScheduleConfig().HotRegionCacheHitsThreshold)
 }
 
+// GetScatterPartitionLabel returns the label dividing the stores into partitions for the region scatterer.
+func (o *PersistOptions) GetScatterPartitionLabel() string {
+	return o.GetScheduleConfig().ScatterPartitionLabel
+}
+
+// GetWriteAmplificationFactor returns the factor multiplied into the byte rate of the hot write peers to move.
+func (o *PersistOptions) GetWriteAmplificationFactor() float64 {
+	return o.GetScheduleConfig().WriteAmplificationFactor
+}
+
+// GetHotRegionCooldownDuration returns the duration during which a moved hot region is not scheduled again.
+func (o *PersistOptions) GetHotRegionCooldownDuration() time.Duration {
+	return o.GetScheduleConfig().HotRegionCooldownDuration.Duration
+}
+
+// GetHeartbeatDenoiseWindowSize returns the min interval in seconds of a flow sample of the hot cache.
+func (o *PersistOptions) GetHeartbeatDenoiseWindowSize() uint64 {
+	return o.GetScheduleConfig().HeartbeatDenoiseWindowSize
+}
+
+// GetHotThresholdRecalibrationInterval returns the min interval between two calculations of the hot thresholds.
+func (o *PersistOptions) GetHotThresholdRecalibrationInterval() time.Duration {
+	return o.GetScheduleConfig().HotThresholdRecalibrationInterval.Duration
+}
+
+// GetHotCheckSamplingRate returns the ratio of the heartbeats of the cold regions checked by the hot cache.
+func (o *PersistOptions) GetHotCheckSamplingRate() float64 {
+	return o.GetScheduleConfig().HotCheckSamplingRate
+}
+
 // GetStoresLimit gets the stores' limit.
 func (o *PersistOptions) GetStoresLimit() map[uint64]StoreLimitConfig {
 	return o.GetScheduleConfig().StoreLimit
@@ -76,6 +76,19 @@ func IsSchedulerRegistered(name string) bool {
 	return ok
 }
 
+var operatorKindMap = make(map[string]struct{})
+
+// RegisterOperatorKind registers the name of the operator kind.
+func RegisterOperatorKind(name string) {
+	operatorKindMap[name] = struct{}{}
+}
+
+// IsOperatorKindRegistered checks if the named operator kind is registered.
+func IsOperatorKindRegistered(name string) bool {
+	_, ok := operatorKindMap[name]
+	return ok
+}
+
 // NewTestOptions creates default options for testing.
 func NewTestOptions() *PersistOptions {
 	// register default schedulers in case config check fail.
@@ -46,6 +46,8 @@ const (
 	componentPath              = "component"
 	customScheduleConfigPath   = "scheduler_config"
 	encryptionKeysPath         = "encryption_keys"
+	hotCachePath               = "hot_cache"
+	checkerPausePath           = "checker_pause"
 	gcWorkerServiceSafePointID = "gc_worker"
 )
 
@@ -315,6 +317,32 @@ func (s *Storage) LoadRangeByPrefix(prefix string, f func(k, v string)) error {
 	}
 }
 
+// SaveCheckerPauseUntil saves the unix time until which the checker is paused.
+func (s *Storage) SaveCheckerPauseUntil(name string, pauseUntil int64) error {
+	return s.Save(path.Join(checkerPausePath, name), strconv.FormatInt(pauseUntil, 10))
+}
+
+// LoadCheckerPauseUntil loads the unix time until which each checker is paused.
+func (s *Storage) LoadCheckerPauseUntil() (map[string]int64, error) {
+	pauses := make(map[string]int64)
+	var parseErr error
+	err := s.LoadRangeByPrefix(checkerPausePath+"/", func(k, v string) {
+		pauseUntil, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			parseErr = errs.ErrStrconvParseInt.Wrap(err).GenWithStackByArgs()
+			return
+		}
+		pauses[k] = pauseUntil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return pauses, nil
+}
+
 // SaveReplicationStatus stores replication status by mode.
 func (s *Storage) SaveReplicationStatus(mode string, status interface{}) error {
 	value, err := json.Marshal(status)
@@ -365,6 +393,16 @@ func (s *Storage) LoadComponent(component interface{}) (bool, error) {
 	return true, nil
 }
 
+// SaveHotCache stores the serialized hot cache to the hotCachePath.
+func (s *Storage) SaveHotCache(data []byte) error {
+	return s.Save(hotCachePath, string(data))
+}
+
+// LoadHotCache loads the serialized hot cache from the hotCachePath.
+func (s *Storage) LoadHotCache() (string, error) {
+	return s.Load(hotCachePath)
+}
+
 // LoadStores loads all stores from storage to StoresInfo.
 func (s *Storage) LoadStores(f func(store *StoreInfo)) error {
 	nextID := uint64(0)
@@ -33,8 +33,18 @@ const (
 	gb                     = 1 << 30 // 1GB size
 	initialMaxRegionCounts = 30      // exclude storage Threshold Filter when region less than 30
 	initialMinSpace        = 1 << 33 // 2^33=8GB
+	// stateHistoryCapacity is the max number of state transitions kept for a store.
+	stateHistoryCapacity = 50
 )
 
+// StoreStateEvent records a state transition of a store.
+type StoreStateEvent struct {
+	PreviousState metapb.StoreState
+	NewState      metapb.StoreState
+	At            time.Time
+	Reason        string
+}
+
 // StoreInfo contains information about a store.
 type StoreInfo struct {
 	meta *metapb.Store
@@ -49,6 +59,7 @@ type StoreInfo struct {
 	leaderWeight        float64
 	regionWeight        float64
 	available           map[storelimit.Type]func() bool
+	stateHistory        []StoreStateEvent
 }
 
 // NewStoreInfo creates StoreInfo with meta data.
@@ -81,6 +92,7 @@ func (s *StoreInfo) Clone(opts ...StoreCreateOption) *StoreInfo {
 		leaderWeight:        s.leaderWeight,
 		regionWeight:        s.regionWeight,
 		available:           s.available,
+		stateHistory:        s.stateHistory,
 	}
 
 	for _, opt := range opts {
@@ -104,6 +116,7 @@ func (s *StoreInfo) ShallowClone(opts ...StoreCreateOption) *StoreInfo {
 		leaderWeight:        s.leaderWeight,
 		regionWeight:        s.regionWeight,
 		available:           s.available,
+		stateHistory:        s.stateHistory,
 	}
 
 	for _, opt := range opts {
@@ -112,6 +125,13 @@ func (s *StoreInfo) ShallowClone(opts ...StoreCreateOption) *StoreInfo {
 	return store
 }
 
+// GetStateHistory returns the recent state transitions of the store, the oldest first.
+func (s *StoreInfo) GetStateHistory() []StoreStateEvent {
+	history := make([]StoreStateEvent, len(s.stateHistory))
+	copy(history, s.stateHistory)
+	return history
+}
+
 // AllowLeaderTransfer returns if the store is allowed to be selected
 // as source or target of transfer leader.
 func (s *StoreInfo) AllowLeaderTransfer() bool {
@@ -339,6 +359,18 @@ func (s *StoreInfo) AvailableRatio() float64 {
 	return float64(s.GetAvailable()) / float64(s.GetCapacity())
 }
 
+// EffectiveWriteBandwidth returns the write bandwidth of the store in MB/s
+// excluding the estimated compaction writes.
+func (s *StoreInfo) EffectiveWriteBandwidth(compactionOverheadRatio float64) float64 {
+	interval := s.GetStoreStats().GetInterval()
+	if interval.GetEndTimestamp() <= interval.GetStartTimestamp() {
+		return 0
+	}
+	seconds := float64(interval.GetEndTimestamp() - interval.GetStartTimestamp())
+	writeMBps := float64(s.GetBytesWritten()) / seconds / (1 << 20)
+	return writeMBps * (1 - math.Min(math.Max(compactionOverheadRatio, 0), 1))
+}
+
 // IsLowSpace checks if the store is lack of space. Not check if region count less
 // than initialMaxRegionCounts and available space more than initialMinSpace
 func (s *StoreInfo) IsLowSpace(lowSpaceRatio float64) bool {
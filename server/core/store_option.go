@@ -101,6 +101,27 @@ func TombstoneStore() StoreCreateOption {
 	}
 }
 
+// RecordStateTransition records the transition from the previous state to the
+// current state of the store. It should be applied after the option which
+// changes the state.
+func RecordStateTransition(previous metapb.StoreState, reason string) StoreCreateOption {
+	return func(store *StoreInfo) {
+		// Always allocate a new slice since the history is shared between clones.
+		start := 0
+		if len(store.stateHistory) >= stateHistoryCapacity {
+			start = len(store.stateHistory) - stateHistoryCapacity + 1
+		}
+		history := make([]StoreStateEvent, 0, len(store.stateHistory)-start+1)
+		history = append(history, store.stateHistory[start:]...)
+		store.stateHistory = append(history, StoreStateEvent{
+			PreviousState: previous,
+			NewState:      store.GetState(),
+			At:            time.Now(),
+			Reason:        reason,
+		})
+	}
+}
+
 // PauseLeaderTransfer prevents the store from been selected as source or
 // target store of TransferLeader.
 func PauseLeaderTransfer() StoreCreateOption {
@@ -14,6 +14,7 @@
 package core
 
 import (
+	"fmt"
 	"math"
 	"sync"
 	"time"
@@ -126,3 +127,44 @@ func (s *testStoreSuite) TestLowSpaceRatio(c *C) {
 	store.rawStats.Available = store.rawStats.Capacity >> 2
 	c.Assert(store.IsLowSpace(0.8), Equals, false)
 }
+
+func (s *testStoreSuite) TestEffectiveWriteBandwidth(c *C) {
+	stats := &pdpb.StoreStats{
+		BytesWritten: 100 * (1 << 20), // 100 MB
+		Interval:     &pdpb.TimeInterval{StartTimestamp: 100, EndTimestamp: 110},
+	}
+	store := NewStoreInfo(&metapb.Store{Id: 1}, SetStoreStats(stats))
+	c.Assert(store.EffectiveWriteBandwidth(0), Equals, 10.0)
+	c.Assert(store.EffectiveWriteBandwidth(0.3), Equals, 7.0)
+	c.Assert(store.EffectiveWriteBandwidth(-1), Equals, 10.0)
+	c.Assert(store.EffectiveWriteBandwidth(2), Equals, 0.0)
+
+	// no valid interval is reported yet.
+	store = NewStoreInfo(&metapb.Store{Id: 1}, SetStoreStats(&pdpb.StoreStats{BytesWritten: 100}))
+	c.Assert(store.EffectiveWriteBandwidth(0.3), Equals, 0.0)
+}
+
+func (s *testStoreSuite) TestStateHistory(c *C) {
+	store := NewStoreInfo(&metapb.Store{Id: 1})
+	c.Assert(store.GetStateHistory(), HasLen, 0)
+	offline := store.Clone(OfflineStore(false), RecordStateTransition(store.GetState(), "offline"))
+	up := offline.Clone(UpStore(), RecordStateTransition(offline.GetState(), "up"))
+	// the history of the clones does not affect each other.
+	c.Assert(store.GetStateHistory(), HasLen, 0)
+	c.Assert(offline.GetStateHistory(), HasLen, 1)
+	history := up.GetStateHistory()
+	c.Assert(history, HasLen, 2)
+	c.Assert(history[0].PreviousState, Equals, metapb.StoreState_Up)
+	c.Assert(history[0].NewState, Equals, metapb.StoreState_Offline)
+	c.Assert(history[1].Reason, Equals, "up")
+	c.Assert(history[1].NewState, Equals, metapb.StoreState_Up)
+
+	// only the latest transitions are kept.
+	for i := 0; i < stateHistoryCapacity; i++ {
+		up = up.Clone(RecordStateTransition(metapb.StoreState_Offline, fmt.Sprintf("up-%d", i)))
+	}
+	history = up.GetStateHistory()
+	c.Assert(history, HasLen, stateHistoryCapacity)
+	c.Assert(history[0].Reason, Equals, "up-0")
+	c.Assert(history[stateHistoryCapacity-1].Reason, Equals, fmt.Sprintf("up-%d", stateHistoryCapacity-1))
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/http"
+	"os"
 	"path"
 	"strconv"
 	"strings"
@@ -79,10 +80,13 @@ type Handler struct {
 	opt             *config.PersistOptions
 	pluginChMap     map[string]chan string
 	pluginChMapLock sync.RWMutex
+	// remotePlugins are the paths of the plugins downloaded from urls, which are
+	// removed once they are unloaded. It is protected by pluginChMapLock.
+	remotePlugins map[string]struct{}
 }
 
 func newHandler(s *Server) *Handler {
-	return &Handler{s: s, opt: s.persistOptions, pluginChMap: make(map[string]chan string), pluginChMapLock: sync.RWMutex{}}
+	return &Handler{s: s, opt: s.persistOptions, pluginChMap: make(map[string]chan string), pluginChMapLock: sync.RWMutex{}, remotePlugins: make(map[string]struct{})}
 }
 
 // GetRaftCluster returns RaftCluster.
@@ -112,6 +116,15 @@ func (h *Handler) IsSchedulerPaused(name string) (bool, error) {
 	return rc.IsSchedulerPaused(name)
 }
 
+// GetSchedulerPauseTimeRemaining returns how long the scheduler is still paused.
+func (h *Handler) GetSchedulerPauseTimeRemaining(name string) (time.Duration, error) {
+	rc, err := h.GetRaftCluster()
+	if err != nil {
+		return 0, err
+	}
+	return rc.GetSchedulerPauseTimeRemaining(name)
+}
+
 // IsSchedulerDisabled returns whether scheduler is disabled.
 func (h *Handler) IsSchedulerDisabled(name string) (bool, error) {
 	rc, err := h.GetRaftCluster()
@@ -135,6 +148,15 @@ func (h *Handler) GetSchedulers() ([]string, error) {
 	return c.GetSchedulers(), nil
 }
 
+// GetSchedulerStats returns the statistics of all schedulers.
+func (h *Handler) GetSchedulerStats() (map[string]cluster.SchedulerStats, error) {
+	c, err := h.GetRaftCluster()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetSchedulerStats(), nil
+}
+
 // GetStores returns all stores in the cluster.
 func (h *Handler) GetStores() ([]*core.StoreInfo, error) {
 	rc := h.s.GetRaftCluster()
@@ -181,6 +203,26 @@ func (h *Handler) GetStoresLoads() map[uint64][]float64 {
 	return rc.GetStoresLoads()
 }
 
+// GetHotStoresSummary gets the summary of the hot peers on each store.
+func (h *Handler) GetHotStoresSummary(kind statistics.FlowKind) map[uint64]*statistics.HotStoreSummary {
+	rc := h.s.GetRaftCluster()
+	if rc == nil {
+		return nil
+	}
+	var stats map[uint64][]*statistics.HotPeerStat
+	switch kind {
+	case statistics.WriteFlow:
+		stats = rc.RegionWriteStats()
+	case statistics.ReadFlow:
+		stats = rc.RegionReadStats()
+	}
+	summary := make(map[uint64]*statistics.HotStoreSummary, len(stats))
+	for storeID, peers := range stats {
+		summary[storeID] = statistics.SummaryHotPeers(peers)
+	}
+	return summary
+}
+
 // AddScheduler adds a scheduler.
 func (h *Handler) AddScheduler(name string, args ...string) error {
 	c, err := h.GetRaftCluster()
@@ -188,6 +230,13 @@ func (h *Handler) AddScheduler(name string, args ...string) error {
 		return err
 	}
 
+	// validate it first, so nothing is persisted if it cannot be added.
+	if err = c.ValidateScheduler(name, args); err != nil {
+		if errors.ErrorEqual(err, errs.ErrSchedulerExisted.FastGenByArgs()) {
+			return err
+		}
+		return errs.ErrSchedulerInvalid.FastGenByArgs(err.Error())
+	}
 	s, err := schedule.CreateScheduler(name, c.GetOperatorController(), h.s.storage, schedule.ConfigSliceDecoder(name, args))
 	if err != nil {
 		return err
@@ -231,6 +280,24 @@ func (h *Handler) PauseOrResumeScheduler(name string, t int64) error {
 	return err
 }
 
+// PauseOrResumeChecker pauses a checker for delay seconds or resume a paused checker.
+// t == 0 : resume checker.
+// t > 0 : checker delays t seconds.
+func (h *Handler) PauseOrResumeChecker(name string, t int64) error {
+	c, err := h.GetRaftCluster()
+	if err != nil {
+		return err
+	}
+	if err = c.PauseOrResumeChecker(name, t); err != nil {
+		if t == 0 {
+			log.Error("can not resume checker", zap.String("checker-name", name), errs.ZapError(err))
+		} else {
+			log.Error("can not pause checker", zap.String("checker-name", name), errs.ZapError(err))
+		}
+	}
+	return err
+}
+
 // AddBalanceLeaderScheduler adds a balance-leader-scheduler.
 func (h *Handler) AddBalanceLeaderScheduler() error {
 	return h.AddScheduler(schedulers.BalanceLeaderType)
@@ -281,6 +348,12 @@ func (h *Handler) AddShuffleHotRegionScheduler(limit uint64) error {
 	return h.AddScheduler(schedulers.ShuffleHotRegionType, strconv.FormatUint(limit, 10))
 }
 
+// AddRegionGroupSizeBalancer adds a region-group-size-balancer. The args are
+// the start key and end key of each group.
+func (h *Handler) AddRegionGroupSizeBalancer(args ...string) error {
+	return h.AddScheduler(schedulers.RegionGroupSizeBalancerType, args...)
+}
+
 // AddRandomMergeScheduler adds a random-merge-scheduler.
 func (h *Handler) AddRandomMergeScheduler() error {
 	return h.AddScheduler(schedulers.RandomMergeType)
@@ -645,7 +718,7 @@ func (h *Handler) AddRemovePeerOperator(regionID uint64, fromStoreID uint64) err
 		return errors.Errorf("region has no peer in store %v", fromStoreID)
 	}
 
-	op, err := operator.CreateRemovePeerOperator("admin-remove-peer", c, operator.OpAdmin, region, fromStoreID)
+	op, err := operator.CreateRemovePeerOperator("admin-remove-peer", c, operator.OpAdmin, region, false, fromStoreID)
 	if err != nil {
 		log.Debug("fail to create move peer operator", errs.ZapError(err))
 		return err
@@ -880,17 +953,40 @@ func (h *Handler) PluginLoad(pluginPath string) error {
 	return nil
 }
 
+// PluginLoadRemote loads the plugin downloaded from a url to the pluginPath.
+// The file is removed when the plugin is unloaded.
+func (h *Handler) PluginLoadRemote(pluginPath string) error {
+	if err := h.PluginLoad(pluginPath); err != nil {
+		return err
+	}
+	h.pluginChMapLock.Lock()
+	defer h.pluginChMapLock.Unlock()
+	h.remotePlugins[pluginPath] = struct{}{}
+	return nil
+}
+
 // PluginUnload unloads the plugin referenced by the pluginPath
 func (h *Handler) PluginUnload(pluginPath string) error {
 	h.pluginChMapLock.Lock()
 	defer h.pluginChMapLock.Unlock()
 	if ch, ok := h.pluginChMap[pluginPath]; ok {
 		ch <- cluster.PluginUnload
+		if _, ok := h.remotePlugins[pluginPath]; ok {
+			delete(h.remotePlugins, pluginPath)
+			if err := os.Remove(pluginPath); err != nil {
+				log.Warn("failed to remove the downloaded plugin", zap.String("plugin", pluginPath), errs.ZapError(err))
+			}
+		}
 		return nil
 	}
 	return ErrPluginNotFound(pluginPath)
 }
 
+// GetRemotePluginConfig returns the configuration for loading the plugins from urls.
+func (h *Handler) GetRemotePluginConfig() *config.RemotePluginConfig {
+	return &h.s.cfg.RemotePlugin
+}
+
 // GetAddr returns the server urls for clients.
 func (h *Handler) GetAddr() string {
 	return h.s.GetAddr()
@@ -16,8 +16,10 @@ package checker
 import (
 	"bytes"
 	"context"
+	"sort"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/cache"
 	"github.com/tikv/pd/pkg/codec"
@@ -80,36 +82,8 @@ func (m *MergeChecker) Check(region *core.RegionInfo) []*operator.Operator {
 
 	checkerCounter.WithLabelValues("merge_checker", "check").Inc()
 
-	// when pd just started, it will load region meta from etcd
-	// but the size for these loaded region info is 0
-	// pd don't know the real size of one region until the first heartbeat of the region
-	// thus here when size is 0, just skip.
-	if region.GetApproximateSize() == 0 {
-		checkerCounter.WithLabelValues("merge_checker", "skip").Inc()
-		return nil
-	}
-
-	// region is not small enough
-	if region.GetApproximateSize() > int64(m.opts.GetMaxMergeRegionSize()) ||
-		region.GetApproximateKeys() > int64(m.opts.GetMaxMergeRegionKeys()) {
-		checkerCounter.WithLabelValues("merge_checker", "no-need").Inc()
-		return nil
-	}
-
-	// skip region has down peers or pending peers or learner peers
-	if !opt.IsRegionHealthy(m.cluster, region) {
-		checkerCounter.WithLabelValues("merge_checker", "special-peer").Inc()
-		return nil
-	}
-
-	if !opt.IsRegionReplicated(m.cluster, region) {
-		checkerCounter.WithLabelValues("merge_checker", "abnormal-replica").Inc()
-		return nil
-	}
-
-	// skip hot region
-	if m.cluster.IsRegionHot(region) {
-		checkerCounter.WithLabelValues("merge_checker", "hot-region").Inc()
+	if reason := m.checkSourceReason(region); reason != "" {
+		checkerCounter.WithLabelValues("merge_checker", reason).Inc()
 		return nil
 	}
 
@@ -138,8 +112,13 @@ func (m *MergeChecker) Check(region *core.RegionInfo) []*operator.Operator {
 	log.Debug("try to merge region",
 		logutil.ZapRedactStringer("from", core.RegionToHexMeta(region.GetMeta())),
 		logutil.ZapRedactStringer("to", core.RegionToHexMeta(target.GetMeta())))
-	ops, err := operator.CreateMergeRegionOperator("merge-region", m.cluster, region, target, operator.OpMerge)
+	ops, err := m.createMergeOperator(region, target)
 	if err != nil {
+		if _, ok := errs.IsRetryable(err); ok {
+			checkerCounter.WithLabelValues("merge_checker", "retry-later").Inc()
+			log.Debug("cannot merge region now", errs.ZapError(err))
+			return nil
+		}
 		log.Warn("create merge region operator failed", errs.ZapError(err))
 		return nil
 	}
@@ -151,10 +130,126 @@ func (m *MergeChecker) Check(region *core.RegionInfo) []*operator.Operator {
 	return ops
 }
 
+// createMergeOperator creates the merge operators. The regions in the joint
+// state cannot be merged until the conf change is finished, so it returns a
+// RetryableError in that case.
+func (m *MergeChecker) createMergeOperator(region, target *core.RegionInfo) ([]*operator.Operator, error) {
+	if core.IsInJointState(region.GetPeers()...) || core.IsInJointState(target.GetPeers()...) {
+		return nil, errs.NewRetryableError(errors.New("cannot merge regions which are in joint state"), m.opts.GetPatrolRegionInterval())
+	}
+	return operator.CreateMergeRegionOperator("merge-region", m.cluster, region, target, operator.OpMerge)
+}
+
 func (m *MergeChecker) checkTarget(region, adjacent *core.RegionInfo) bool {
-	return adjacent != nil && !m.splitCache.Exists(adjacent.GetID()) && !m.cluster.IsRegionHot(adjacent) &&
-		AllowMerge(m.cluster, region, adjacent) && opt.IsRegionHealthy(m.cluster, adjacent) &&
-		opt.IsRegionReplicated(m.cluster, adjacent)
+	return adjacent != nil && m.checkTargetReason(region, adjacent) == ""
+}
+
+// checkTargetReason returns the reason why the region cannot be merged with the
+// adjacent region, or an empty string if they can be merged.
+func (m *MergeChecker) checkTargetReason(region, adjacent *core.RegionInfo) string {
+	switch {
+	case m.splitCache.Exists(adjacent.GetID()):
+		return "target-recently-split"
+	case m.cluster.IsRegionHot(adjacent):
+		return "target-hot-region"
+	case !AllowMerge(m.cluster, region, adjacent):
+		return "not-allowed"
+	case !opt.IsRegionHealthy(m.cluster, adjacent):
+		return "target-special-peer"
+	case !opt.IsRegionReplicated(m.cluster, adjacent):
+		return "target-abnormal-replica"
+	}
+	return ""
+}
+
+// checkSourceReason returns the reason why the region cannot be merged with any
+// other region, or an empty string if it can be merged.
+func (m *MergeChecker) checkSourceReason(region *core.RegionInfo) string {
+	switch {
+	// when pd just started, it will load region meta from etcd
+	// but the size for these loaded region info is 0
+	// pd don't know the real size of one region until the first heartbeat of the region
+	// thus here when size is 0, just skip.
+	case region.GetApproximateSize() == 0:
+		return "skip"
+	// region is not small enough
+	case region.GetApproximateSize() > int64(m.opts.GetMaxMergeRegionSize()) ||
+		region.GetApproximateKeys() > int64(m.opts.GetMaxMergeRegionKeys()):
+		return "no-need"
+	// skip region has down peers or pending peers or learner peers
+	case !opt.IsRegionHealthy(m.cluster, region):
+		return "special-peer"
+	case !opt.IsRegionReplicated(m.cluster, region):
+		return "abnormal-replica"
+	// skip hot region
+	case m.cluster.IsRegionHot(region):
+		return "hot-region"
+	}
+	return ""
+}
+
+// MergeCandidate is an adjacent region which the region may be merged with.
+type MergeCandidate struct {
+	AdjacentRegion *core.RegionInfo
+	// CombinedSize is the approximate size in MB of the region after merging.
+	CombinedSize int64
+	// Score is larger if the candidate is more preferred, it is 0 if the merge is blocked.
+	Score float64
+	// BlockingReason is the reason why the regions cannot be merged, it is empty if they can.
+	BlockingReason string
+}
+
+// GetMergeCandidates returns at most maxResults adjacent regions of the region
+// with the reason why they are not merged, the more preferred ones come first.
+// 0 means no limit of the result count. It is used for diagnosis and does not
+// create any operator.
+func (m *MergeChecker) GetMergeCandidates(region *core.RegionInfo, maxResults int) []*MergeCandidate {
+	var sourceReason string
+	switch {
+	case time.Now().Before(m.startTime.Add(m.opts.GetSplitMergeInterval())):
+		sourceReason = "recently-start"
+	case m.splitCache.Exists(region.GetID()):
+		sourceReason = "recently-split"
+	default:
+		sourceReason = m.checkSourceReason(region)
+	}
+	prev, next := m.cluster.GetAdjacentRegions(region)
+	var candidates []*MergeCandidate
+	for _, adjacent := range []*core.RegionInfo{next, prev} {
+		if adjacent == nil {
+			continue
+		}
+		candidate := &MergeCandidate{
+			AdjacentRegion: adjacent,
+			CombinedSize:   region.GetApproximateSize() + adjacent.GetApproximateSize(),
+		}
+		switch {
+		case sourceReason != "":
+			candidate.BlockingReason = sourceReason
+		case adjacent == prev && m.opts.IsOneWayMergeEnabled():
+			candidate.BlockingReason = "one-way-merge"
+		default:
+			candidate.BlockingReason = m.checkTargetReason(region, adjacent)
+		}
+		if candidate.BlockingReason == "" && adjacent.GetApproximateSize() > maxTargetRegionSize {
+			candidate.BlockingReason = "target-too-large"
+		}
+		if candidate.BlockingReason == "" && (core.IsInJointState(region.GetPeers()...) || core.IsInJointState(adjacent.GetPeers()...)) {
+			candidate.BlockingReason = "joint-state"
+		}
+		if candidate.BlockingReason == "" {
+			// the smaller target is preferred, the same as Check.
+			candidate.Score = 1 / float64(1+adjacent.GetApproximateSize())
+		}
+		candidates = append(candidates, candidate)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if maxResults > 0 && len(candidates) > maxResults {
+		candidates = candidates[:maxResults]
+	}
+	return candidates
 }
 
 // AllowMerge returns true if two regions can be merged according to the key type.
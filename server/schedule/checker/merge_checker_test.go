@@ -211,6 +211,45 @@ func (s *testMergeCheckerSuite) TestBasic(c *C) {
 	c.Assert(ops, IsNil)
 }
 
+func (s *testMergeCheckerSuite) TestGetMergeCandidates(c *C) {
+	s.cluster.SetSplitMergeInterval(0)
+
+	candidates := s.mc.GetMergeCandidates(s.regions[2], 0)
+	c.Assert(candidates, HasLen, 2)
+	// the previous region is preferred as the next one does not have enough replicas.
+	c.Assert(candidates[0].AdjacentRegion.GetID(), Equals, s.regions[1].GetID())
+	c.Assert(candidates[0].CombinedSize, Equals, int64(201))
+	c.Assert(candidates[0].BlockingReason, Equals, "")
+	c.Assert(candidates[0].Score, Greater, 0.0)
+	c.Assert(candidates[1].AdjacentRegion.GetID(), Equals, s.regions[3].GetID())
+	c.Assert(candidates[1].CombinedSize, Equals, int64(2))
+	c.Assert(candidates[1].BlockingReason, Equals, "target-abnormal-replica")
+	c.Assert(candidates[1].Score, Equals, 0.0)
+	c.Assert(s.mc.GetMergeCandidates(s.regions[2], 1), HasLen, 1)
+
+	// Enable one way merge
+	s.cluster.SetEnableOneWayMerge(true)
+	candidates = s.mc.GetMergeCandidates(s.regions[2], 0)
+	c.Assert(candidates, HasLen, 2)
+	c.Assert(candidates[1].AdjacentRegion.GetID(), Equals, s.regions[1].GetID())
+	c.Assert(candidates[1].BlockingReason, Equals, "one-way-merge")
+	s.cluster.SetEnableOneWayMerge(false)
+
+	// target region size is too large
+	s.cluster.PutRegion(s.regions[1].Clone(core.SetApproximateSize(600)))
+	candidates = s.mc.GetMergeCandidates(s.regions[2], 0)
+	c.Assert(candidates, HasLen, 2)
+	c.Assert(candidates[1].AdjacentRegion.GetID(), Equals, s.regions[1].GetID())
+	c.Assert(candidates[1].CombinedSize, Equals, int64(601))
+	c.Assert(candidates[1].BlockingReason, Equals, "target-too-large")
+
+	// the source region is not small enough.
+	for _, candidate := range s.mc.GetMergeCandidates(s.regions[1], 0) {
+		c.Assert(candidate.BlockingReason, Equals, "no-need")
+		c.Assert(candidate.Score, Equals, 0.0)
+	}
+}
+
 func (s *testMergeCheckerSuite) checkSteps(c *C, op *operator.Operator, steps []operator.OpStep) {
 	c.Assert(op.Kind()&operator.OpMerge, Not(Equals), 0)
 	c.Assert(steps, NotNil)
@@ -16,6 +16,7 @@ package checker
 import (
 	"fmt"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/cache"
@@ -36,6 +37,27 @@ const (
 	downStatus    = "down"
 )
 
+// maxFixPlanRounds limits the rounds to simulate when generating the fix plan,
+// it is enough for any reasonable replica count.
+const maxFixPlanRounds = 16
+
+// PlanStepType is the type of a step in the fix plan.
+type PlanStepType string
+
+// Types of the steps in the fix plan.
+const (
+	PlanStepAddPeer    PlanStepType = "add-peer"
+	PlanStepRemovePeer PlanStepType = "remove-peer"
+)
+
+// PlanStep is a step of the plan to fix the replicas of a region.
+type PlanStep struct {
+	Type    PlanStepType `json:"type"`
+	StoreID uint64       `json:"store-id"`
+	// Desc is the same as the description of the operator created by Check.
+	Desc string `json:"desc"`
+}
+
 // ReplicaChecker ensures region has the best replicas.
 // Including the following:
 // Replica number management.
@@ -45,6 +67,9 @@ type ReplicaChecker struct {
 	cluster           opt.Cluster
 	opts              *config.PersistOptions
 	regionWaitingList cache.Cache
+	// dryRun is set for the checker generating the fix plan, which neither
+	// updates the metrics nor puts the region into the waiting list.
+	dryRun bool
 }
 
 // NewReplicaChecker creates a replica checker.
@@ -61,30 +86,37 @@ func (r *ReplicaChecker) GetType() string {
 	return "replica-checker"
 }
 
+func (r *ReplicaChecker) incCounter(label string) {
+	if r.dryRun {
+		return
+	}
+	checkerCounter.WithLabelValues("replica_checker", label).Inc()
+}
+
 // Check verifies a region's replicas, creating an operator.Operator if need.
 func (r *ReplicaChecker) Check(region *core.RegionInfo) *operator.Operator {
-	checkerCounter.WithLabelValues("replica_checker", "check").Inc()
+	r.incCounter("check")
 	if op := r.checkDownPeer(region); op != nil {
-		checkerCounter.WithLabelValues("replica_checker", "new-operator").Inc()
+		r.incCounter("new-operator")
 		op.SetPriorityLevel(core.HighPriority)
 		return op
 	}
 	if op := r.checkOfflinePeer(region); op != nil {
-		checkerCounter.WithLabelValues("replica_checker", "new-operator").Inc()
+		r.incCounter("new-operator")
 		op.SetPriorityLevel(core.HighPriority)
 		return op
 	}
 	if op := r.checkMakeUpReplica(region); op != nil {
-		checkerCounter.WithLabelValues("replica_checker", "new-operator").Inc()
+		r.incCounter("new-operator")
 		op.SetPriorityLevel(core.HighPriority)
 		return op
 	}
 	if op := r.checkRemoveExtraReplica(region); op != nil {
-		checkerCounter.WithLabelValues("replica_checker", "new-operator").Inc()
+		r.incCounter("new-operator")
 		return op
 	}
 	if op := r.checkLocationReplacement(region); op != nil {
-		checkerCounter.WithLabelValues("replica_checker", "new-operator").Inc()
+		r.incCounter("new-operator")
 		return op
 	}
 	return nil
@@ -156,15 +188,14 @@ func (r *ReplicaChecker) checkMakeUpReplica(region *core.RegionInfo) *operator.O
 	regionStores := r.cluster.GetRegionStores(region)
 	target := r.strategy(region).SelectStoreToAdd(regionStores)
 	if target == 0 {
-		log.Debug("no store to add replica", zap.Uint64("region-id", region.GetID()))
-		checkerCounter.WithLabelValues("replica_checker", "no-target-store").Inc()
-		r.regionWaitingList.Put(region.GetID(), nil)
+		r.incCounter("no-target-store")
+		r.handleFailure(region, r.retryableError(errors.New("no store to add replica")))
 		return nil
 	}
 	newPeer := &metapb.Peer{StoreId: target}
 	op, err := operator.CreateAddPeerOperator("make-up-replica", r.cluster, region, newPeer, operator.OpReplica)
 	if err != nil {
-		log.Debug("create make-up-replica operator fail", errs.ZapError(err))
+		r.handleFailure(region, errors.Annotate(err, "create make-up-replica operator fail"))
 		return nil
 	}
 	return op
@@ -183,13 +214,13 @@ func (r *ReplicaChecker) checkRemoveExtraReplica(region *core.RegionInfo) *opera
 	regionStores := r.cluster.GetRegionStores(region)
 	old := r.strategy(region).SelectStoreToRemove(regionStores)
 	if old == 0 {
-		checkerCounter.WithLabelValues("replica_checker", "no-worst-peer").Inc()
-		r.regionWaitingList.Put(region.GetID(), nil)
+		r.incCounter("no-worst-peer")
+		r.handleFailure(region, r.retryableError(errors.New("no peer to remove")))
 		return nil
 	}
-	op, err := operator.CreateRemovePeerOperator("remove-extra-replica", r.cluster, operator.OpReplica, region, old)
+	op, err := operator.CreateRemovePeerOperator("remove-extra-replica", r.cluster, operator.OpReplica, region, false, old)
 	if err != nil {
-		checkerCounter.WithLabelValues("replica_checker", "create-operator-fail").Inc()
+		r.incCounter("create-operator-fail")
 		return nil
 	}
 	return op
@@ -204,20 +235,20 @@ func (r *ReplicaChecker) checkLocationReplacement(region *core.RegionInfo) *oper
 	regionStores := r.cluster.GetRegionStores(region)
 	oldStore := strategy.SelectStoreToRemove(regionStores)
 	if oldStore == 0 {
-		checkerCounter.WithLabelValues("replica_checker", "all-right").Inc()
+		r.incCounter("all-right")
 		return nil
 	}
 	newStore := strategy.SelectStoreToImprove(regionStores, oldStore)
 	if newStore == 0 {
 		log.Debug("no better peer", zap.Uint64("region-id", region.GetID()))
-		checkerCounter.WithLabelValues("replica_checker", "not-better").Inc()
+		r.incCounter("not-better")
 		return nil
 	}
 
 	newPeer := &metapb.Peer{StoreId: newStore}
 	op, err := operator.CreateMovePeerOperator("move-to-better-location", r.cluster, region, operator.OpReplica, oldStore, newPeer)
 	if err != nil {
-		checkerCounter.WithLabelValues("replica_checker", "create-operator-fail").Inc()
+		r.incCounter("create-operator-fail")
 		return nil
 	}
 	return op
@@ -227,10 +258,10 @@ func (r *ReplicaChecker) fixPeer(region *core.RegionInfo, storeID uint64, status
 	// Check the number of replicas first.
 	if len(region.GetVoters()) > r.opts.GetMaxReplicas() {
 		removeExtra := fmt.Sprintf("remove-extra-%s-replica", status)
-		op, err := operator.CreateRemovePeerOperator(removeExtra, r.cluster, operator.OpReplica, region, storeID)
+		op, err := operator.CreateRemovePeerOperator(removeExtra, r.cluster, operator.OpReplica, region, false, storeID)
 		if err != nil {
 			reason := fmt.Sprintf("%s-fail", removeExtra)
-			checkerCounter.WithLabelValues("replica_checker", reason).Inc()
+			r.incCounter(reason)
 			return nil
 		}
 		return op
@@ -240,9 +271,8 @@ func (r *ReplicaChecker) fixPeer(region *core.RegionInfo, storeID uint64, status
 	target := r.strategy(region).SelectStoreToReplace(regionStores, storeID)
 	if target == 0 {
 		reason := fmt.Sprintf("no-store-%s", status)
-		checkerCounter.WithLabelValues("replica_checker", reason).Inc()
-		r.regionWaitingList.Put(region.GetID(), nil)
-		log.Debug("no best store to add replica", zap.Uint64("region-id", region.GetID()))
+		r.incCounter(reason)
+		r.handleFailure(region, r.retryableError(errors.New("no best store to add replica")))
 		return nil
 	}
 	newPeer := &metapb.Peer{StoreId: target}
@@ -250,12 +280,79 @@ func (r *ReplicaChecker) fixPeer(region *core.RegionInfo, storeID uint64, status
 	op, err := operator.CreateMovePeerOperator(replace, r.cluster, region, operator.OpReplica, storeID, newPeer)
 	if err != nil {
 		reason := fmt.Sprintf("%s-fail", replace)
-		checkerCounter.WithLabelValues("replica_checker", reason).Inc()
+		r.incCounter(reason)
 		return nil
 	}
 	return op
 }
 
+// retryableError marks the failure as transient, which may be fixed in the
+// next patrol, such as a store becoming available.
+func (r *ReplicaChecker) retryableError(err error) error {
+	return errs.NewRetryableError(err, r.opts.GetPatrolRegionInterval())
+}
+
+// handleFailure logs the reason why the region cannot be fixed, and puts the
+// region into the waiting list to check it again soon if the failure is transient.
+func (r *ReplicaChecker) handleFailure(region *core.RegionInfo, err error) {
+	if r.dryRun {
+		return
+	}
+	log.Debug("fail to fix replica", zap.Uint64("region-id", region.GetID()), errs.ZapError(err))
+	if _, ok := errs.IsRetryable(err); ok {
+		r.regionWaitingList.Put(region.GetID(), nil)
+	}
+}
+
+// GetFixPlan returns all the steps needed to bring the region to the desired
+// replica state. It runs Check on a copy of the region repeatedly, applying
+// the steps of each operator to the copy, and never changes the checker or
+// the region.
+func (r *ReplicaChecker) GetFixPlan(region *core.RegionInfo) []PlanStep {
+	sim := &ReplicaChecker{
+		cluster: r.cluster,
+		opts:    r.opts,
+		dryRun:  true,
+	}
+	var plan []PlanStep
+	for i := 0; i < maxFixPlanRounds; i++ {
+		op := sim.Check(region)
+		if op == nil {
+			break
+		}
+		for j := 0; j < op.Len(); j++ {
+			step := op.Step(j)
+			plan = append(plan, planSteps(step, op.Desc())...)
+			region = operator.SimulateStep(region, step)
+		}
+	}
+	return plan
+}
+
+// planSteps converts the operator step to the steps of the plan. The steps
+// only changing the role of a peer are omitted.
+func planSteps(step operator.OpStep, desc string) []PlanStep {
+	switch s := step.(type) {
+	case operator.AddPeer:
+		return []PlanStep{{Type: PlanStepAddPeer, StoreID: s.ToStore, Desc: desc}}
+	case operator.AddLightPeer:
+		return []PlanStep{{Type: PlanStepAddPeer, StoreID: s.ToStore, Desc: desc}}
+	case operator.AddLearner:
+		return []PlanStep{{Type: PlanStepAddPeer, StoreID: s.ToStore, Desc: desc}}
+	case operator.AddLightLearner:
+		return []PlanStep{{Type: PlanStepAddPeer, StoreID: s.ToStore, Desc: desc}}
+	case operator.RemovePeer:
+		return []PlanStep{{Type: PlanStepRemovePeer, StoreID: s.FromStore, Desc: desc}}
+	case operator.BatchRemovePeer:
+		steps := make([]PlanStep, 0, len(s.FromStores))
+		for _, storeID := range s.FromStores {
+			steps = append(steps, PlanStep{Type: PlanStepRemovePeer, StoreID: storeID, Desc: desc})
+		}
+		return steps
+	}
+	return nil
+}
+
 func (r *ReplicaChecker) strategy(region *core.RegionInfo) *ReplicaStrategy {
 	return &ReplicaStrategy{
 		checkerName:    replicaCheckerName,
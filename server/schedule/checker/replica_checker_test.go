@@ -16,6 +16,7 @@ package checker
 import (
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
@@ -32,6 +33,7 @@ import (
 const (
 	KB = 1024
 	MB = 1024 * KB
+	GB = 1024 * MB
 )
 
 var _ = Suite(&testReplicaCheckerSuite{})
@@ -47,8 +49,8 @@ func (s *testReplicaCheckerSuite) SetUpTest(c *C) {
 	s.cluster.DisableFeature(versioninfo.JointConsensus)
 	s.rc = NewReplicaChecker(s.cluster, cache.NewDefaultCache(10))
 	stats := &pdpb.StoreStats{
-		Capacity:  100,
-		Available: 100,
+		Capacity:  100 * GB,
+		Available: 100 * GB,
 	}
 	stores := []*core.StoreInfo{
 		core.NewStoreInfo(
@@ -338,6 +340,43 @@ func (s *testReplicaCheckerSuite) TestOffline(c *C) {
 	c.Assert(rc.Check(region), IsNil)
 }
 
+func (s *testReplicaCheckerSuite) TestGetFixPlan(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	tc.DisableFeature(versioninfo.JointConsensus)
+	tc.SetMaxReplicas(3)
+	tc.SetLocationLabels([]string{"zone", "rack", "host"})
+
+	rc := NewReplicaChecker(tc, cache.NewDefaultCache(10))
+
+	tc.AddLabelsStore(1, 1, map[string]string{"zone": "z1", "rack": "r1", "host": "h1"})
+	tc.AddLabelsStore(2, 2, map[string]string{"zone": "z2", "rack": "r1", "host": "h1"})
+	tc.AddLabelsStore(3, 3, map[string]string{"zone": "z3", "rack": "r1", "host": "h1"})
+	tc.AddLabelsStore(4, 4, map[string]string{"zone": "z3", "rack": "r2", "host": "h1"})
+
+	// The region needs 2 more replicas, the second one is selected after the first one is added.
+	tc.AddLeaderRegion(1, 1)
+	region := tc.GetRegion(1)
+	c.Assert(rc.GetFixPlan(region), DeepEquals, []PlanStep{
+		{Type: PlanStepAddPeer, StoreID: 2, Desc: "make-up-replica"},
+		{Type: PlanStepAddPeer, StoreID: 3, Desc: "make-up-replica"},
+	})
+	// The region itself is not changed.
+	c.Assert(region.GetPeers(), HasLen, 1)
+
+	// The offline peer is replaced.
+	tc.AddLeaderRegion(2, 1, 2, 3)
+	tc.SetStoreOffline(3)
+	c.Assert(rc.GetFixPlan(tc.GetRegion(2)), DeepEquals, []PlanStep{
+		{Type: PlanStepAddPeer, StoreID: 4, Desc: "replace-offline-replica"},
+		{Type: PlanStepRemovePeer, StoreID: 3, Desc: "replace-offline-replica"},
+	})
+
+	// Nothing to do for a healthy region.
+	tc.AddLeaderRegion(3, 1, 2, 4)
+	c.Assert(rc.GetFixPlan(tc.GetRegion(3)), HasLen, 0)
+}
+
 func (s *testReplicaCheckerSuite) TestDistinctScore(c *C) {
 	opt := config.NewTestOptions()
 	tc := mockcluster.NewCluster(opt)
@@ -483,6 +522,32 @@ func (s *testReplicaCheckerSuite) TestStorageThreshold(c *C) {
 	testutil.CheckAddPeer(c, rc.Check(region), operator.OpReplica, 2)
 }
 
+func (s *testReplicaCheckerSuite) TestWriteBandwidth(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	tc.DisableFeature(versioninfo.JointConsensus)
+	rc := NewReplicaChecker(tc, cache.NewDefaultCache(10))
+
+	for i := uint64(1); i <= 4; i++ {
+		tc.AddRegionStore(i, 1)
+	}
+	tc.AddLeaderRegion(1, 1, 2)
+	setWriteBandwidth := func(storeID uint64, mbps uint64) {
+		store := tc.GetStore(storeID)
+		stats := proto.Clone(store.GetStoreStats()).(*pdpb.StoreStats)
+		stats.BytesWritten = mbps * MB * 10
+		stats.Interval = &pdpb.TimeInterval{StartTimestamp: 100, EndTimestamp: 110}
+		tc.PutStore(store.Clone(core.SetStoreStats(stats)))
+	}
+
+	// Store 3 and 4 have the same region score, the one with less write bandwidth is better.
+	setWriteBandwidth(3, 100)
+	setWriteBandwidth(4, 10)
+	testutil.CheckAddPeer(c, rc.Check(tc.GetRegion(1)), operator.OpReplica, 4)
+	setWriteBandwidth(4, 200)
+	testutil.CheckAddPeer(c, rc.Check(tc.GetRegion(1)), operator.OpReplica, 3)
+}
+
 func (s *testReplicaCheckerSuite) TestOpts(c *C) {
 	opt := config.NewTestOptions()
 	tc := mockcluster.NewCluster(opt)
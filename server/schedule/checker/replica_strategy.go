@@ -54,6 +54,7 @@ func (s *ReplicaStrategy) SelectStoreToAdd(coLocationStores []*core.StoreInfo, e
 	filters := []filter.Filter{
 		filter.NewExcludedFilter(s.checkerName, nil, s.region.GetStoreIds()),
 		filter.NewStorageThresholdFilter(s.checkerName),
+		filter.NewRegionCountFilter(s.checkerName),
 		filter.NewSpecialUseFilter(s.checkerName),
 		&filter.StoreStateFilter{ActionScope: s.checkerName, MoveRegion: true, AllowTemporaryStates: true},
 	}
@@ -68,11 +69,20 @@ func (s *ReplicaStrategy) SelectStoreToAdd(coLocationStores []*core.StoreInfo, e
 	}
 
 	isolationComparer := filter.IsolationComparer(s.locationLabels, coLocationStores)
+	regionScoreComparer := filter.RegionScoreComparer(s.cluster.GetOpts())
+	writeBandwidthComparer := filter.WriteBandwidthComparer(s.cluster.GetOpts())
+	// less region score is better, then less write bandwidth is better
+	capacityComparer := func(a, b *core.StoreInfo) int {
+		if r := regionScoreComparer(a, b); r != 0 {
+			return r
+		}
+		return writeBandwidthComparer(a, b)
+	}
 	strictStateFilter := &filter.StoreStateFilter{ActionScope: s.checkerName, MoveRegion: true}
 	target := filter.NewCandidates(s.cluster.GetStores()).
 		FilterTarget(s.cluster.GetOpts(), filters...).
 		Sort(isolationComparer).Reverse().Top(isolationComparer).        // greater isolation score is better
-		Sort(filter.RegionScoreComparer(s.cluster.GetOpts())).           // less region score is better
+		Sort(capacityComparer).                                          // less region score and write bandwidth is better
 		FilterTarget(s.cluster.GetOpts(), strictStateFilter).PickFirst() // the filter does not ignore temp states
 	if target == nil {
 		return 0
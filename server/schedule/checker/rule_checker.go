@@ -14,6 +14,12 @@
 package checker
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
 	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
@@ -34,15 +40,30 @@ type RuleChecker struct {
 	ruleManager       *placement.RuleManager
 	name              string
 	regionWaitingList cache.Cache
+	// orphanPeerFixes is the number of orphan peer removal operators created
+	// in the current patrol round. It is accessed atomically since the patrol
+	// resets it while the checker may be called by others.
+	orphanPeerFixes uint64
+	// roleConversions records the peers whose role is converted recently, the
+	// key is "regionID-peerID".
+	roleConversions *cache.TTLString
+	// pendingPeers records when a peer is found pending for the first time,
+	// the key is "regionID-peerID".
+	pendingPeers *cache.TTLString
+	// dryRun is set for the simulation, which neither changes the records above
+	// nor the metrics. See newSimulation.
+	dryRun bool
 }
 
 // NewRuleChecker creates a checker instance.
-func NewRuleChecker(cluster opt.Cluster, ruleManager *placement.RuleManager, regionWaitingList cache.Cache) *RuleChecker {
+func NewRuleChecker(ctx context.Context, cluster opt.Cluster, ruleManager *placement.RuleManager, regionWaitingList cache.Cache) *RuleChecker {
 	return &RuleChecker{
 		cluster:           cluster,
 		ruleManager:       ruleManager,
 		name:              "rule-checker",
 		regionWaitingList: regionWaitingList,
+		roleConversions:   cache.NewStringTTL(ctx, time.Minute, time.Minute),
+		pendingPeers:      cache.NewStringTTL(ctx, time.Minute, time.Minute),
 	}
 }
 
@@ -54,11 +75,11 @@ func (c *RuleChecker) GetType() string {
 // Check checks if the region matches placement rules and returns Operator to
 // fix it.
 func (c *RuleChecker) Check(region *core.RegionInfo) *operator.Operator {
-	checkerCounter.WithLabelValues("rule_checker", "check").Inc()
+	c.incCounter("check")
 
 	fit := c.cluster.FitRegion(region)
 	if len(fit.RuleFits) == 0 {
-		checkerCounter.WithLabelValues("rule_checker", "fix-range").Inc()
+		c.incCounter("fix-range")
 		// If the region matches no rules, the most possible reason is it spans across
 		// multiple rules.
 		return c.fixRange(region)
@@ -72,6 +93,10 @@ func (c *RuleChecker) Check(region *core.RegionInfo) *operator.Operator {
 		op, err := c.fixRulePeer(region, fit, rf)
 		if err != nil {
 			log.Debug("fail to fix rule peer", zap.String("rule-group", rf.Rule.GroupID), zap.String("rule-id", rf.Rule.ID), errs.ZapError(err))
+			// the region will be checked again soon if the failure is transient.
+			if _, ok := errs.IsRetryable(err); ok {
+				c.regionWaitingList.Put(region.GetID(), nil)
+			}
 			continue
 		}
 		if op != nil {
@@ -81,13 +106,86 @@ func (c *RuleChecker) Check(region *core.RegionInfo) *operator.Operator {
 	return nil
 }
 
+// newSimulation returns a checker to simulate the fixes of this checker. It
+// reads the role conversions and the pending peers recorded by this checker,
+// but has its own count of the orphan peer fixes, which starts from 0.
+func (c *RuleChecker) newSimulation() *RuleChecker {
+	return &RuleChecker{
+		cluster:         c.cluster,
+		ruleManager:     c.ruleManager,
+		name:            c.name,
+		roleConversions: c.roleConversions,
+		pendingPeers:    c.pendingPeers,
+		dryRun:          true,
+	}
+}
+
+func (c *RuleChecker) incCounter(label string) {
+	if c.dryRun {
+		return
+	}
+	checkerCounter.WithLabelValues("rule_checker", label).Inc()
+}
+
+// GetOrphanPeerCount returns the number of peers of the region which match no
+// placement rule. It does not change any state of the checker.
+func (c *RuleChecker) GetOrphanPeerCount(region *core.RegionInfo) int {
+	return len(c.cluster.FitRegion(region).OrphanPeers)
+}
+
+// Simulate runs the same fixes as Check, but returns all the operators which can
+// be created for the region rather than the first one. It does not change the
+// state of the checker and the operators are never added to the operator controller.
+func (c *RuleChecker) Simulate(region *core.RegionInfo) ([]*operator.Operator, *placement.RegionFit, error) {
+	if !c.cluster.GetOpts().IsPlacementRulesEnabled() {
+		return nil, nil, errors.New("placement rules feature is disabled")
+	}
+	sim := c.newSimulation()
+
+	fit := c.cluster.FitRegion(region)
+	if len(fit.RuleFits) == 0 {
+		if op := sim.fixRange(region); op != nil {
+			return []*operator.Operator{op}, fit, nil
+		}
+		return nil, fit, nil
+	}
+	var ops []*operator.Operator
+	op, err := sim.fixOrphanPeers(region, fit)
+	if err != nil {
+		log.Debug("fail to fix orphan peer", errs.ZapError(err))
+	} else if op != nil {
+		ops = append(ops, op)
+	}
+	for _, rf := range fit.RuleFits {
+		op, err := sim.fixRulePeer(region, fit, rf)
+		if err != nil {
+			log.Debug("fail to fix rule peer", zap.String("rule-group", rf.Rule.GroupID), zap.String("rule-id", rf.Rule.ID), errs.ZapError(err))
+			continue
+		}
+		if op != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops, fit, nil
+}
+
+// ProposeSplitKeys returns the keys to split the region at the boundaries of the
+// placement rules. Unlike Check, it never creates an operator, so diagnostic tools
+// can use it to understand the split decisions without triggering the split.
+func (c *RuleChecker) ProposeSplitKeys(region *core.RegionInfo) ([][]byte, error) {
+	if !c.cluster.GetOpts().IsPlacementRulesEnabled() {
+		return nil, errors.New("placement rules feature is disabled")
+	}
+	return c.ruleManager.GetSplitKeys(region.GetStartKey(), region.GetEndKey()), nil
+}
+
 func (c *RuleChecker) fixRange(region *core.RegionInfo) *operator.Operator {
-	keys := c.ruleManager.GetSplitKeys(region.GetStartKey(), region.GetEndKey())
-	if len(keys) == 0 {
+	keys, err := c.ProposeSplitKeys(region)
+	if err != nil || len(keys) == 0 {
 		return nil
 	}
 
-	op, err := operator.CreateSplitRegionOperator("rule-split-region", region, 0, pdpb.CheckPolicy_USEKEY, keys)
+	op, err := operator.CreateSplitRegionOperator("rule-split-region", region, 0, c.splitPolicy(keys), keys)
 	if err != nil {
 		log.Debug("create split region operator failed", errs.ZapError(err))
 		return nil
@@ -96,20 +194,31 @@ func (c *RuleChecker) fixRange(region *core.RegionInfo) *operator.Operator {
 	return op
 }
 
+// splitPolicy returns the split policy of the rules which apply to the range
+// beginning with the first split key. USEKEY is used if no policy is set.
+func (c *RuleChecker) splitPolicy(keys [][]byte) pdpb.CheckPolicy {
+	for _, rule := range c.ruleManager.GetRulesByKey(keys[0]) {
+		if rule.SplitPolicy != "" {
+			return rule.SplitPolicy.CheckPolicy()
+		}
+	}
+	return pdpb.CheckPolicy_USEKEY
+}
+
 func (c *RuleChecker) fixRulePeer(region *core.RegionInfo, fit *placement.RegionFit, rf *placement.RuleFit) (*operator.Operator, error) {
 	// make up peers.
 	if len(rf.Peers) < rf.Rule.Count {
-		return c.addRulePeer(region, rf)
+		return c.addRulePeer(region, fit, rf)
 	}
 	// fix down/offline peers.
 	for _, peer := range rf.Peers {
 		if c.isDownPeer(region, peer) {
-			checkerCounter.WithLabelValues("rule_checker", "replace-down").Inc()
-			return c.replaceRulePeer(region, rf, peer, downStatus)
+			c.incCounter("replace-down")
+			return c.replaceRulePeer(region, fit, rf, peer, downStatus)
 		}
 		if c.isOfflinePeer(region, peer) {
-			checkerCounter.WithLabelValues("rule_checker", "replace-offline").Inc()
-			return c.replaceRulePeer(region, rf, peer, offlineStatus)
+			c.incCounter("replace-offline")
+			return c.replaceRulePeer(region, fit, rf, peer, offlineStatus)
 		}
 	}
 	// fix loose matched peers.
@@ -122,41 +231,55 @@ func (c *RuleChecker) fixRulePeer(region *core.RegionInfo, fit *placement.Region
 			return op, nil
 		}
 	}
-	return c.fixBetterLocation(region, rf)
+	return c.fixBetterLocation(region, fit, rf)
 }
 
-func (c *RuleChecker) addRulePeer(region *core.RegionInfo, rf *placement.RuleFit) (*operator.Operator, error) {
-	checkerCounter.WithLabelValues("rule_checker", "add-rule-peer").Inc()
+func (c *RuleChecker) addRulePeer(region *core.RegionInfo, fit *placement.RegionFit, rf *placement.RuleFit) (*operator.Operator, error) {
+	c.incCounter("add-rule-peer")
 	ruleStores := c.getRuleFitStores(rf)
 	store := c.strategy(region, rf.Rule).SelectStoreToAdd(ruleStores)
 	if store == 0 {
-		checkerCounter.WithLabelValues("rule_checker", "no-store-add").Inc()
-		c.regionWaitingList.Put(region.GetID(), nil)
-		return nil, errors.New("no store to add peer")
+		c.incCounter("no-store-add")
+		return nil, c.retryableError(errors.New("no store to add peer"))
 	}
 	peer := &metapb.Peer{StoreId: store, Role: rf.Rule.Role.MetaPeerRole()}
-	return operator.CreateAddPeerOperator("add-rule-peer", c.cluster, region, peer, operator.OpReplica)
+	op, err := operator.CreateAddPeerOperator("add-rule-peer", c.cluster, region, peer, operator.OpReplica)
+	return withFitSummary(op, err, fit)
 }
 
-func (c *RuleChecker) replaceRulePeer(region *core.RegionInfo, rf *placement.RuleFit, peer *metapb.Peer, status string) (*operator.Operator, error) {
+func (c *RuleChecker) replaceRulePeer(region *core.RegionInfo, fit *placement.RegionFit, rf *placement.RuleFit, peer *metapb.Peer, status string) (*operator.Operator, error) {
 	ruleStores := c.getRuleFitStores(rf)
 	store := c.strategy(region, rf.Rule).SelectStoreToReplace(ruleStores, peer.GetStoreId())
 	if store == 0 {
-		checkerCounter.WithLabelValues("rule_checker", "no-store-replace").Inc()
-		c.regionWaitingList.Put(region.GetID(), nil)
-		return nil, errors.New("no store to replace peer")
+		c.incCounter("no-store-replace")
+		return nil, c.retryableError(errors.New("no store to replace peer"))
 	}
 	newPeer := &metapb.Peer{StoreId: store, Role: rf.Rule.Role.MetaPeerRole()}
-	return operator.CreateMovePeerOperator("replace-rule-"+status+"-peer", c.cluster, region, operator.OpReplica, peer.StoreId, newPeer)
+	op, err := operator.CreateMovePeerOperator("replace-rule-"+status+"-peer", c.cluster, region, operator.OpReplica, peer.StoreId, newPeer)
+	return withFitSummary(op, err, fit)
+}
+
+// retryableError marks the failure as transient, which may be fixed in the
+// next patrol, such as a store becoming available.
+func (c *RuleChecker) retryableError(err error) error {
+	return errs.NewRetryableError(err, c.cluster.GetOpts().GetPatrolRegionInterval())
 }
 
 func (c *RuleChecker) fixLooseMatchPeer(region *core.RegionInfo, fit *placement.RegionFit, rf *placement.RuleFit, peer *metapb.Peer) (*operator.Operator, error) {
 	if core.IsLearner(peer) && rf.Rule.Role != placement.Learner {
-		checkerCounter.WithLabelValues("rule_checker", "fix-peer-role").Inc()
-		return operator.CreatePromoteLearnerOperator("fix-peer-role", c.cluster, region, peer)
+		if c.inRoleConversionCooldown(region, peer) {
+			c.incCounter("role-conversion-cooldown")
+			return nil, nil
+		}
+		c.incCounter("fix-peer-role")
+		op, err := operator.CreatePromoteLearnerOperator("fix-peer-role", c.cluster, region, peer)
+		if err == nil {
+			c.recordRoleConversion(region, peer)
+		}
+		return op, err
 	}
 	if region.GetLeader().GetId() != peer.GetId() && rf.Rule.Role == placement.Leader {
-		checkerCounter.WithLabelValues("rule_checker", "fix-leader-role").Inc()
+		c.incCounter("fix-leader-role")
 		if c.allowLeader(fit, peer) {
 			return operator.CreateTransferLeaderOperator("fix-leader-role", c.cluster, region, region.GetLeader().StoreId, peer.GetStoreId(), 0)
 		}
@@ -164,18 +287,37 @@ func (c *RuleChecker) fixLooseMatchPeer(region *core.RegionInfo, fit *placement.
 		return nil, errors.New("peer cannot be leader")
 	}
 	if region.GetLeader().GetId() == peer.GetId() && rf.Rule.Role == placement.Follower {
-		checkerCounter.WithLabelValues("rule_checker", "fix-follower-role").Inc()
+		c.incCounter("fix-follower-role")
 		for _, p := range region.GetPeers() {
 			if c.allowLeader(fit, p) {
 				return operator.CreateTransferLeaderOperator("fix-follower-role", c.cluster, region, peer.GetStoreId(), p.GetStoreId(), 0)
 			}
 		}
-		checkerCounter.WithLabelValues("rule_checker", "no-new-leader").Inc()
-		return nil, errors.New("no new leader")
+		c.incCounter("no-new-leader")
+		return nil, c.retryableError(errors.New("no new leader"))
 	}
 	return nil, nil
 }
 
+func peerKey(region *core.RegionInfo, peer *metapb.Peer) string {
+	return fmt.Sprintf("%d-%d", region.GetID(), peer.GetId())
+}
+
+// inRoleConversionCooldown checks if the role of the peer was converted within
+// the cooldown, which avoids the role of a peer oscillating.
+func (c *RuleChecker) inRoleConversionCooldown(region *core.RegionInfo, peer *metapb.Peer) bool {
+	_, ok := c.roleConversions.Get(peerKey(region, peer))
+	return ok
+}
+
+func (c *RuleChecker) recordRoleConversion(region *core.RegionInfo, peer *metapb.Peer) {
+	cooldown := c.cluster.GetOpts().GetPeerRoleConversionCooldown()
+	if cooldown <= 0 || c.dryRun {
+		return
+	}
+	c.roleConversions.PutWithTTL(peerKey(region, peer), nil, cooldown)
+}
+
 func (c *RuleChecker) allowLeader(fit *placement.RegionFit, peer *metapb.Peer) bool {
 	if core.IsLearner(peer) {
 		return false
@@ -188,6 +330,9 @@ func (c *RuleChecker) allowLeader(fit *placement.RegionFit, peer *metapb.Peer) b
 	if !stateFilter.Target(c.cluster.GetOpts(), s) {
 		return false
 	}
+	if !(operator.TransferLeader{ToStore: peer.GetStoreId()}).CheckLabelPreference(c.cluster) {
+		return false
+	}
 	for _, rf := range fit.RuleFits {
 		if (rf.Rule.Role == placement.Leader || rf.Rule.Role == placement.Voter) &&
 			placement.MatchLabelConstraints(s, rf.Rule.LabelConstraints) {
@@ -197,7 +342,7 @@ func (c *RuleChecker) allowLeader(fit *placement.RegionFit, peer *metapb.Peer) b
 	return false
 }
 
-func (c *RuleChecker) fixBetterLocation(region *core.RegionInfo, rf *placement.RuleFit) (*operator.Operator, error) {
+func (c *RuleChecker) fixBetterLocation(region *core.RegionInfo, fit *placement.RegionFit, rf *placement.RuleFit) (*operator.Operator, error) {
 	if len(rf.Rule.LocationLabels) == 0 || rf.Rule.Count <= 1 {
 		return nil, nil
 	}
@@ -213,25 +358,51 @@ func (c *RuleChecker) fixBetterLocation(region *core.RegionInfo, rf *placement.R
 		log.Debug("no replacement store", zap.Uint64("region-id", region.GetID()))
 		return nil, nil
 	}
-	checkerCounter.WithLabelValues("rule_checker", "move-to-better-location").Inc()
+	c.incCounter("move-to-better-location")
 	newPeer := &metapb.Peer{StoreId: newStore, Role: rf.Rule.Role.MetaPeerRole()}
-	return operator.CreateMovePeerOperator("move-to-better-location", c.cluster, region, operator.OpReplica, oldStore, newPeer)
+	op, err := operator.CreateMovePeerOperator("move-to-better-location", c.cluster, region, operator.OpReplica, oldStore, newPeer)
+	return withFitSummary(op, err, fit)
 }
 
 func (c *RuleChecker) fixOrphanPeers(region *core.RegionInfo, fit *placement.RegionFit) (*operator.Operator, error) {
 	if len(fit.OrphanPeers) == 0 {
 		return nil, nil
 	}
-	// remove orphan peers only when all rules are satisfied (count+role)
-	for _, rf := range fit.RuleFits {
-		if !rf.IsSatisfied() {
-			checkerCounter.WithLabelValues("rule_checker", "skip-remove-orphan-peer").Inc()
-			return nil, nil
+	// an unhealthy orphan peer can be removed first since it does not serve anyway.
+	var peer *metapb.Peer
+	for _, p := range fit.OrphanPeers {
+		if c.isUnhealthyPeer(region, p) {
+			peer = p
+			break
 		}
 	}
-	checkerCounter.WithLabelValues("rule_checker", "remove-orphan-peer").Inc()
-	peer := fit.OrphanPeers[0]
-	return operator.CreateRemovePeerOperator("remove-orphan-peer", c.cluster, 0, region, peer.StoreId)
+	if peer == nil {
+		// remove healthy orphan peers only when all rules are satisfied (count+role)
+		for _, rf := range fit.RuleFits {
+			if !rf.IsSatisfied() {
+				c.incCounter("skip-remove-orphan-peer")
+				return nil, nil
+			}
+		}
+		peer = fit.OrphanPeers[0]
+	}
+	if limit := c.cluster.GetOpts().GetMaxOrphanPeerFixesPerCycle(); limit > 0 && atomic.LoadUint64(&c.orphanPeerFixes) >= limit {
+		c.incCounter("exceed-orphan-peer-limit")
+		return nil, nil
+	}
+	c.incCounter("remove-orphan-peer")
+	op, err := operator.CreateRemovePeerOperator("remove-orphan-peer", c.cluster, 0, region, false, peer.StoreId)
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddUint64(&c.orphanPeerFixes, 1)
+	return op, nil
+}
+
+// ResetOrphanPeerFixes resets the number of orphan peers removed in the
+// current patrol round.
+func (c *RuleChecker) ResetOrphanPeerFixes() {
+	atomic.StoreUint64(&c.orphanPeerFixes, 0)
 }
 
 func (c *RuleChecker) isDownPeer(region *core.RegionInfo, peer *metapb.Peer) bool {
@@ -256,6 +427,36 @@ func (c *RuleChecker) isDownPeer(region *core.RegionInfo, peer *metapb.Peer) boo
 	return false
 }
 
+// isUnhealthyPeer checks if the peer is down or has been pending longer than
+// the grace period. A peer is pending for a while after it is added since the
+// snapshot is still in transit, which should not be treated as unhealthy.
+func (c *RuleChecker) isUnhealthyPeer(region *core.RegionInfo, peer *metapb.Peer) bool {
+	if c.isDownPeer(region, peer) {
+		return true
+	}
+	key := peerKey(region, peer)
+	if region.GetPendingPeer(peer.GetId()) == nil {
+		if !c.dryRun {
+			c.pendingPeers.Remove(key)
+		}
+		return false
+	}
+	grace := c.cluster.GetOpts().GetPendingPeerGracePeriod()
+	if grace <= 0 {
+		return true
+	}
+	now := time.Now()
+	since := now
+	if v, ok := c.pendingPeers.Get(key); ok {
+		since = v.(time.Time)
+	}
+	// refresh the record, it is dropped if the region is not checked for a long time.
+	if !c.dryRun {
+		c.pendingPeers.PutWithTTL(key, since, 2*grace)
+	}
+	return now.Sub(since) >= grace
+}
+
 func (c *RuleChecker) isOfflinePeer(region *core.RegionInfo, peer *metapb.Peer) bool {
 	store := c.cluster.GetStore(peer.GetStoreId())
 	if store == nil {
@@ -285,3 +486,41 @@ func (c *RuleChecker) getRuleFitStores(rf *placement.RuleFit) []*core.StoreInfo
 	}
 	return stores
 }
+
+// ruleFitSummary is the summary of a rule fit in the operator.
+type ruleFitSummary struct {
+	GroupID      string `json:"group_id"`
+	ID           string `json:"id"`
+	Count        int    `json:"count"`
+	MatchedPeers int    `json:"matched_peers"`
+}
+
+// regionFitSummary is the summary of a region fit in the operator.
+type regionFitSummary struct {
+	Rules       []ruleFitSummary `json:"rules"`
+	OrphanPeers []*metapb.Peer   `json:"orphan_peers,omitempty"`
+}
+
+// withFitSummary attaches the summary of the region fit to the operator, so
+// it is possible to tell which rule triggers the operator.
+func withFitSummary(op *operator.Operator, err error, fit *placement.RegionFit) (*operator.Operator, error) {
+	if err != nil || op == nil {
+		return op, err
+	}
+	summary := regionFitSummary{OrphanPeers: fit.OrphanPeers}
+	for _, rf := range fit.RuleFits {
+		summary.Rules = append(summary.Rules, ruleFitSummary{
+			GroupID:      rf.Rule.GroupID,
+			ID:           rf.Rule.ID,
+			Count:        rf.Rule.Count,
+			MatchedPeers: len(rf.Peers),
+		})
+	}
+	data, err := json.Marshal(summary)
+	if err != nil {
+		log.Warn("failed to encode the region fit", zap.Uint64("region-id", op.RegionID()), errs.ZapError(err))
+		return op, nil
+	}
+	op.FitSummary = string(data)
+	return op, nil
+}
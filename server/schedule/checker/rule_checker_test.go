@@ -14,12 +14,16 @@
 package checker
 
 import (
+	"context"
 	"encoding/hex"
+	"sync/atomic"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/tikv/pd/pkg/cache"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
@@ -34,6 +38,8 @@ type testRuleCheckerSuite struct {
 	cluster     *mockcluster.Cluster
 	ruleManager *placement.RuleManager
 	rc          *RuleChecker
+	ctx         context.Context
+	cancel      context.CancelFunc
 }
 
 func (s *testRuleCheckerSuite) SetUpTest(c *C) {
@@ -42,7 +48,12 @@ func (s *testRuleCheckerSuite) SetUpTest(c *C) {
 	s.cluster.DisableFeature(versioninfo.JointConsensus)
 	s.cluster.SetEnablePlacementRules(true)
 	s.ruleManager = s.cluster.RuleManager
-	s.rc = NewRuleChecker(s.cluster, s.ruleManager, cache.NewDefaultCache(10))
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.rc = NewRuleChecker(s.ctx, s.cluster, s.ruleManager, cache.NewDefaultCache(10))
+}
+
+func (s *testRuleCheckerSuite) TearDownTest(c *C) {
+	s.cancel()
 }
 
 func (s *testRuleCheckerSuite) TestFixRange(c *C) {
@@ -66,6 +77,61 @@ func (s *testRuleCheckerSuite) TestFixRange(c *C) {
 	c.Assert(hex.EncodeToString(splitKeys[1]), Equals, "ff")
 }
 
+func (s *testRuleCheckerSuite) TestFixRangeSplitPolicy(c *C) {
+	s.cluster.AddLeaderStore(1, 1)
+	s.cluster.AddLeaderStore(2, 1)
+	s.cluster.AddLeaderStore(3, 1)
+	s.cluster.AddLeaderRegionWithRange(1, "", "", 1, 2, 3)
+	rule := &placement.Rule{
+		GroupID:     "test",
+		ID:          "test",
+		StartKeyHex: "AA",
+		EndKeyHex:   "FF",
+		Role:        placement.Voter,
+		Count:       1,
+	}
+	s.ruleManager.SetRule(rule)
+	op := s.rc.Check(s.cluster.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Step(0).(operator.SplitRegion).Policy, Equals, pdpb.CheckPolicy_USEKEY)
+
+	rule.SplitPolicy = placement.SplitApproximate
+	s.ruleManager.SetRule(rule)
+	op = s.rc.Check(s.cluster.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Step(0).(operator.SplitRegion).Policy, Equals, pdpb.CheckPolicy_APPROXIMATE)
+}
+
+func (s *testRuleCheckerSuite) TestProposeSplitKeys(c *C) {
+	s.cluster.AddLeaderStore(1, 1)
+	s.cluster.AddLeaderStore(2, 1)
+	s.cluster.AddLeaderStore(3, 1)
+	s.ruleManager.SetRule(&placement.Rule{
+		GroupID:     "test",
+		ID:          "test",
+		StartKeyHex: "AA",
+		EndKeyHex:   "FF",
+		Role:        placement.Voter,
+		Count:       1,
+	})
+	s.cluster.AddLeaderRegionWithRange(1, "", "", 1, 2, 3)
+
+	keys, err := s.rc.ProposeSplitKeys(s.cluster.GetRegion(1))
+	c.Assert(err, IsNil)
+	c.Assert(keys, HasLen, 2)
+	c.Assert(hex.EncodeToString(keys[0]), Equals, "aa")
+	c.Assert(hex.EncodeToString(keys[1]), Equals, "ff")
+
+	region := core.NewRegionInfo(&metapb.Region{Id: 2, StartKey: []byte{0xaa}, EndKey: []byte{0xff}}, nil)
+	keys, err = s.rc.ProposeSplitKeys(region)
+	c.Assert(err, IsNil)
+	c.Assert(keys, HasLen, 0)
+
+	s.cluster.SetEnablePlacementRules(false)
+	_, err = s.rc.ProposeSplitKeys(s.cluster.GetRegion(1))
+	c.Assert(err, NotNil)
+}
+
 func (s *testRuleCheckerSuite) TestAddRulePeer(c *C) {
 	s.cluster.AddLeaderStore(1, 1)
 	s.cluster.AddLeaderStore(2, 1)
@@ -75,6 +141,8 @@ func (s *testRuleCheckerSuite) TestAddRulePeer(c *C) {
 	c.Assert(op, NotNil)
 	c.Assert(op.Desc(), Equals, "add-rule-peer")
 	c.Assert(op.Step(0).(operator.AddLearner).ToStore, Equals, uint64(3))
+	c.Assert(op.FitSummary, Equals, `{"rules":[{"group_id":"pd","id":"default","count":3,"matched_peers":2}]}`)
+	c.Assert(op.String(), Not(Matches), `.*"rules".*`)
 }
 
 func (s *testRuleCheckerSuite) TestAddRulePeerWithIsolationLevel(c *C) {
@@ -142,10 +210,65 @@ func (s *testRuleCheckerSuite) TestFixOrphanPeers(c *C) {
 	s.cluster.AddLeaderStore(3, 1)
 	s.cluster.AddLeaderStore(4, 1)
 	s.cluster.AddLeaderRegionWithRange(1, "", "", 1, 2, 3, 4)
+	c.Assert(s.rc.GetOrphanPeerCount(s.cluster.GetRegion(1)), Equals, 1)
 	op := s.rc.Check(s.cluster.GetRegion(1))
 	c.Assert(op, NotNil)
 	c.Assert(op.Desc(), Equals, "remove-orphan-peer")
 	c.Assert(op.Step(0).(operator.RemovePeer).FromStore, Equals, uint64(4))
+
+	s.cluster.AddLeaderRegionWithRange(1, "", "", 1, 2, 3)
+	c.Assert(s.rc.GetOrphanPeerCount(s.cluster.GetRegion(1)), Equals, 0)
+}
+
+func (s *testRuleCheckerSuite) TestFixOrphanPeersLimit(c *C) {
+	s.cluster.SetMaxOrphanPeerFixesPerCycle(1)
+	s.cluster.AddLeaderStore(1, 1)
+	s.cluster.AddLeaderStore(2, 1)
+	s.cluster.AddLeaderStore(3, 1)
+	s.cluster.AddLeaderStore(4, 1)
+	s.cluster.AddLeaderRegionWithRange(1, "", "a", 1, 2, 3, 4)
+	s.cluster.AddLeaderRegionWithRange(2, "a", "", 1, 2, 3, 4)
+	op := s.rc.Check(s.cluster.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "remove-orphan-peer")
+	// the budget of this round is used up.
+	c.Assert(s.rc.Check(s.cluster.GetRegion(2)), IsNil)
+
+	s.rc.ResetOrphanPeerFixes()
+	op = s.rc.Check(s.cluster.GetRegion(2))
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "remove-orphan-peer")
+}
+
+func (s *testRuleCheckerSuite) TestSimulate(c *C) {
+	s.cluster.SetMaxOrphanPeerFixesPerCycle(1)
+	s.cluster.AddLeaderStore(1, 1)
+	s.cluster.AddLeaderStore(2, 1)
+	s.cluster.AddLeaderStore(3, 1)
+	s.cluster.AddLeaderStore(4, 1)
+	s.cluster.AddLeaderRegionWithRange(1, "", "", 1, 2, 3, 4)
+	// simulating does not use up the budget of orphan peer fixes.
+	for i := 0; i < 2; i++ {
+		ops, fit, err := s.rc.Simulate(s.cluster.GetRegion(1))
+		c.Assert(err, IsNil)
+		c.Assert(fit.OrphanPeers, HasLen, 1)
+		c.Assert(ops, HasLen, 1)
+		c.Assert(ops[0].Desc(), Equals, "remove-orphan-peer")
+	}
+	op := s.rc.Check(s.cluster.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "remove-orphan-peer")
+	// simulating is not limited by the budget used up by the checker, and
+	// leaves it untouched.
+	ops, _, err := s.rc.Simulate(s.cluster.GetRegion(1))
+	c.Assert(err, IsNil)
+	c.Assert(ops, HasLen, 1)
+	c.Assert(atomic.LoadUint64(&s.rc.orphanPeerFixes), Equals, uint64(1))
+	c.Assert(s.rc.Check(s.cluster.GetRegion(1)), IsNil)
+
+	s.cluster.SetEnablePlacementRules(false)
+	_, _, err = s.rc.Simulate(s.cluster.GetRegion(1))
+	c.Assert(err, NotNil)
 }
 
 func (s *testRuleCheckerSuite) TestFixOrphanPeers2(c *C) {
@@ -170,6 +293,42 @@ func (s *testRuleCheckerSuite) TestFixOrphanPeers2(c *C) {
 	c.Assert(op, IsNil)
 }
 
+func (s *testRuleCheckerSuite) TestFixPendingOrphanPeer(c *C) {
+	// an unhealthy orphan peer can be removed even if the rules are not satisfied.
+	s.cluster.AddLabelsStore(1, 1, map[string]string{"foo": "bar"})
+	s.cluster.AddLabelsStore(2, 1, map[string]string{"foo": "bar"})
+	s.cluster.AddLabelsStore(3, 1, map[string]string{"foo": "baz"})
+	s.cluster.AddLeaderRegionWithRange(1, "", "", 3, 1)
+	s.ruleManager.SetRule(&placement.Rule{
+		GroupID:  "pd",
+		ID:       "r1",
+		Index:    100,
+		Override: true,
+		Role:     placement.Leader,
+		Count:    2,
+		LabelConstraints: []placement.LabelConstraint{
+			{Key: "foo", Op: "in", Values: []string{"baz"}},
+		},
+	})
+	s.cluster.SetStoreDown(2)
+	region := s.cluster.GetRegion(1)
+	region = region.Clone(core.WithPendingPeers([]*metapb.Peer{region.GetStorePeer(1)}))
+
+	// the peer is still in the grace period.
+	s.cluster.SetPendingPeerGracePeriod(50 * time.Millisecond)
+	c.Assert(s.rc.Check(region), IsNil)
+	time.Sleep(100 * time.Millisecond)
+	op := s.rc.Check(region)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "remove-orphan-peer")
+	c.Assert(op.Step(0).(operator.RemovePeer).FromStore, Equals, uint64(1))
+
+	// the record is cleaned once the peer is not pending.
+	s.cluster.SetPendingPeerGracePeriod(time.Minute)
+	c.Assert(s.rc.Check(s.cluster.GetRegion(1)), IsNil)
+	c.Assert(s.rc.Check(region), IsNil)
+}
+
 func (s *testRuleCheckerSuite) TestFixRole(c *C) {
 	s.cluster.AddLeaderStore(1, 1)
 	s.cluster.AddLeaderStore(2, 1)
@@ -185,6 +344,32 @@ func (s *testRuleCheckerSuite) TestFixRole(c *C) {
 	c.Assert(op.Step(0).(operator.PromoteLearner).ToStore, Equals, uint64(1))
 }
 
+func (s *testRuleCheckerSuite) TestFixRoleCooldown(c *C) {
+	s.cluster.AddLeaderStore(1, 1)
+	s.cluster.AddLeaderStore(2, 1)
+	s.cluster.AddLeaderStore(3, 1)
+	s.cluster.AddLeaderRegionWithRange(1, "", "", 2, 1, 3)
+	r := s.cluster.GetRegion(1)
+	p := r.GetStorePeer(1)
+	p.Role = metapb.PeerRole_Learner
+	r = r.Clone(core.WithLearners([]*metapb.Peer{p}))
+	c.Assert(s.rc.Check(r), NotNil)
+	// The same peer is not converted again within the cooldown.
+	c.Assert(s.rc.Check(r), IsNil)
+
+	// No cooldown.
+	s.rc.roleConversions.Clear()
+	s.cluster.SetPeerRoleConversionCooldown(0)
+	c.Assert(s.rc.Check(r), NotNil)
+	c.Assert(s.rc.Check(r), NotNil)
+
+	s.cluster.SetPeerRoleConversionCooldown(10 * time.Millisecond)
+	c.Assert(s.rc.Check(r), NotNil)
+	c.Assert(s.rc.Check(r), IsNil)
+	time.Sleep(20 * time.Millisecond)
+	c.Assert(s.rc.Check(r), NotNil)
+}
+
 func (s *testRuleCheckerSuite) TestFixRoleLeader(c *C) {
 	s.cluster.AddLabelsStore(1, 1, map[string]string{"role": "follower"})
 	s.cluster.AddLabelsStore(2, 1, map[string]string{"role": "follower"})
@@ -249,6 +434,36 @@ func (s *testRuleCheckerSuite) TestFixRoleLeaderIssue3130(c *C) {
 	c.Assert(op.Step(0).(operator.RemovePeer).FromStore, Equals, uint64(1))
 }
 
+func (s *testRuleCheckerSuite) TestFixRoleLeaderPreferredLabels(c *C) {
+	s.cluster.AddLabelsStore(1, 1, map[string]string{"role": "follower", "zone": "us-east"})
+	s.cluster.AddLabelsStore(2, 1, map[string]string{"role": "leader", "zone": "us-west"})
+	s.cluster.AddLeaderRegion(1, 1, 2)
+	s.ruleManager.SetRule(&placement.Rule{
+		GroupID:  "pd",
+		ID:       "r1",
+		Index:    100,
+		Override: true,
+		Role:     placement.Leader,
+		Count:    1,
+		LabelConstraints: []placement.LabelConstraint{
+			{Key: "role", Op: "in", Values: []string{"leader"}},
+		},
+	})
+	s.cluster.SetPreferredLeaderLabels(map[string]string{"zone": "us-east"})
+	op := s.rc.Check(s.cluster.GetRegion(1))
+	c.Assert(op, IsNil)
+	c.Assert(operator.TransferLeader{FromStore: 1, ToStore: 2}.CheckLabelPreference(s.cluster), IsFalse)
+
+	s.cluster.AddLabelsStore(2, 1, map[string]string{"role": "leader", "zone": "us-east"})
+	op = s.rc.Check(s.cluster.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "fix-leader-role")
+	c.Assert(op.Step(0).(operator.TransferLeader).ToStore, Equals, uint64(2))
+
+	s.cluster.SetPreferredLeaderLabels(nil)
+	c.Assert(operator.TransferLeader{FromStore: 1, ToStore: 3}.CheckLabelPreference(s.cluster), IsTrue)
+}
+
 func (s *testRuleCheckerSuite) TestBetterReplacement(c *C) {
 	s.cluster.AddLabelsStore(1, 1, map[string]string{"host": "host1"})
 	s.cluster.AddLabelsStore(2, 1, map[string]string{"host": "host1"})
@@ -498,3 +713,21 @@ func (s *testRuleCheckerSuite) TestIssue3299(c *C) {
 		}
 	}
 }
+
+func (s *testRuleCheckerSuite) TestRetryableFailure(c *C) {
+	s.cluster.AddLeaderStore(1, 1)
+	s.cluster.AddLeaderStore(2, 1)
+	s.cluster.AddLeaderRegionWithRange(1, "", "", 1, 2)
+
+	// no store to add the third peer, which may be fixed later.
+	fit := s.cluster.FitRegion(s.cluster.GetRegion(1))
+	_, err := s.rc.fixRulePeer(s.cluster.GetRegion(1), fit, fit.RuleFits[0])
+	c.Assert(err, NotNil)
+	retryAfter, ok := errs.IsRetryable(err)
+	c.Assert(ok, IsTrue)
+	c.Assert(retryAfter, Equals, s.cluster.GetOpts().GetPatrolRegionInterval())
+
+	c.Assert(s.rc.Check(s.cluster.GetRegion(1)), IsNil)
+	_, ok = s.rc.regionWaitingList.Get(1)
+	c.Assert(ok, IsTrue)
+}
@@ -15,8 +15,12 @@ package schedule
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/tikv/pd/pkg/cache"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/schedule/checker"
@@ -25,8 +29,13 @@ import (
 	"github.com/tikv/pd/server/schedule/placement"
 )
 
-// DefaultCacheSize is the default length of waiting list.
-const DefaultCacheSize = 1000
+const (
+	// DefaultCacheSize is the default length of waiting list.
+	DefaultCacheSize = 1000
+	// checkerHitRateWindow is the number of the latest checks used to calculate
+	// the hit rate of a checker.
+	checkerHitRateWindow = 1000
+)
 
 // CheckerController is used to manage all checkers.
 type CheckerController struct {
@@ -39,23 +48,40 @@ type CheckerController struct {
 	mergeChecker      *checker.MergeChecker
 	jointStateChecker *checker.JointStateChecker
 	regionWaitingList cache.Cache
+	hitStats          *checkerHitStats
+	// pauseUntil records the unix time until which each checker is paused.
+	// The map is never modified after creation, so only the values need to
+	// be accessed atomically.
+	pauseUntil map[string]*int64
 }
 
 // NewCheckerController create a new CheckerController.
 // TODO: isSupportMerge should be removed.
 func NewCheckerController(ctx context.Context, cluster opt.Cluster, ruleManager *placement.RuleManager, opController *OperatorController) *CheckerController {
 	regionWaitingList := cache.NewDefaultCache(DefaultCacheSize)
-	return &CheckerController{
+	c := &CheckerController{
 		cluster:           cluster,
 		opts:              cluster.GetOpts(),
 		opController:      opController,
 		learnerChecker:    checker.NewLearnerChecker(cluster),
 		replicaChecker:    checker.NewReplicaChecker(cluster, regionWaitingList),
-		ruleChecker:       checker.NewRuleChecker(cluster, ruleManager, regionWaitingList),
+		ruleChecker:       checker.NewRuleChecker(ctx, cluster, ruleManager, regionWaitingList),
 		mergeChecker:      checker.NewMergeChecker(ctx, cluster),
 		jointStateChecker: checker.NewJointStateChecker(cluster),
 		regionWaitingList: regionWaitingList,
+		hitStats:          newCheckerHitStats(),
+		pauseUntil:        make(map[string]*int64),
+	}
+	for _, name := range []string{
+		c.learnerChecker.GetType(),
+		c.replicaChecker.GetType(),
+		c.ruleChecker.GetType(),
+		c.mergeChecker.GetType(),
+		c.jointStateChecker.GetType(),
+	} {
+		c.pauseUntil[name] = new(int64)
 	}
+	return c
 }
 
 // CheckRegion will check the region and add a new operator if needed.
@@ -64,12 +90,12 @@ func (c *CheckerController) CheckRegion(region *core.RegionInfo) []*operator.Ope
 	// Don't check isRaftLearnerEnabled cause it maybe disable learner feature but there are still some learners to promote.
 	opController := c.opController
 
-	if op := c.jointStateChecker.Check(region); op != nil {
+	if op := c.check(c.jointStateChecker, region); op != nil {
 		return []*operator.Operator{op}
 	}
 
 	if c.opts.IsPlacementRulesEnabled() {
-		if op := c.ruleChecker.Check(region); op != nil {
+		if op := c.check(c.ruleChecker, region); op != nil {
 			if opController.OperatorCount(operator.OpReplica) < c.opts.GetReplicaScheduleLimit() {
 				return []*operator.Operator{op}
 			}
@@ -77,10 +103,10 @@ func (c *CheckerController) CheckRegion(region *core.RegionInfo) []*operator.Ope
 			c.regionWaitingList.Put(region.GetID(), nil)
 		}
 	} else {
-		if op := c.learnerChecker.Check(region); op != nil {
+		if op := c.check(c.learnerChecker, region); op != nil {
 			return []*operator.Operator{op}
 		}
-		if op := c.replicaChecker.Check(region); op != nil {
+		if op := c.check(c.replicaChecker, region); op != nil {
 			if opController.OperatorCount(operator.OpReplica) < c.opts.GetReplicaScheduleLimit() {
 				return []*operator.Operator{op}
 			}
@@ -89,12 +115,12 @@ func (c *CheckerController) CheckRegion(region *core.RegionInfo) []*operator.Ope
 		}
 	}
 
-	if c.mergeChecker != nil {
+	if c.mergeChecker != nil && !c.isPaused(c.mergeChecker.GetType()) {
 		allowed := opController.OperatorCount(operator.OpMerge) < c.opts.GetMergeScheduleLimit()
 		if !allowed {
 			operator.OperatorLimitCounter.WithLabelValues(c.mergeChecker.GetType(), operator.OpMerge.String()).Inc()
 		} else {
-			if ops := c.mergeChecker.Check(region); ops != nil {
+			if ops := c.mergeChecker.Check(region); c.record(c.mergeChecker.GetType(), ops != nil) {
 				// It makes sure that two operators can be added successfully altogether.
 				return ops
 			}
@@ -103,6 +129,95 @@ func (c *CheckerController) CheckRegion(region *core.RegionInfo) []*operator.Ope
 	return nil
 }
 
+// regionChecker is a checker which creates at most one operator for a region.
+type regionChecker interface {
+	GetType() string
+	Check(region *core.RegionInfo) *operator.Operator
+}
+
+// check checks the region with the checker if it is not paused, and records
+// whether the check results in an operator.
+func (c *CheckerController) check(checker regionChecker, region *core.RegionInfo) *operator.Operator {
+	if c.isPaused(checker.GetType()) {
+		return nil
+	}
+	op := checker.Check(region)
+	c.record(checker.GetType(), op != nil)
+	return op
+}
+
+// record records whether the check of the checker results in an operator, and returns it.
+func (c *CheckerController) record(checker string, hit bool) bool {
+	c.hitStats.record(checker, hit)
+	return hit
+}
+
+// PauseChecker pauses the checker for t seconds. It resumes the checker if t
+// is not positive.
+func (c *CheckerController) PauseChecker(name string, t int64) error {
+	var pauseUntil int64
+	if t > 0 {
+		pauseUntil = time.Now().Unix() + t
+	}
+	return c.PauseCheckerUntil(name, pauseUntil)
+}
+
+// ResumeChecker resumes the checker.
+func (c *CheckerController) ResumeChecker(name string) error {
+	return c.PauseChecker(name, 0)
+}
+
+// PauseCheckerUntil pauses the checker until the given unix time. It is used
+// to restore the persisted pause of the checker.
+func (c *CheckerController) PauseCheckerUntil(name string, pauseUntil int64) error {
+	p, ok := c.pauseUntil[name]
+	if !ok {
+		return errs.ErrCheckerNotFound.FastGenByArgs()
+	}
+	atomic.StoreInt64(p, pauseUntil)
+	return nil
+}
+
+// GetCheckerPauseUntil returns the unix time until which the checker is paused.
+func (c *CheckerController) GetCheckerPauseUntil(name string) (int64, error) {
+	p, ok := c.pauseUntil[name]
+	if !ok {
+		return 0, errs.ErrCheckerNotFound.FastGenByArgs()
+	}
+	return atomic.LoadInt64(p), nil
+}
+
+func (c *CheckerController) isPaused(name string) bool {
+	p, ok := c.pauseUntil[name]
+	return ok && time.Now().Unix() < atomic.LoadInt64(p)
+}
+
+// CheckerHitRate returns the fraction of the latest checks which result in an
+// operator of each checker.
+func (c *CheckerController) CheckerHitRate() map[string]float64 {
+	return c.hitStats.hitRates()
+}
+
+// ResetOrphanPeerFixes resets the orphan peer removal budget of the rule checker.
+// It should be called once per patrol round.
+func (c *CheckerController) ResetOrphanPeerFixes() {
+	c.ruleChecker.ResetOrphanPeerFixes()
+}
+
+// GetOrphanPeerCount returns the number of orphan peers of the region.
+// It returns 0 if placement rules are disabled.
+func (c *CheckerController) GetOrphanPeerCount(region *core.RegionInfo) int {
+	if !c.opts.IsPlacementRulesEnabled() {
+		return 0
+	}
+	return c.ruleChecker.GetOrphanPeerCount(region)
+}
+
+// GetRuleChecker returns the rule checker.
+func (c *CheckerController) GetRuleChecker() *checker.RuleChecker {
+	return c.ruleChecker
+}
+
 // GetMergeChecker returns the merge checker.
 func (c *CheckerController) GetMergeChecker() *checker.MergeChecker {
 	return c.mergeChecker
@@ -113,6 +228,11 @@ func (c *CheckerController) GetWaitingRegions() []*cache.Item {
 	return c.regionWaitingList.Elems()
 }
 
+// GetRegionWaitingListSize returns the number of the regions in the waiting list.
+func (c *CheckerController) GetRegionWaitingListSize() int {
+	return c.regionWaitingList.Len()
+}
+
 // AddWaitingRegion returns the regions in the waiting list.
 func (c *CheckerController) AddWaitingRegion(region *core.RegionInfo) {
 	c.regionWaitingList.Put(region.GetID(), nil)
@@ -122,3 +242,53 @@ func (c *CheckerController) AddWaitingRegion(region *core.RegionInfo) {
 func (c *CheckerController) RemoveWaitingRegion(id uint64) {
 	c.regionWaitingList.Remove(id)
 }
+
+// checkerHitStats records the results of the latest checks of each checker.
+type checkerHitStats struct {
+	sync.Mutex
+	windows map[string]*hitWindow
+}
+
+// hitWindow is a ring of the results of the latest checks.
+type hitWindow struct {
+	results [checkerHitRateWindow]bool
+	next    int
+	count   int
+	hits    int
+}
+
+func newCheckerHitStats() *checkerHitStats {
+	return &checkerHitStats{windows: make(map[string]*hitWindow)}
+}
+
+func (s *checkerHitStats) record(checker string, hit bool) {
+	s.Lock()
+	defer s.Unlock()
+	w, ok := s.windows[checker]
+	if !ok {
+		w = &hitWindow{}
+		s.windows[checker] = w
+	}
+	if w.count == checkerHitRateWindow {
+		if w.results[w.next] {
+			w.hits--
+		}
+	} else {
+		w.count++
+	}
+	w.results[w.next] = hit
+	if hit {
+		w.hits++
+	}
+	w.next = (w.next + 1) % checkerHitRateWindow
+}
+
+func (s *checkerHitStats) hitRates() map[string]float64 {
+	s.Lock()
+	defer s.Unlock()
+	rates := make(map[string]float64, len(s.windows))
+	for checker, w := range s.windows {
+		rates[checker] = float64(w.hits) / float64(w.count)
+	}
+	return rates
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testCheckerHitStatsSuite{})
+
+type testCheckerHitStatsSuite struct{}
+
+func (s *testCheckerHitStatsSuite) TestHitRate(c *C) {
+	stats := newCheckerHitStats()
+	c.Assert(stats.hitRates(), HasLen, 0)
+
+	for i := 0; i < 4; i++ {
+		stats.record("replica-checker", i == 0)
+	}
+	stats.record("merge-checker", false)
+	c.Assert(stats.hitRates(), DeepEquals, map[string]float64{"replica-checker": 0.25, "merge-checker": 0})
+
+	// only the latest checks are counted.
+	for i := 0; i < checkerHitRateWindow; i++ {
+		stats.record("replica-checker", i%2 == 0)
+	}
+	c.Assert(stats.hitRates()["replica-checker"], Equals, 0.5)
+	for i := 0; i < checkerHitRateWindow; i++ {
+		stats.record("replica-checker", true)
+	}
+	c.Assert(stats.hitRates()["replica-checker"], Equals, 1.0)
+}
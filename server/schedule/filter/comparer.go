@@ -39,6 +39,29 @@ func RegionScoreComparer(opt *config.PersistOptions) StoreComparer {
 	}
 }
 
+// WriteBandwidthComparer creates a StoreComparer to sort store by the effective
+// write bandwidth, which excludes the estimated compaction writes.
+func WriteBandwidthComparer(opt *config.PersistOptions) StoreComparer {
+	bandwidth := func(s *core.StoreInfo) float64 {
+		engine := s.GetLabelValue(EngineKey)
+		if engine == "" {
+			engine = EngineTiKV
+		}
+		return s.EffectiveWriteBandwidth(opt.GetCompactionOverheadRatio(engine))
+	}
+	return func(a, b *core.StoreInfo) int {
+		ba, bb := bandwidth(a), bandwidth(b)
+		switch {
+		case ba > bb:
+			return 1
+		case ba < bb:
+			return -1
+		default:
+			return 0
+		}
+	}
+}
+
 // IsolationComparer creates a StoreComparer to sort store by isolation score.
 func IsolationComparer(locationLabels []string, regionStores []*core.StoreInfo) StoreComparer {
 	return func(a, b *core.StoreInfo) int {
@@ -187,6 +187,31 @@ func (f *storageThresholdFilter) Target(opt *config.PersistOptions, store *core.
 	return !store.IsLowSpace(opt.GetLowSpaceRatio())
 }
 
+type regionCountFilter struct{ scope string }
+
+// NewRegionCountFilter creates a Filter that filters all stores that have reached
+// the max number of regions.
+func NewRegionCountFilter(scope string) Filter {
+	return &regionCountFilter{scope: scope}
+}
+
+func (f *regionCountFilter) Scope() string {
+	return f.scope
+}
+
+func (f *regionCountFilter) Type() string {
+	return "region-count-filter"
+}
+
+func (f *regionCountFilter) Source(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return true
+}
+
+func (f *regionCountFilter) Target(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	maxRegions := opt.GetMaxRegionsPerStore()
+	return maxRegions == 0 || uint64(store.GetRegionCount()) < maxRegions
+}
+
 // distinctScoreFilter ensures that distinct score will not decrease.
 type distinctScoreFilter struct {
 	scope     string
@@ -349,6 +374,12 @@ func (f *StoreStateFilter) tooManyPendingPeers(opt *config.PersistOptions, store
 		store.GetPendingPeerCount() > int(opt.GetMaxPendingPeerCount())
 }
 
+func (f *StoreStateFilter) isBelowStorageFloor(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	f.Reason = "below-storage-floor"
+	// the store which has not reported its capacity yet is not judged.
+	return store.GetCapacity() > 0 && store.GetAvailable() < opt.GetMinAvailableStorageBytes()
+}
+
 func (f *StoreStateFilter) hasRejectLeaderProperty(opts *config.PersistOptions, store *core.StoreInfo) bool {
 	f.Reason = "reject-leader"
 	return opts.CheckLabelProperty(opt.RejectLeader, store.GetLabels())
@@ -359,13 +390,13 @@ func (f *StoreStateFilter) hasRejectLeaderProperty(opts *config.PersistOptions,
 // N: the condition is expected to be true for a long time.
 // X means when the condition is true, the store CANNOT be selected.
 //
-// Condition    Down Offline Tomb Pause Disconn Busy RmLimit AddLimit Snap Pending Reject
-// IsTemporary  N    N       N    N     Y       Y    Y       Y        Y    Y       N
+// Condition    Down Offline Tomb Pause Disconn Busy RmLimit AddLimit Snap Pending Reject Floor
+// IsTemporary  N    N       N    N     Y       Y    Y       Y        Y    Y       N      N
 //
 // LeaderSource X            X    X     X
 // RegionSource                                 X    X                X
 // LeaderTarget X    X       X    X     X       X                                  X
-// RegionTarget X    X       X          X       X            X        X    X
+// RegionTarget X    X       X          X       X            X        X    X              X
 
 const (
 	leaderSource = iota
@@ -387,9 +418,9 @@ func (f *StoreStateFilter) anyConditionMatch(typ int, opt *config.PersistOptions
 			f.isDisconnected, f.isBusy, f.hasRejectLeaderProperty}
 	case regionTarget:
 		funcs = []conditionFunc{f.isTombstone, f.isOffline, f.isDown, f.isDisconnected, f.isBusy,
-			f.exceedAddLimit, f.tooManySnapshots, f.tooManyPendingPeers}
+			f.exceedAddLimit, f.tooManySnapshots, f.tooManyPendingPeers, f.isBelowStorageFloor}
 	case scatterRegionTarget:
-		funcs = []conditionFunc{f.isTombstone, f.isOffline, f.isDown, f.isDisconnected, f.isBusy}
+		funcs = []conditionFunc{f.isTombstone, f.isOffline, f.isDown, f.isDisconnected, f.isBusy, f.isBelowStorageFloor}
 	}
 	for _, cf := range funcs {
 		if cf(opt, store) {
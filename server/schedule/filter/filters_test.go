@@ -182,6 +182,61 @@ func (s *testFiltersSuite) TestStoreStateFilter(c *C) {
 	check(store, testCases)
 }
 
+func (s *testFiltersSuite) TestStoreStateFilterStorageFloor(c *C) {
+	opt := config.NewTestOptions()
+	testCluster := mockcluster.NewCluster(opt)
+	testCluster.AddRegionStore(1, 1)
+	f := &StoreStateFilter{MoveRegion: true}
+	c.Assert(f.Target(testCluster.GetOpts(), testCluster.GetStore(1)), IsTrue)
+
+	// 1% of 100GiB is below the default floor.
+	testCluster.UpdateStorageRatio(1, 0.5, 0.01)
+	c.Assert(f.Target(testCluster.GetOpts(), testCluster.GetStore(1)), IsFalse)
+	c.Assert(f.Source(testCluster.GetOpts(), testCluster.GetStore(1)), IsTrue)
+	scatter := &StoreStateFilter{MoveRegion: true, ScatterRegion: true}
+	c.Assert(scatter.Target(testCluster.GetOpts(), testCluster.GetStore(1)), IsFalse)
+
+	testCluster.SetMinAvailableStorageBytes(0)
+	c.Assert(f.Target(testCluster.GetOpts(), testCluster.GetStore(1)), IsTrue)
+}
+
+func (s *testFiltersSuite) TestRegionCountFilter(c *C) {
+	opt := config.NewTestOptions()
+	testCluster := mockcluster.NewCluster(opt)
+	testCluster.AddRegionStore(1, 10)
+	f := NewRegionCountFilter("")
+	c.Assert(f.Target(testCluster.GetOpts(), testCluster.GetStore(1)), IsTrue)
+
+	testCluster.SetMaxRegionsPerStore(10)
+	c.Assert(f.Target(testCluster.GetOpts(), testCluster.GetStore(1)), IsFalse)
+	c.Assert(f.Source(testCluster.GetOpts(), testCluster.GetStore(1)), IsTrue)
+	testCluster.SetMaxRegionsPerStore(11)
+	c.Assert(f.Target(testCluster.GetOpts(), testCluster.GetStore(1)), IsTrue)
+}
+
+func (s *testFiltersSuite) TestWriteBandwidthComparer(c *C) {
+	opt := config.NewTestOptions()
+	testCluster := mockcluster.NewCluster(opt)
+	newStore := func(id uint64, engine string, mbps uint64) *core.StoreInfo {
+		meta := &metapb.Store{Id: id}
+		if engine != "" {
+			meta.Labels = []*metapb.StoreLabel{{Key: EngineKey, Value: engine}}
+		}
+		stats := &pdpb.StoreStats{
+			BytesWritten: mbps * (1 << 20) * 10,
+			Interval:     &pdpb.TimeInterval{StartTimestamp: 100, EndTimestamp: 110},
+		}
+		return core.NewStoreInfo(meta, core.SetStoreStats(stats))
+	}
+	tikv, tiflash := newStore(1, "", 100), newStore(2, EngineTiFlash, 150)
+
+	c.Assert(WriteBandwidthComparer(testCluster.GetOpts())(tikv, tiflash), Equals, -1)
+	testCluster.SetCompactionOverheadRatio(EngineTiFlash, 0.6)
+	c.Assert(WriteBandwidthComparer(testCluster.GetOpts())(tikv, tiflash), Equals, 1)
+	testCluster.SetCompactionOverheadRatio(EngineTiKV, 0.4)
+	c.Assert(WriteBandwidthComparer(testCluster.GetOpts())(tikv, tiflash), Equals, 0)
+}
+
 func (s *testFiltersSuite) TestIsolationFilter(c *C) {
 	opt := config.NewTestOptions()
 	testCluster := mockcluster.NewCluster(opt)
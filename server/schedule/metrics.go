@@ -73,6 +73,13 @@ var (
 			Name:      "scatter_distribution",
 			Help:      "Counter of the distribution in scatter.",
 		}, []string{"store", "is_leader", "engine"})
+
+	operatorSurgeCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Name:      "operator_surge_total",
+			Help:      "Counter of the surges of the operator generation rate.",
+		})
 )
 
 func init() {
@@ -83,4 +90,5 @@ func init() {
 	prometheus.MustRegister(operatorWaitCounter)
 	prometheus.MustRegister(scatterCounter)
 	prometheus.MustRegister(scatterDistributionCounter)
+	prometheus.MustRegister(operatorSurgeCounter)
 }
@@ -47,6 +47,7 @@ type Builder struct {
 	// operation record
 	originPeers         peersMap
 	unhealthyPeers      peersMap
+	downPeers           peersMap
 	originLeaderStoreID uint64
 	targetPeers         peersMap
 	targetLeaderStoreID uint64
@@ -55,6 +56,10 @@ type Builder struct {
 	// skip origin check flags
 	skipOriginJointStateCheck bool
 
+	// batchRemovePeers lets the builder remove all the peers in one step after
+	// the voters are demoted.
+	batchRemovePeers bool
+
 	// build flags
 	allowDemote       bool
 	useJointConsensus bool
@@ -80,6 +85,13 @@ func SkipOriginJointStateCheck(b *Builder) {
 	b.skipOriginJointStateCheck = true
 }
 
+// BatchRemovePeers lets the builder remove the peers with a BatchRemovePeer step
+// when joint consensus is used. The voters to remove are demoted in the joint
+// conf change before the step, so the region enters the joint state twice.
+func BatchRemovePeers(b *Builder) {
+	b.batchRemovePeers = true
+}
+
 // NewBuilder creates a Builder.
 func NewBuilder(desc string, cluster opt.Cluster, region *core.RegionInfo, opts ...BuilderOption) *Builder {
 	b := &Builder{
@@ -98,6 +110,7 @@ func NewBuilder(desc string, cluster opt.Cluster, region *core.RegionInfo, opts
 	err := b.err
 	originPeers := newPeersMap()
 	unhealthyPeers := newPeersMap()
+	downPeers := newPeersMap()
 
 	for _, p := range region.GetPeers() {
 		if p == nil || p.GetStoreId() == 0 {
@@ -113,6 +126,7 @@ func NewBuilder(desc string, cluster opt.Cluster, region *core.RegionInfo, opts
 
 	for _, p := range region.GetDownPeers() {
 		unhealthyPeers.Set(p.Peer)
+		downPeers.Set(p.Peer)
 	}
 
 	// origin leader
@@ -144,6 +158,7 @@ func NewBuilder(desc string, cluster opt.Cluster, region *core.RegionInfo, opts
 	b.rules = rules
 	b.originPeers = originPeers
 	b.unhealthyPeers = unhealthyPeers
+	b.downPeers = downPeers
 	b.originLeaderStoreID = originLeaderStoreID
 	b.targetPeers = originPeers.Copy()
 	b.allowDemote = supportJointConsensus
@@ -514,6 +529,10 @@ func (b *Builder) buildStepsWithJointConsensus(kind OpKind) (OpKind, error) {
 	}
 
 	// Finally, remove all the peers as Learner
+	if b.batchRemovePeers && len(b.toRemove) > 1 {
+		b.execBatchRemovePeer()
+		kind |= OpRegion
+	}
 	for _, remove := range b.toRemove.IDs() {
 		b.execRemovePeer(b.toRemove[remove])
 		kind |= OpRegion
@@ -668,6 +687,19 @@ func (b *Builder) execRemovePeer(peer *metapb.Peer) {
 	delete(b.toRemove, peer.GetStoreId())
 }
 
+func (b *Builder) execBatchRemovePeer() {
+	step := BatchRemovePeer{IsDownStore: true}
+	for _, storeID := range b.toRemove.IDs() {
+		step.FromStores = append(step.FromStores, storeID)
+		if _, ok := b.downPeers[storeID]; !ok {
+			step.IsDownStore = false
+		}
+		delete(b.currentPeers, storeID)
+	}
+	b.toRemove = newPeersMap()
+	b.steps = append(b.steps, step)
+}
+
 func (b *Builder) execChangePeerV2(needEnter bool, needTransferLeader bool) {
 	// Enter
 	step := ChangePeerV2Enter{
@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/slice"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/schedule/opt"
 	"github.com/tikv/pd/server/schedule/placement"
@@ -43,11 +44,21 @@ func CreatePromoteLearnerOperator(desc string, cluster opt.Cluster, region *core
 		Build(0)
 }
 
-// CreateRemovePeerOperator creates an operator that removes a peer from region.
-func CreateRemovePeerOperator(desc string, cluster opt.Cluster, kind OpKind, region *core.RegionInfo, storeID uint64) (*Operator, error) {
-	return NewBuilder(desc, cluster, region).
-		RemovePeer(storeID).
-		Build(kind)
+// CreateRemovePeerOperator creates an operator that removes peers from region.
+// If batch is set and joint consensus is available, the voters are demoted to
+// learners in a joint conf change first, then all the peers are removed by a
+// single BatchRemovePeer step, which enters and leaves the joint state again.
+// Otherwise, the peers are removed one by one.
+func CreateRemovePeerOperator(desc string, cluster opt.Cluster, kind OpKind, region *core.RegionInfo, batch bool, storeIDs ...uint64) (*Operator, error) {
+	var opts []BuilderOption
+	if batch {
+		opts = append(opts, BatchRemovePeers)
+	}
+	b := NewBuilder(desc, cluster, region, opts...)
+	for _, storeID := range storeIDs {
+		b.RemovePeer(storeID)
+	}
+	return b.Build(kind)
 }
 
 // CreateTransferLeaderOperator creates an operator that transfers the leader from a source store to a target store.
@@ -87,6 +98,25 @@ func CreateMovePeerOperator(desc string, cluster opt.Cluster, region *core.Regio
 		Build(kind)
 }
 
+// DryRunMovePeerOperator creates an operator like CreateMovePeerOperator, and
+// checks the safety of all its steps by simulating them on the current region
+// in the cluster. The operator is only returned if all the steps are safe, and
+// it is never added to the operator controller.
+func DryRunMovePeerOperator(desc string, cluster opt.Cluster, region *core.RegionInfo, kind OpKind, oldStore uint64, peer *metapb.Peer) (*Operator, error) {
+	op, err := CreateMovePeerOperator(desc, cluster, region, kind, oldStore, peer)
+	if err != nil {
+		return nil, err
+	}
+	current := cluster.GetRegion(region.GetID())
+	if current == nil {
+		return nil, errors.Errorf("region %d not found", region.GetID())
+	}
+	if err := checkStepsSafety(current, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
 // CreateMoveLeaderOperator creates an operator that replaces an old leader with a new leader.
 func CreateMoveLeaderOperator(desc string, cluster opt.Cluster, region *core.RegionInfo, kind OpKind, oldStore uint64, peer *metapb.Peer) (*Operator, error) {
 	return NewBuilder(desc, cluster, region).
@@ -259,3 +289,76 @@ func CreateLeaveJointStateOperator(desc string, cluster opt.Cluster, origin *cor
 	b.execChangePeerV2(false, true)
 	return NewOperator(b.desc, brief, b.regionID, b.regionEpoch, kind, b.steps...), nil
 }
+
+// checkStepsSafety checks the safety of each step of the operator against the
+// region which the previous steps have been applied to.
+func checkStepsSafety(region *core.RegionInfo, op *Operator) error {
+	for i := 0; i < op.Len(); i++ {
+		step := op.Step(i)
+		if err := step.CheckSafety(region); err != nil {
+			return errors.Errorf("step %d (%s) is unsafe: %v", i, step, err)
+		}
+		region = SimulateStep(region, step)
+	}
+	return nil
+}
+
+// SimulateStep returns a copy of the region after the step is finished. The
+// down stats of the removed peers are dropped as well.
+func SimulateStep(region *core.RegionInfo, step OpStep) *core.RegionInfo {
+	withRole := func(storeID, peerID uint64, role metapb.PeerRole) []core.RegionCreateOption {
+		return []core.RegionCreateOption{
+			core.WithRemoveStorePeer(storeID),
+			core.WithAddPeer(&metapb.Peer{Id: peerID, StoreId: storeID, Role: role}),
+		}
+	}
+	var opts []core.RegionCreateOption
+	switch s := step.(type) {
+	case TransferLeader:
+		opts = append(opts, core.WithLeader(region.GetStorePeer(s.ToStore)))
+	case AddPeer:
+		opts = append(opts, core.WithAddPeer(&metapb.Peer{Id: s.PeerID, StoreId: s.ToStore}))
+	case AddLightPeer:
+		opts = append(opts, core.WithAddPeer(&metapb.Peer{Id: s.PeerID, StoreId: s.ToStore}))
+	case AddLearner:
+		opts = append(opts, core.WithAddPeer(&metapb.Peer{Id: s.PeerID, StoreId: s.ToStore, Role: metapb.PeerRole_Learner}))
+	case AddLightLearner:
+		opts = append(opts, core.WithAddPeer(&metapb.Peer{Id: s.PeerID, StoreId: s.ToStore, Role: metapb.PeerRole_Learner}))
+	case PromoteLearner:
+		opts = append(opts, withRole(s.ToStore, s.PeerID, metapb.PeerRole_Voter)...)
+	case DemoteFollower:
+		opts = append(opts, withRole(s.ToStore, s.PeerID, metapb.PeerRole_Learner)...)
+	case RemovePeer:
+		opts = append(opts, core.WithRemoveStorePeer(s.FromStore), withoutDownPeers(region, s.FromStore))
+	case BatchRemovePeer:
+		for _, storeID := range s.FromStores {
+			opts = append(opts, core.WithRemoveStorePeer(storeID))
+		}
+		opts = append(opts, withoutDownPeers(region, s.FromStores...))
+	case ChangePeerV2Enter:
+		for _, pl := range s.PromoteLearners {
+			opts = append(opts, withRole(pl.ToStore, pl.PeerID, metapb.PeerRole_IncomingVoter)...)
+		}
+		for _, dv := range s.DemoteVoters {
+			opts = append(opts, withRole(dv.ToStore, dv.PeerID, metapb.PeerRole_DemotingVoter)...)
+		}
+	case ChangePeerV2Leave:
+		for _, pl := range s.PromoteLearners {
+			opts = append(opts, withRole(pl.ToStore, pl.PeerID, metapb.PeerRole_Voter)...)
+		}
+		for _, dv := range s.DemoteVoters {
+			opts = append(opts, withRole(dv.ToStore, dv.PeerID, metapb.PeerRole_Learner)...)
+		}
+	}
+	return region.Clone(opts...)
+}
+
+func withoutDownPeers(region *core.RegionInfo, storeIDs ...uint64) core.RegionCreateOption {
+	var downPeers []*pdpb.PeerStats
+	for _, stats := range region.GetDownPeers() {
+		if slice.NoneOf(storeIDs, func(i int) bool { return storeIDs[i] == stats.GetPeer().GetStoreId() }) {
+			downPeers = append(downPeers, stats)
+		}
+	}
+	return core.WithDownPeers(downPeers)
+}
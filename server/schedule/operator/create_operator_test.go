@@ -1066,3 +1066,49 @@ func (s *testCreateOperatorSuite) TestMoveRegionWithoutJointConsensus(c *C) {
 		}
 	}
 }
+
+func (s *testCreateOperatorSuite) TestDryRunMovePeerOperator(c *C) {
+	for _, enableJointConsensus := range []bool{true, false} {
+		if !enableJointConsensus {
+			s.cluster.DisableFeature(versioninfo.JointConsensus)
+		}
+		s.cluster.AddLeaderRegion(1, 1, 2, 3)
+		region := s.cluster.GetRegion(1)
+		op, err := DryRunMovePeerOperator("test", s.cluster, region, OpRegion, 3, &metapb.Peer{StoreId: 4})
+		c.Assert(err, IsNil)
+		c.Assert(op, NotNil)
+
+		// The region in the cluster has changed, the steps are not safe any more.
+		s.cluster.PutRegion(region.Clone(core.WithAddPeer(&metapb.Peer{Id: 100, StoreId: 4, Role: metapb.PeerRole_Learner})))
+		op, err = DryRunMovePeerOperator("test", s.cluster, region, OpRegion, 3, &metapb.Peer{StoreId: 4})
+		c.Assert(err, NotNil)
+		c.Assert(op, IsNil)
+	}
+}
+
+func (s *testCreateOperatorSuite) TestCreateRemovePeerOperatorInBatch(c *C) {
+	s.cluster.AddLeaderRegion(1, 1, 2, 3, 4, 5)
+	region := s.cluster.GetRegion(1)
+	op, err := CreateRemovePeerOperator("test", s.cluster, 0, region, true, 4, 5)
+	c.Assert(err, IsNil)
+	c.Assert(op.Kind()&OpRegion, Equals, OpRegion)
+	c.Assert(op.Len(), Equals, 3)
+	c.Assert(op.Step(0), FitsTypeOf, ChangePeerV2Enter{})
+	c.Assert(op.Step(1), FitsTypeOf, ChangePeerV2Leave{})
+	c.Assert(op.Step(2), DeepEquals, BatchRemovePeer{FromStores: []uint64{4, 5}})
+
+	// The peers are removed one by one if batch is not set.
+	op, err = CreateRemovePeerOperator("test", s.cluster, 0, region, false, 4, 5)
+	c.Assert(err, IsNil)
+	c.Assert(op.Len(), Equals, 4)
+	c.Assert(op.Step(2), FitsTypeOf, RemovePeer{})
+	c.Assert(op.Step(3), FitsTypeOf, RemovePeer{})
+
+	// Fall back to removing the peers one by one without joint consensus.
+	s.cluster.DisableFeature(versioninfo.JointConsensus)
+	op, err = CreateRemovePeerOperator("test", s.cluster, 0, region, true, 4, 5)
+	c.Assert(err, IsNil)
+	for i := 0; i < op.Len(); i++ {
+		c.Assert(op.Step(i), FitsTypeOf, RemovePeer{})
+	}
+}
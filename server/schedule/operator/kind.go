@@ -17,6 +17,7 @@ import (
 	"strings"
 
 	"github.com/pingcap/errors"
+	"github.com/tikv/pd/server/config"
 )
 
 // OpKind is a bit field to identify operator types.
@@ -65,6 +66,13 @@ var nameToFlag = map[string]OpKind{
 	"range":      OpRange,
 }
 
+func init() {
+	// register the names for the config validation.
+	for name := range nameToFlag {
+		config.RegisterOperatorKind(name)
+	}
+}
+
 func (k OpKind) String() string {
 	var flagNames []string
 	for flag := OpKind(1); flag < opMax; flag <<= 1 {
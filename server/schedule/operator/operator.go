@@ -53,6 +53,14 @@ type Operator struct {
 	Counters         []prometheus.Counter
 	FinishedCounters []prometheus.Counter
 	AdditionalInfos  map[string]string
+	// FitSummary is the JSON-encoded summary of the placement rule fit which
+	// triggers the operator, it is empty if the operator is not created by
+	// the rule checker. It is not a part of String, the /operators API shows
+	// it in a separate field.
+	FitSummary string
+	// slowWaitTime is the timeout of the operator marked `OpRegion`,
+	// SlowOperatorWaitTime is used if it is 0.
+	slowWaitTime time.Duration
 }
 
 // NewOperator creates a new operator.
@@ -202,11 +210,20 @@ func (o *Operator) CheckTimeout() bool {
 		return false
 	}
 	if o.kind&OpRegion != 0 {
+		if o.slowWaitTime > 0 {
+			return o.status.CheckTimeout(o.slowWaitTime)
+		}
 		return o.status.CheckTimeout(SlowOperatorWaitTime)
 	}
 	return o.status.CheckTimeout(FastOperatorWaitTime)
 }
 
+// SetSlowWaitTime sets the timeout of the operator if it is marked `OpRegion`.
+// It should be called before the operator is started.
+func (o *Operator) SetSlowWaitTime(wait time.Duration) {
+	o.slowWaitTime = wait
+}
+
 // Len returns the operator's steps count.
 func (o *Operator) Len() int {
 	return len(o.steps)
@@ -232,14 +249,8 @@ func (o *Operator) Check(region *core.RegionInfo) OpStep {
 	for step := atomic.LoadInt32(&o.currentStep); int(step) < len(o.steps); step++ {
 		if o.steps[int(step)].IsFinish(region) {
 			if atomic.CompareAndSwapInt64(&(o.stepsTime[step]), 0, time.Now().UnixNano()) {
-				var startTime time.Time
-				if step == 0 {
-					startTime = o.GetStartTime()
-				} else {
-					startTime = time.Unix(0, atomic.LoadInt64(&(o.stepsTime[step-1])))
-				}
 				operatorStepDuration.WithLabelValues(reflect.TypeOf(o.steps[int(step)]).Name()).
-					Observe(time.Unix(0, o.stepsTime[step]).Sub(startTime).Seconds())
+					Observe(o.GetStepDuration(int(step)).Seconds())
 			}
 			atomic.StoreInt32(&o.currentStep, step+1)
 		} else {
@@ -249,6 +260,23 @@ func (o *Operator) Check(region *core.RegionInfo) OpStep {
 	return nil
 }
 
+// GetStepDuration returns the duration the i-th step takes to finish. It
+// returns 0 if the step is not finished yet.
+func (o *Operator) GetStepDuration(i int) time.Duration {
+	if i < 0 || i >= len(o.stepsTime) {
+		return 0
+	}
+	finishTime := atomic.LoadInt64(&(o.stepsTime[i]))
+	if finishTime == 0 {
+		return 0
+	}
+	startTime := o.GetStartTime()
+	if i > 0 {
+		startTime = time.Unix(0, atomic.LoadInt64(&(o.stepsTime[i-1])))
+	}
+	return time.Unix(0, finishTime).Sub(startTime)
+}
+
 // ConfVerChanged returns the number of confver has consumed by steps
 func (o *Operator) ConfVerChanged(region *core.RegionInfo) (total uint64) {
 	current := atomic.LoadInt32(&o.currentStep)
@@ -319,6 +347,8 @@ func (o *Operator) History() []OpHistory {
 			addPeerStores = append(addPeerStores, s.ToStore)
 		case RemovePeer:
 			removePeerStores = append(removePeerStores, s.FromStore)
+		case BatchRemovePeer:
+			removePeerStores = append(removePeerStores, s.FromStores...)
 		}
 	}
 	for i := range addPeerStores {
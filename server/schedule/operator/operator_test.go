@@ -134,6 +134,16 @@ func (s *testOperatorSuite) TestOperator(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(len(res), Equals, len(op.String())+2)
 
+	// check the configured timeout for the operators marked OpRegion.
+	op = s.newTestOperator(1, OpLeader|OpRegion, steps...)
+	op.SetSlowWaitTime(time.Minute)
+	op.Start()
+	SetOperatorStatusReachTime(op, STARTED, op.GetStartTime().Add(-FastOperatorWaitTime-time.Second))
+	c.Assert(op.CheckTimeout(), IsFalse)
+	SetOperatorStatusReachTime(op, STARTED, op.GetStartTime().Add(-time.Minute-time.Second))
+	c.Assert(op.CheckTimeout(), IsTrue)
+	c.Assert(op.Status(), Equals, TIMEOUT)
+
 	// check short timeout for transfer leader only operators.
 	steps = []OpStep{TransferLeader{FromStore: 2, ToStore: 1}}
 	op = s.newTestOperator(1, OpLeader, steps...)
@@ -26,6 +26,7 @@ import (
 	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/core/storelimit"
+	"github.com/tikv/pd/server/schedule/opt"
 	"go.uber.org/zap"
 )
 
@@ -69,6 +70,25 @@ func (tl TransferLeader) CheckSafety(region *core.RegionInfo) error {
 	return nil
 }
 
+// CheckLabelPreference checks if the target store matches all the preferred-leader-labels.
+// It always returns true if no preferred labels are configured.
+func (tl TransferLeader) CheckLabelPreference(cluster opt.Cluster) bool {
+	labels := cluster.GetOpts().GetPreferredLeaderLabels()
+	if len(labels) == 0 {
+		return true
+	}
+	store := cluster.GetStore(tl.ToStore)
+	if store == nil {
+		return false
+	}
+	for key, value := range labels {
+		if store.GetLabelValue(key) != value {
+			return false
+		}
+	}
+	return true
+}
+
 // Influence calculates the store difference that current step makes.
 func (tl TransferLeader) Influence(opInfluence OpInfluence, region *core.RegionInfo) {
 	from := opInfluence.GetStoreInfluence(tl.FromStore)
@@ -262,6 +282,93 @@ func (rp RemovePeer) Influence(opInfluence OpInfluence, region *core.RegionInfo)
 	from.AdjustStepCost(storelimit.RemovePeer, regionSize)
 }
 
+// BatchRemovePeer is an OpStep that removes several region peers in a single
+// conf change. It relies on joint consensus, so all the peers to remove should
+// be learners already.
+type BatchRemovePeer struct {
+	FromStores []uint64
+	// IsDownStore indicates the peers are removed because their stores are down.
+	IsDownStore bool
+}
+
+// ConfVerChanged returns the delta value for version increased by this step.
+func (brp BatchRemovePeer) ConfVerChanged(region *core.RegionInfo) uint64 {
+	var removed uint64
+	for _, storeID := range brp.FromStores {
+		if region.GetStorePeer(storeID) == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+func (brp BatchRemovePeer) String() string {
+	return fmt.Sprintf("remove peers on stores %v", brp.FromStores)
+}
+
+// IsFinish checks if current step is finished.
+func (brp BatchRemovePeer) IsFinish(region *core.RegionInfo) bool {
+	for _, storeID := range brp.FromStores {
+		if region.GetStorePeer(storeID) != nil {
+			return false
+		}
+	}
+	return !core.IsInJointState(region.GetPeers()...)
+}
+
+// CheckSafety checks if the step meets the safety properties.
+func (brp BatchRemovePeer) CheckSafety(region *core.RegionInfo) error {
+	for _, storeID := range brp.FromStores {
+		peer := region.GetStorePeer(storeID)
+		if peer == nil {
+			continue
+		}
+		if storeID == region.GetLeader().GetStoreId() {
+			return errors.New("cannot remove leader peer")
+		}
+		if !core.IsLearner(peer) {
+			return errors.Errorf("cannot remove voter peer on store %v in batch", storeID)
+		}
+	}
+	return nil
+}
+
+// Influence calculates the store difference that current step makes.
+func (brp BatchRemovePeer) Influence(opInfluence OpInfluence, region *core.RegionInfo) {
+	regionSize := region.GetApproximateSize()
+	stepCost := regionSize
+	// Removing a peer from a down store costs the store nothing, so count it as a small region.
+	if brp.IsDownStore && stepCost > storelimit.SmallRegionThreshold {
+		stepCost = storelimit.SmallRegionThreshold
+	}
+	for _, storeID := range brp.FromStores {
+		from := opInfluence.GetStoreInfluence(storeID)
+		from.RegionSize -= regionSize
+		from.RegionCount--
+		from.AdjustStepCost(storelimit.RemovePeer, stepCost)
+	}
+}
+
+// GetRequest gets the ChangePeerV2 request which removes the remaining peers.
+// If the region is still in the joint state, an empty request is returned to leave it.
+func (brp BatchRemovePeer) GetRequest(region *core.RegionInfo) *pdpb.ChangePeerV2 {
+	if core.IsInJointState(region.GetPeers()...) {
+		return &pdpb.ChangePeerV2{}
+	}
+	changes := make([]*pdpb.ChangePeer, 0, len(brp.FromStores))
+	for _, storeID := range brp.FromStores {
+		if peer := region.GetStorePeer(storeID); peer != nil {
+			changes = append(changes, &pdpb.ChangePeer{
+				ChangeType: eraftpb.ConfChangeType_RemoveNode,
+				Peer:       peer,
+			})
+		}
+	}
+	return &pdpb.ChangePeerV2{
+		Changes: changes,
+	}
+}
+
 // MergeRegion is an OpStep that merge two regions.
 type MergeRegion struct {
 	FromRegion *metapb.Region
@@ -344,6 +451,13 @@ func (sr SplitRegion) Influence(opInfluence OpInfluence, region *core.RegionInfo
 
 // CheckSafety checks if the step meets the safety properties.
 func (sr SplitRegion) CheckSafety(region *core.RegionInfo) error {
+	for _, key := range sr.SplitKeys {
+		if bytes.Compare(key, region.GetStartKey()) < 0 ||
+			(len(region.GetEndKey()) > 0 && bytes.Compare(key, region.GetEndKey()) >= 0) {
+			return errors.Errorf("split key %s is out of the region range [%s, %s)",
+				core.HexRegionKeyStr(key), core.HexRegionKeyStr(region.GetStartKey()), core.HexRegionKeyStr(region.GetEndKey()))
+		}
+	}
 	return nil
 }
 
@@ -17,6 +17,7 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/core/storelimit"
 )
 
 type testStepSuite struct{}
@@ -338,6 +339,112 @@ func (s *testStepSuite) TestChangePeerV2Leave(c *C) {
 	s.check(c, cpl, desc, cases)
 }
 
+func (s *testStepSuite) TestBatchRemovePeer(c *C) {
+	brp := BatchRemovePeer{FromStores: []uint64{2, 3}}
+	cases := []testCase{
+		{ // before step
+			[]*metapb.Peer{
+				{Id: 1, StoreId: 1, Role: metapb.PeerRole_Voter},
+				{Id: 2, StoreId: 2, Role: metapb.PeerRole_Learner},
+				{Id: 3, StoreId: 3, Role: metapb.PeerRole_Learner},
+			},
+			0,
+			false,
+			IsNil,
+		},
+		{ // one peer is removed
+			[]*metapb.Peer{
+				{Id: 1, StoreId: 1, Role: metapb.PeerRole_Voter},
+				{Id: 3, StoreId: 3, Role: metapb.PeerRole_Learner},
+			},
+			1,
+			false,
+			IsNil,
+		},
+		{ // after step
+			[]*metapb.Peer{
+				{Id: 1, StoreId: 1, Role: metapb.PeerRole_Voter},
+			},
+			2,
+			true,
+			IsNil,
+		},
+		{ // after step, but still in joint state
+			[]*metapb.Peer{
+				{Id: 1, StoreId: 1, Role: metapb.PeerRole_Voter},
+				{Id: 4, StoreId: 4, Role: metapb.PeerRole_DemotingVoter},
+				{Id: 5, StoreId: 5, Role: metapb.PeerRole_Voter},
+			},
+			2,
+			false,
+			IsNil,
+		},
+		{ // remove voter
+			[]*metapb.Peer{
+				{Id: 1, StoreId: 1, Role: metapb.PeerRole_Voter},
+				{Id: 2, StoreId: 2, Role: metapb.PeerRole_Voter},
+				{Id: 3, StoreId: 3, Role: metapb.PeerRole_Learner},
+			},
+			0,
+			false,
+			NotNil,
+		},
+		{ // remove leader
+			[]*metapb.Peer{
+				{Id: 2, StoreId: 2, Role: metapb.PeerRole_Voter},
+				{Id: 1, StoreId: 1, Role: metapb.PeerRole_Voter},
+				{Id: 3, StoreId: 3, Role: metapb.PeerRole_Learner},
+			},
+			0,
+			false,
+			NotNil,
+		},
+	}
+	s.check(c, brp, "remove peers on stores [2 3]", cases)
+
+	peers := []*metapb.Peer{
+		{Id: 1, StoreId: 1, Role: metapb.PeerRole_Voter},
+		{Id: 2, StoreId: 2, Role: metapb.PeerRole_Learner},
+		{Id: 3, StoreId: 3, Role: metapb.PeerRole_Learner},
+	}
+	region := core.NewRegionInfo(&metapb.Region{Id: 1, Peers: peers}, peers[0], core.SetApproximateSize(100))
+	c.Assert(brp.GetRequest(region).GetChanges(), HasLen, 2)
+
+	influence := OpInfluence{StoresInfluence: make(map[uint64]*StoreInfluence)}
+	brp.Influence(influence, region)
+	c.Assert(influence.GetStoreInfluence(2).RegionSize, Equals, int64(-100))
+	c.Assert(influence.GetStoreInfluence(3).RegionCount, Equals, int64(-1))
+	c.Assert(influence.GetStoreInfluence(2).GetStepCost(storelimit.RemovePeer), Equals, storelimit.RegionInfluence[storelimit.RemovePeer])
+
+	brp.IsDownStore = true
+	influence = OpInfluence{StoresInfluence: make(map[uint64]*StoreInfluence)}
+	brp.Influence(influence, region)
+	c.Assert(influence.GetStoreInfluence(2).GetStepCost(storelimit.RemovePeer), Equals, storelimit.SmallRegionInfluence[storelimit.RemovePeer])
+}
+
+func (s *testStepSuite) TestSplitRegionCheckSafety(c *C) {
+	peers := []*metapb.Peer{{Id: 1, StoreId: 1}}
+	region := core.NewRegionInfo(&metapb.Region{Id: 1, StartKey: []byte("b"), EndKey: []byte("d"), Peers: peers}, peers[0])
+	cases := []struct {
+		splitKeys   [][]byte
+		checkSafety Checker
+	}{
+		{[][]byte{[]byte("b"), []byte("c")}, IsNil},
+		{[][]byte{[]byte("a")}, NotNil},
+		{[][]byte{[]byte("c"), []byte("d")}, NotNil},
+		{[][]byte{[]byte("e")}, NotNil},
+	}
+	for _, tc := range cases {
+		sr := SplitRegion{StartKey: region.GetStartKey(), EndKey: region.GetEndKey(), SplitKeys: tc.splitKeys}
+		c.Assert(sr.CheckSafety(region), tc.checkSafety)
+	}
+
+	// the last region has no end key.
+	region = region.Clone(core.WithEndKey(nil))
+	sr := SplitRegion{SplitKeys: [][]byte{[]byte("e")}}
+	c.Assert(sr.CheckSafety(region), IsNil)
+}
+
 func (s *testStepSuite) check(c *C, step OpStep, desc string, cases []testCase) {
 	c.Assert(step.String(), Equals, desc)
 	for _, tc := range cases {
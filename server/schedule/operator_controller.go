@@ -18,6 +18,7 @@ import (
 	"container/list"
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -29,6 +30,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/cache"
 	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/movingaverage"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/core/storelimit"
 	"github.com/tikv/pd/server/schedule/hbstream"
@@ -68,6 +70,10 @@ type OperatorController struct {
 	wop             WaitingOperator
 	wopStatus       *WaitingOperatorStatus
 	opNotifierQueue operatorQueue
+	stepDurations   *stepDurationStats
+	// opsPool reuses the operator slices when promoting the waiting operators.
+	opsPool       sync.Pool
+	surgeDetector *OperatorSurgeDetector
 }
 
 // NewOperatorController creates a OperatorController.
@@ -84,6 +90,8 @@ func NewOperatorController(ctx context.Context, cluster opt.Cluster, hbStreams *
 		wop:             NewRandBuckets(),
 		wopStatus:       NewWaitingOperatorStatus(),
 		opNotifierQueue: make(operatorQueue, 0),
+		stepDurations:   newStepDurationStats(),
+		surgeDetector:   NewOperatorSurgeDetector(DefaultSurgeThresholdRatio, DefaultSurgeWindow),
 	}
 }
 
@@ -255,6 +263,13 @@ func (oc *OperatorController) PushOperators() {
 
 // AddWaitingOperator adds operators to waiting operators.
 func (oc *OperatorController) AddWaitingOperator(ops ...*operator.Operator) int {
+	added, _ := oc.TryAddWaitingOperator(ops...)
+	return added
+}
+
+// TryAddWaitingOperator adds operators to waiting operators like AddWaitingOperator,
+// but returns ErrRegionLocked if the region is locked by a running operator.
+func (oc *OperatorController) TryAddWaitingOperator(ops ...*operator.Operator) (int, error) {
 	oc.Lock()
 	added := 0
 
@@ -267,17 +282,25 @@ func (oc *OperatorController) AddWaitingOperator(ops ...*operator.Operator) int
 				// should not be here forever
 				log.Error("orphan merge operators found", zap.String("desc", desc), errs.ZapError(errs.ErrMergeOperator.FastGenByArgs("orphan operator found")))
 				oc.Unlock()
-				return added
+				return added, nil
 			}
 			if ops[i+1].Kind()&operator.OpMerge == 0 {
 				log.Error("merge operator should be paired", zap.String("desc",
 					ops[i+1].Desc()), errs.ZapError(errs.ErrMergeOperator.FastGenByArgs("operator should be paired")))
 				oc.Unlock()
-				return added
+				return added, nil
 			}
 			isMerge = true
 		}
-		if !oc.checkAddOperator(op) {
+		var lockErr error
+		if isMerge {
+			lockErr = oc.checkRegionLocks(op, ops[i+1])
+		} else {
+			lockErr = oc.checkRegionLocks(op)
+		}
+		// the locks are checked above to return the error, so they are not
+		// checked again.
+		if lockErr != nil || oc.exceedTotalOperatorLimitLocked(op) || oc.exceedWaitingKindLimitLocked(op) || !oc.checkAddOperatorWithoutLock(op) {
 			_ = op.Cancel()
 			oc.buryOperator(op)
 			if isMerge {
@@ -287,7 +310,7 @@ func (oc *OperatorController) AddWaitingOperator(ops ...*operator.Operator) int
 				oc.buryOperator(next)
 			}
 			oc.Unlock()
-			return added
+			return added, lockErr
 		}
 		oc.wop.PutOperator(op)
 		if isMerge {
@@ -305,7 +328,7 @@ func (oc *OperatorController) AddWaitingOperator(ops ...*operator.Operator) int
 	oc.Unlock()
 	operatorWaitCounter.WithLabelValues(ops[0].Desc(), "promote-add").Inc()
 	oc.PromoteWaitingOperator()
-	return added
+	return added, nil
 }
 
 // AddOperator adds operators to the running operators.
@@ -333,10 +356,17 @@ func (oc *OperatorController) PromoteWaitingOperator() {
 	oc.Lock()
 	defer oc.Unlock()
 	var ops []*operator.Operator
+	if buf := oc.getOperatorSlice(); buf != nil {
+		ops = *buf
+		defer func() {
+			*buf = ops
+			oc.putOperatorSlice(buf)
+		}()
+	}
 	for {
-		// GetOperator returns one operator or two merge operators
-		ops = oc.wop.GetOperator()
-		if ops == nil {
+		// AppendOperator returns one operator or two merge operators
+		ops = oc.wop.AppendOperator(ops[:0])
+		if len(ops) == 0 {
 			return
 		}
 		operatorWaitCounter.WithLabelValues(ops[0].Desc(), "get").Inc()
@@ -361,14 +391,134 @@ func (oc *OperatorController) PromoteWaitingOperator() {
 	}
 }
 
+// getOperatorSlice returns an empty operator slice from the pool, or nil if
+// the operator pool is disabled.
+func (oc *OperatorController) getOperatorSlice() *[]*operator.Operator {
+	size := oc.getOperatorPoolSize()
+	if size == 0 {
+		return nil
+	}
+	if buf, ok := oc.opsPool.Get().(*[]*operator.Operator); ok && cap(*buf) == size {
+		return buf
+	}
+	ops := make([]*operator.Operator, 0, size)
+	return &ops
+}
+
+// putOperatorSlice puts the operator slice back to the pool. The slice is
+// dropped if it has grown larger or the pool size has been changed.
+func (oc *OperatorController) putOperatorSlice(buf *[]*operator.Operator) {
+	if cap(*buf) != oc.getOperatorPoolSize() {
+		return
+	}
+	// do not keep the finished operators alive.
+	ops := (*buf)[:cap(*buf)]
+	for i := range ops {
+		ops[i] = nil
+	}
+	*buf = ops[:0]
+	oc.opsPool.Put(buf)
+}
+
+func (oc *OperatorController) getOperatorPoolSize() int {
+	if oc.cluster == nil {
+		return 0
+	}
+	return oc.cluster.GetOpts().GetOperatorPoolSize()
+}
+
+// totalOperatorLimitRatio is the ratio of MaxTotalOperators that each priority
+// level can use, so the operators with lower priority are rejected first.
+var totalOperatorLimitRatio = []float64{0.8, 0.9, 1.0}
+
+// exceedTotalOperatorLimitLocked checks if the number of the running and waiting
+// operators reaches the limit of the priority of the operator.
+func (oc *OperatorController) exceedTotalOperatorLimitLocked(op *operator.Operator) bool {
+	maxTotal := oc.cluster.GetOpts().GetMaxTotalOperators()
+	if maxTotal == 0 {
+		return false
+	}
+	total := uint64(len(oc.operators))
+	for _, n := range oc.wopStatus.ops {
+		total += n
+	}
+	limit := uint64(float64(maxTotal) * totalOperatorLimitRatio[op.GetPriorityLevel()])
+	if total >= limit {
+		log.Debug("exceed max total operators, cancel add operator",
+			zap.Uint64("region-id", op.RegionID()),
+			zap.Uint64("total", total),
+			zap.Uint64("limit", limit))
+		operatorWaitCounter.WithLabelValues(op.Desc(), "exceed-total-limit").Inc()
+		return true
+	}
+	return false
+}
+
+// exceedWaitingKindLimitLocked checks if the waiting operators of any kind of
+// the operator reach the limit of the kind.
+func (oc *OperatorController) exceedWaitingKindLimitLocked(op *operator.Operator) bool {
+	limits := oc.cluster.GetOpts().GetMaxWaitingOperatorsPerKind()
+	if len(limits) == 0 {
+		return false
+	}
+	waiting := oc.wop.ListOperator()
+	for name, limit := range limits {
+		kind, err := operator.ParseOperatorKind(name)
+		if err != nil || op.Kind()&kind == 0 {
+			continue
+		}
+		var count uint64
+		for i := 0; i < len(waiting); i++ {
+			if waiting[i].Kind()&kind != 0 {
+				count++
+			}
+			// the two operators of a merge are counted as one.
+			if waiting[i].Kind()&operator.OpMerge != 0 {
+				i++
+			}
+		}
+		if count >= limit {
+			log.Debug("exceed max waiting operators of the kind, cancel add operator",
+				zap.Uint64("region-id", op.RegionID()),
+				zap.String("kind", name),
+				zap.Uint64("limit", limit))
+			operatorWaitCounter.WithLabelValues(op.Desc(), "exceed-kind-limit").Inc()
+			return true
+		}
+	}
+	return false
+}
+
 // checkAddOperator checks if the operator can be added.
 // There are several situations that cannot be added:
 // - There is no such region in the cluster
 // - The epoch of the operator and the epoch of the corresponding region are no longer consistent.
 // - The region already has a higher priority or same priority operator.
 // - Exceed the max number of waiting operators
+// - The region is locked by a running operator.
 // - At least one operator is expired.
 func (oc *OperatorController) checkAddOperator(ops ...*operator.Operator) bool {
+	return oc.checkRegionLocks(ops...) == nil && oc.checkAddOperatorWithoutLock(ops...)
+}
+
+// checkRegionLocks returns the error of the first operator whose region is
+// locked, see checkRegionLockLocked.
+func (oc *OperatorController) checkRegionLocks(ops ...*operator.Operator) error {
+	for _, op := range ops {
+		if err := oc.checkRegionLockLocked(op); err != nil {
+			log.Debug("region is locked, cancel add operator",
+				zap.Uint64("region-id", op.RegionID()),
+				errs.ZapError(err))
+			operatorWaitCounter.WithLabelValues(op.Desc(), "region-locked").Inc()
+			return err
+		}
+	}
+	return nil
+}
+
+// checkAddOperatorWithoutLock is checkAddOperator without checking if the
+// regions are locked.
+func (oc *OperatorController) checkAddOperatorWithoutLock(ops ...*operator.Operator) bool {
 	for _, op := range ops {
 		region := oc.cluster.GetRegion(op.RegionID())
 		if region == nil {
@@ -420,6 +570,21 @@ func (oc *OperatorController) checkAddOperator(ops ...*operator.Operator) bool {
 	return !expired
 }
 
+// checkRegionLockLocked checks if the region of the operator is locked. A region
+// is locked when it has a running operator with multiple steps, replacing it
+// may leave the region in an intermediate state, e.g. with an extra learner.
+// Only the admin operator can break the lock.
+func (oc *OperatorController) checkRegionLockLocked(op *operator.Operator) error {
+	if op.Kind()&operator.OpAdmin != 0 {
+		return nil
+	}
+	old := oc.operators[op.RegionID()]
+	if old == nil || old.Len() <= 1 || !old.HasStarted() || old.IsEnd() {
+		return nil
+	}
+	return errs.ErrRegionLocked.FastGenByArgs(op.RegionID(), old.Desc())
+}
+
 func isHigherPriorityOperator(new, old *operator.Operator) bool {
 	return new.GetPriorityLevel() > old.GetPriorityLevel()
 }
@@ -440,6 +605,11 @@ func (oc *OperatorController) addOperatorLocked(op *operator.Operator) bool {
 		oc.buryOperator(old)
 	}
 
+	if oc.cluster != nil {
+		opts := oc.cluster.GetOpts()
+		op.SetSlowWaitTime(opts.GetMaxOperatorWaitTime())
+		oc.surgeDetector.SetThreshold(opts.GetOperatorSurgeThresholdRatio(), opts.GetOperatorSurgeWindow())
+	}
 	if !op.Start() {
 		log.Error("adding operator with unexpected status",
 			zap.Uint64("region-id", regionID),
@@ -453,6 +623,7 @@ func (oc *OperatorController) addOperatorLocked(op *operator.Operator) bool {
 	}
 	oc.operators[regionID] = op
 	operatorCounter.WithLabelValues(op.Desc(), "start").Inc()
+	oc.surgeDetector.Record(time.Now())
 	operatorWaitDuration.WithLabelValues(op.Desc()).Observe(op.ElapsedTime().Seconds())
 	opInfluence := NewTotalOpInfluence([]*operator.Operator{op}, oc.cluster)
 	for storeID := range opInfluence.StoresInfluence {
@@ -547,6 +718,7 @@ func (oc *OperatorController) buryOperator(op *operator.Operator, extraFields ..
 			zap.String("additional-info", op.GetAdditionalInfo()))
 		operatorCounter.WithLabelValues(op.Desc(), "finish").Inc()
 		operatorDuration.WithLabelValues(op.Desc()).Observe(op.RunningTime().Seconds())
+		oc.stepDurations.record(op)
 		for _, counter := range op.FinishedCounters {
 			counter.Inc()
 		}
@@ -594,6 +766,11 @@ func (oc *OperatorController) GetOperatorStatus(id uint64) *OperatorWithStatus {
 	return oc.opRecords.Get(id)
 }
 
+// GetRegionOperatorHistory gets the recently finished operators of the region.
+func (oc *OperatorController) GetRegionOperatorHistory(regionID uint64) []*OperatorRecord {
+	return oc.opRecords.GetHistory(regionID)
+}
+
 // GetOperator gets a operator from the given region.
 func (oc *OperatorController) GetOperator(regionID uint64) *operator.Operator {
 	oc.RLock()
@@ -601,6 +778,18 @@ func (oc *OperatorController) GetOperator(regionID uint64) *operator.Operator {
 	return oc.operators[regionID]
 }
 
+// GetRegionOperatorAge returns how long the running operator of the region has
+// been running. It returns false if there is no running operator.
+func (oc *OperatorController) GetRegionOperatorAge(regionID uint64) (time.Duration, bool) {
+	oc.RLock()
+	defer oc.RUnlock()
+	op, ok := oc.operators[regionID]
+	if !ok {
+		return 0, false
+	}
+	return op.RunningTime(), true
+}
+
 // GetOperators gets operators from the running operators.
 func (oc *OperatorController) GetOperators() []*operator.Operator {
 	oc.RLock()
@@ -671,6 +860,10 @@ func (oc *OperatorController) SendScheduleCommand(region *core.RegionInfo, step
 				Peer:       region.GetStorePeer(st.FromStore),
 			},
 		}
+	case operator.BatchRemovePeer:
+		cmd = &pdpb.RegionHeartbeatResponse{
+			ChangePeerV2: st.GetRequest(region),
+		}
 	case operator.MergeRegion:
 		if st.IsPassive {
 			return
@@ -850,14 +1043,24 @@ func (o *OperatorWithStatus) MarshalJSON() ([]byte, error) {
 // OperatorRecords remains the operator and its status for a while.
 type OperatorRecords struct {
 	ttl *cache.TTLUint64
+	// historyMu protects the finished operators of each region in histories.
+	historyMu sync.Mutex
+	histories *cache.TTLUint64
 }
 
-const operatorStatusRemainTime = 10 * time.Minute
+const (
+	operatorStatusRemainTime = 10 * time.Minute
+	// The finished operators of a region are kept for an hour since the
+	// last one finishes, and at most regionOperatorHistorySize are kept.
+	operatorHistoryRemainTime = time.Hour
+	regionOperatorHistorySize = 10
+)
 
 // NewOperatorRecords returns a OperatorRecords.
 func NewOperatorRecords(ctx context.Context) *OperatorRecords {
 	return &OperatorRecords{
-		ttl: cache.NewIDTTL(ctx, time.Minute, operatorStatusRemainTime),
+		ttl:       cache.NewIDTTL(ctx, time.Minute, operatorStatusRemainTime),
+		histories: cache.NewIDTTL(ctx, time.Minute, operatorHistoryRemainTime),
 	}
 }
 
@@ -875,6 +1078,73 @@ func (o *OperatorRecords) Put(op *operator.Operator) {
 	id := op.RegionID()
 	record := NewOperatorWithStatus(op)
 	o.ttl.Put(id, record)
+	o.putHistory(op)
+}
+
+// OperatorRecord is the summary of a finished operator.
+type OperatorRecord struct {
+	Kind         string    `json:"kind"`
+	CreatedAt    time.Time `json:"created_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+	FinishReason string    `json:"finish_reason"`
+	Steps        []string  `json:"steps"`
+}
+
+func newOperatorRecord(op *operator.Operator) *OperatorRecord {
+	steps := make([]string, 0, op.Len())
+	for i := 0; i < op.Len(); i++ {
+		steps = append(steps, op.Step(i).String())
+	}
+	return &OperatorRecord{
+		Kind:         op.Kind().String(),
+		CreatedAt:    op.GetCreateTime(),
+		FinishedAt:   op.GetReachTimeOf(op.Status()),
+		FinishReason: operator.OpStatusToString(op.Status()),
+		Steps:        steps,
+	}
+}
+
+// regionOperatorHistory is a ring buffer of the finished operators of a region.
+type regionOperatorHistory struct {
+	records [regionOperatorHistorySize]*OperatorRecord
+	next    int
+	count   int
+}
+
+func (o *OperatorRecords) putHistory(op *operator.Operator) {
+	o.historyMu.Lock()
+	defer o.historyMu.Unlock()
+	var history *regionOperatorHistory
+	if v, ok := o.histories.Get(op.RegionID()); ok {
+		history = v.(*regionOperatorHistory)
+	} else {
+		history = &regionOperatorHistory{}
+	}
+	history.records[history.next] = newOperatorRecord(op)
+	history.next = (history.next + 1) % regionOperatorHistorySize
+	if history.count < regionOperatorHistorySize {
+		history.count++
+	}
+	// put it again to refresh the TTL.
+	o.histories.Put(op.RegionID(), history)
+}
+
+// GetHistory returns the recently finished operators of the region, the
+// earlier finished the former.
+func (o *OperatorRecords) GetHistory(regionID uint64) []*OperatorRecord {
+	o.historyMu.Lock()
+	defer o.historyMu.Unlock()
+	v, ok := o.histories.Get(regionID)
+	if !ok {
+		return nil
+	}
+	history := v.(*regionOperatorHistory)
+	records := make([]*OperatorRecord, 0, history.count)
+	start := (history.next - history.count + regionOperatorHistorySize) % regionOperatorHistorySize
+	for i := 0; i < history.count; i++ {
+		records = append(records, history.records[(start+i)%regionOperatorHistorySize])
+	}
+	return records
 }
 
 // ExceedStoreLimit returns true if the store exceeds the cost limit after adding the operator. Otherwise, returns false.
@@ -901,6 +1171,43 @@ func (oc *OperatorController) exceedStoreLimitLocked(ops ...*operator.Operator)
 	return false
 }
 
+// StoreOperatorQuota is the remaining budget of a store to run operators.
+type StoreOperatorQuota struct {
+	// AddPeerRemaining is the number of add peer steps allowed by the store limit now.
+	AddPeerRemaining int `json:"add-peer-remaining"`
+	// RemovePeerRemaining is the number of remove peer steps allowed by the store limit now.
+	RemovePeerRemaining int `json:"remove-peer-remaining"`
+	// SnapshotRemaining is the number of snapshots the store can still handle.
+	SnapshotRemaining int `json:"snapshot-remaining"`
+}
+
+// GetStoreOperatorQuota returns the remaining budget of the store. The steps are
+// counted by the cost of a regular region, and the tokens taken by the
+// in-flight operators have already been deducted from the store limit.
+func (oc *OperatorController) GetStoreOperatorQuota(storeID uint64) StoreOperatorQuota {
+	var quota StoreOperatorQuota
+	store := oc.cluster.GetStore(storeID)
+	if store == nil {
+		return quota
+	}
+	oc.Lock()
+	quota.AddPeerRemaining = int(oc.getOrCreateStoreLimit(storeID, storelimit.AddPeer).Available() / storelimit.RegionInfluence[storelimit.AddPeer])
+	quota.RemovePeerRemaining = int(oc.getOrCreateStoreLimit(storeID, storelimit.RemovePeer).Available() / storelimit.RegionInfluence[storelimit.RemovePeer])
+	oc.Unlock()
+
+	snapCount := store.GetSendingSnapCount()
+	if count := store.GetReceivingSnapCount(); count > snapCount {
+		snapCount = count
+	}
+	if count := store.GetApplyingSnapCount(); count > snapCount {
+		snapCount = count
+	}
+	if maxCount := oc.cluster.GetOpts().GetMaxSnapshotCount(); uint64(snapCount) < maxCount {
+		quota.SnapshotRemaining = int(maxCount - uint64(snapCount))
+	}
+	return quota
+}
+
 // newStoreLimit is used to create the limit of a store.
 func (oc *OperatorController) newStoreLimit(storeID uint64, ratePerSec float64, limitType storelimit.Type) {
 	log.Info("create or update a store limit", zap.Uint64("store-id", storeID), zap.String("type", limitType.String()), zap.Float64("rate", ratePerSec))
@@ -938,3 +1245,115 @@ func (oc *OperatorController) GetLeaderSchedulePolicy() core.SchedulePolicy {
 	}
 	return oc.cluster.GetOpts().GetLeaderSchedulePolicy()
 }
+
+const (
+	stepDurationDecay = 0.2
+	// minStepDurationSamples is the min number of finished steps for a store
+	// to be judged as slow, which avoids the noise of a few slow steps.
+	minStepDurationSamples = 10
+)
+
+// stepDurationStats records the rolling average duration of the finished
+// operator steps on each store.
+type stepDurationStats struct {
+	sync.Mutex
+	avgs   map[uint64]*movingaverage.EMA
+	counts map[uint64]int
+}
+
+func newStepDurationStats() *stepDurationStats {
+	return &stepDurationStats{
+		avgs:   make(map[uint64]*movingaverage.EMA),
+		counts: make(map[uint64]int),
+	}
+}
+
+// record records the durations of the steps of a finished operator.
+func (s *stepDurationStats) record(op *operator.Operator) {
+	s.Lock()
+	defer s.Unlock()
+	for i := 0; i < op.Len(); i++ {
+		d := op.GetStepDuration(i)
+		if d <= 0 {
+			continue
+		}
+		for _, storeID := range stepStores(op.Step(i)) {
+			avg, ok := s.avgs[storeID]
+			if !ok {
+				avg = movingaverage.NewEMA(stepDurationDecay)
+				s.avgs[storeID] = avg
+			}
+			avg.Add(d.Seconds())
+			s.counts[storeID]++
+		}
+	}
+}
+
+// get returns the average step duration of the store and the number of samples.
+func (s *stepDurationStats) get(storeID uint64) (time.Duration, int) {
+	s.Lock()
+	defer s.Unlock()
+	avg, ok := s.avgs[storeID]
+	if !ok {
+		return 0, 0
+	}
+	return time.Duration(avg.Get() * float64(time.Second)), s.counts[storeID]
+}
+
+// stepStores returns the stores which need to take actions to finish the step.
+func stepStores(step operator.OpStep) []uint64 {
+	switch s := step.(type) {
+	case operator.TransferLeader:
+		return []uint64{s.FromStore, s.ToStore}
+	case operator.AddPeer:
+		return []uint64{s.ToStore}
+	case operator.AddLearner:
+		return []uint64{s.ToStore}
+	case operator.AddLightPeer:
+		return []uint64{s.ToStore}
+	case operator.AddLightLearner:
+		return []uint64{s.ToStore}
+	case operator.PromoteLearner:
+		return []uint64{s.ToStore}
+	case operator.DemoteFollower:
+		return []uint64{s.ToStore}
+	case operator.RemovePeer:
+		return []uint64{s.FromStore}
+	case operator.BatchRemovePeer:
+		return s.FromStores
+	case operator.ChangePeerV2Enter:
+		return changePeerV2Stores(s.PromoteLearners, s.DemoteVoters)
+	case operator.ChangePeerV2Leave:
+		return changePeerV2Stores(s.PromoteLearners, s.DemoteVoters)
+	default:
+		return nil
+	}
+}
+
+func changePeerV2Stores(pls []operator.PromoteLearner, dvs []operator.DemoteVoter) []uint64 {
+	stores := make([]uint64, 0, len(pls)+len(dvs))
+	for _, pl := range pls {
+		stores = append(stores, pl.ToStore)
+	}
+	for _, dv := range dvs {
+		stores = append(stores, dv.ToStore)
+	}
+	return stores
+}
+
+// GetSlowStores returns the stores whose rolling average step duration exceeds
+// the threshold. Only the stores which have finished enough steps are judged.
+func (oc *OperatorController) GetSlowStores(threshold time.Duration) []uint64 {
+	var slowStores []uint64
+	for _, store := range oc.cluster.GetStores() {
+		if store.IsTombstone() {
+			continue
+		}
+		avg, count := oc.stepDurations.get(store.GetID())
+		if count >= minStepDurationSamples && avg > threshold {
+			slowStores = append(slowStores, store.GetID())
+		}
+	}
+	sort.Slice(slowStores, func(i, j int) bool { return slowStores[i] < slowStores[j] })
+	return slowStores
+}
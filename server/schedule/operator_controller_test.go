@@ -22,9 +22,11 @@ import (
 	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
@@ -96,6 +98,31 @@ func (t *testOperatorControllerSuite) TestGetOpInfluence(c *C) {
 	c.Assert(oc.GetOperator(2), NotNil)
 }
 
+func (t *testOperatorControllerSuite) TestOperatorPool(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	oc := NewOperatorController(t.ctx, tc, nil)
+	c.Assert(opt.GetOperatorPoolSize(), Equals, 2)
+
+	buf := oc.getOperatorSlice()
+	c.Assert(*buf, HasLen, 0)
+	c.Assert(cap(*buf), Equals, 2)
+	op := operator.NewOperator("test", "test", 1, &metapb.RegionEpoch{}, operator.OpRegion, operator.RemovePeer{FromStore: 1})
+	*buf = append(*buf, op, op)
+	ops := *buf
+	oc.putOperatorSlice(buf)
+	// the operators are not kept alive by the pool.
+	c.Assert(*buf, HasLen, 0)
+	c.Assert(ops[0], IsNil)
+	c.Assert(ops[1], IsNil)
+
+	// The pool is disabled.
+	cfg := opt.GetScheduleConfig().Clone()
+	cfg.OperatorPoolSize = 0
+	opt.SetScheduleConfig(cfg)
+	c.Assert(oc.getOperatorSlice(), IsNil)
+}
+
 func (t *testOperatorControllerSuite) TestOperatorStatus(c *C) {
 	opt := config.NewTestOptions()
 	tc := mockcluster.NewCluster(opt)
@@ -131,6 +158,40 @@ func (t *testOperatorControllerSuite) TestOperatorStatus(c *C) {
 	c.Assert(oc.GetOperatorStatus(2).Status, Equals, pdpb.OperatorStatus_SUCCESS)
 }
 
+func (t *testOperatorControllerSuite) TestRegionOperatorHistory(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.AddLeaderStore(1, 1)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+	c.Assert(oc.GetRegionOperatorHistory(1), HasLen, 0)
+
+	var op *operator.Operator
+	for i := 0; i < regionOperatorHistorySize+2; i++ {
+		kind := operator.OpLeader
+		if i == regionOperatorHistorySize+1 {
+			kind |= operator.OpAdmin
+		}
+		op = operator.NewOperator("test", "test", 1, &metapb.RegionEpoch{}, kind, operator.TransferLeader{FromStore: 1, ToStore: 2})
+		c.Assert(op.Start(), IsTrue)
+		oc.SetOperator(op)
+		c.Assert(oc.RemoveOperator(op), IsTrue)
+	}
+	// only the latest operators are kept.
+	history := oc.GetRegionOperatorHistory(1)
+	c.Assert(history, HasLen, regionOperatorHistorySize)
+	last := history[regionOperatorHistorySize-1]
+	c.Assert(last.Kind, Equals, op.Kind().String())
+	c.Assert(last.CreatedAt, Equals, op.GetCreateTime())
+	c.Assert(last.FinishedAt, Equals, op.GetReachTimeOf(operator.CANCELED))
+	c.Assert(last.FinishReason, Equals, "CANCELED")
+	c.Assert(last.Steps, DeepEquals, []string{op.Step(0).String()})
+	c.Assert(history[0].Kind, Equals, operator.OpLeader.String())
+	c.Assert(oc.GetRegionOperatorHistory(2), HasLen, 0)
+}
+
 func (t *testOperatorControllerSuite) TestFastFailOperator(c *C) {
 	opt := config.NewTestOptions()
 	tc := mockcluster.NewCluster(opt)
@@ -571,6 +632,63 @@ func (t *testOperatorControllerSuite) TestDispatchUnfinishedStep(c *C) {
 	}
 }
 
+func (t *testOperatorControllerSuite) TestGetRegionOperatorAge(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	oc := NewOperatorController(t.ctx, tc, nil)
+	tc.AddLeaderStore(1, 1)
+	tc.AddLeaderStore(2, 1)
+	tc.AddLeaderRegion(1, 1, 2)
+
+	_, ok := oc.GetRegionOperatorAge(1)
+	c.Assert(ok, IsFalse)
+
+	op := operator.NewOperator("test", "test", 1, &metapb.RegionEpoch{}, operator.OpRegion, operator.RemovePeer{FromStore: 2})
+	c.Assert(op.Start(), IsTrue)
+	oc.SetOperator(op)
+	time.Sleep(10 * time.Millisecond)
+	age, ok := oc.GetRegionOperatorAge(1)
+	c.Assert(ok, IsTrue)
+	c.Assert(age >= 10*time.Millisecond, IsTrue)
+
+	c.Assert(oc.RemoveOperator(op), IsTrue)
+	_, ok = oc.GetRegionOperatorAge(1)
+	c.Assert(ok, IsFalse)
+}
+
+func (t *testOperatorControllerSuite) TestGetStoreOperatorQuota(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1)
+
+	// unknown store has no quota.
+	c.Assert(oc.GetStoreOperatorQuota(10), DeepEquals, StoreOperatorQuota{})
+
+	maxSnapshotCount := int(tc.GetOpts().GetMaxSnapshotCount())
+	tc.SetStoreLimit(2, storelimit.AddPeer, 120)
+	tc.SetStoreLimit(2, storelimit.RemovePeer, 120)
+	quota := oc.GetStoreOperatorQuota(2)
+	c.Assert(quota.AddPeerRemaining, Equals, 2)
+	c.Assert(quota.RemovePeerRemaining, Equals, 2)
+	c.Assert(quota.SnapshotRemaining, Equals, maxSnapshotCount)
+
+	// the in-flight operator takes the quota.
+	op := operator.NewOperator("test", "test", 1, &metapb.RegionEpoch{}, operator.OpRegion, operator.AddPeer{ToStore: 2, PeerID: 2})
+	c.Assert(oc.AddOperator(op), IsTrue)
+	quota = oc.GetStoreOperatorQuota(2)
+	c.Assert(quota.AddPeerRemaining, Equals, 1)
+	c.Assert(quota.RemovePeerRemaining, Equals, 2)
+
+	tc.UpdateSnapshotCount(2, 1)
+	c.Assert(oc.GetStoreOperatorQuota(2).SnapshotRemaining, Equals, maxSnapshotCount-1)
+	tc.UpdateSnapshotCount(2, maxSnapshotCount+1)
+	c.Assert(oc.GetStoreOperatorQuota(2).SnapshotRemaining, Equals, 0)
+}
+
 func (t *testOperatorControllerSuite) TestStoreLimitWithMerge(c *C) {
 	cfg := config.NewTestOptions()
 	tc := mockcluster.NewCluster(cfg)
@@ -700,3 +818,134 @@ func (t *testOperatorControllerSuite) TestAddWaitingOperator(c *C) {
 	// no space left, new operator can not be added.
 	c.Assert(controller.AddWaitingOperator(addPeerOp(0)), Equals, 0)
 }
+
+func (t *testOperatorControllerSuite) TestMaxTotalOperators(c *C) {
+	tc := mockcluster.NewCluster(config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	tc.SetMaxTotalOperators(10)
+	transferLeaderOp := func(regionID uint64, level core.PriorityLevel) *operator.Operator {
+		tc.AddLeaderRegion(regionID, 1, 2)
+		op := operator.NewOperator(fmt.Sprintf("test-%d", regionID), "test", regionID, tc.GetRegion(regionID).GetRegionEpoch(), operator.OpLeader, operator.TransferLeader{FromStore: 1, ToStore: 2})
+		op.SetPriorityLevel(level)
+		return op
+	}
+
+	// 8 running operators reach the limit of the low priority operators.
+	for i := uint64(1); i <= 8; i++ {
+		c.Assert(oc.AddOperator(transferLeaderOp(i, core.NormalPriority)), IsTrue)
+	}
+	c.Assert(oc.AddWaitingOperator(transferLeaderOp(9, core.LowPriority)), Equals, 0)
+	c.Assert(oc.AddWaitingOperator(transferLeaderOp(10, core.NormalPriority)), Equals, 1)
+	c.Assert(oc.AddWaitingOperator(transferLeaderOp(11, core.NormalPriority)), Equals, 0)
+	c.Assert(oc.AddWaitingOperator(transferLeaderOp(12, core.HighPriority)), Equals, 1)
+	c.Assert(oc.AddWaitingOperator(transferLeaderOp(13, core.HighPriority)), Equals, 0)
+	c.Assert(oc.GetOperators(), HasLen, 10)
+
+	// No limit.
+	tc.SetMaxTotalOperators(0)
+	c.Assert(oc.AddWaitingOperator(transferLeaderOp(14, core.LowPriority)), Equals, 1)
+}
+
+func (t *testOperatorControllerSuite) TestMaxWaitingOperatorsPerKind(c *C) {
+	tc := mockcluster.NewCluster(config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+	tc.AddLeaderRegion(2, 1, 2)
+	transferLeader := operator.NewOperator("test", "test", 1, tc.GetRegion(1).GetRegionEpoch(), operator.OpLeader, operator.TransferLeader{FromStore: 1, ToStore: 2})
+	ops, err := operator.CreateMergeRegionOperator("merge-region", tc, tc.GetRegion(1), tc.GetRegion(2), operator.OpMerge)
+	c.Assert(err, IsNil)
+
+	// the waiting merge operators are counted as one.
+	oc.wop.PutOperator(ops[0])
+	oc.wop.PutOperator(ops[1])
+	tc.SetMaxWaitingOperatorsPerKind("merge", 2)
+	c.Assert(oc.exceedWaitingKindLimitLocked(ops[0]), IsFalse)
+	c.Assert(oc.exceedWaitingKindLimitLocked(transferLeader), IsFalse)
+	tc.SetMaxWaitingOperatorsPerKind("merge", 1)
+	c.Assert(oc.exceedWaitingKindLimitLocked(ops[0]), IsTrue)
+	// other kinds are not limited.
+	c.Assert(oc.exceedWaitingKindLimitLocked(transferLeader), IsFalse)
+
+	tc.SetMaxWaitingOperatorsPerKind("leader", 0)
+	c.Assert(oc.AddWaitingOperator(transferLeader), Equals, 0)
+	c.Assert(transferLeader.Status(), Equals, operator.CANCELED)
+}
+
+func (t *testOperatorControllerSuite) TestGetSlowStores(c *C) {
+	tc := mockcluster.NewCluster(config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderStore(3, 0)
+	finishTransferLeader := func(regionID uint64) {
+		tc.AddLeaderRegion(regionID, 1, 2)
+		op := operator.NewOperator("test", "test", regionID, tc.GetRegion(regionID).GetRegionEpoch(), operator.OpLeader, operator.TransferLeader{FromStore: 1, ToStore: 2})
+		c.Assert(oc.AddOperator(op), IsTrue)
+		tc.AddLeaderRegion(regionID, 2, 1)
+		oc.Dispatch(tc.GetRegion(regionID), DispatchFromHeartBeat)
+		c.Assert(op.Status(), Equals, operator.SUCCESS)
+		c.Assert(op.GetStepDuration(0), Greater, time.Duration(0))
+	}
+
+	// not enough samples.
+	for i := uint64(1); i < minStepDurationSamples; i++ {
+		finishTransferLeader(i)
+	}
+	c.Assert(oc.GetSlowStores(0), HasLen, 0)
+	finishTransferLeader(minStepDurationSamples)
+	c.Assert(oc.GetSlowStores(0), DeepEquals, []uint64{1, 2})
+	c.Assert(oc.GetSlowStores(time.Hour), HasLen, 0)
+}
+
+func (t *testOperatorControllerSuite) TestRegionLock(c *C) {
+	tc := mockcluster.NewCluster(config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderStore(3, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+	epoch := tc.GetRegion(1).GetRegionEpoch()
+
+	// a running operator with multiple steps locks the region.
+	steps := []operator.OpStep{
+		operator.AddLearner{ToStore: 3, PeerID: 3},
+		operator.PromoteLearner{ToStore: 3, PeerID: 3},
+		operator.RemovePeer{FromStore: 2},
+	}
+	op1 := operator.NewOperator("test", "test", 1, epoch, operator.OpRegion, steps...)
+	c.Assert(oc.AddOperator(op1), IsTrue)
+	c.Assert(op1.HasStarted(), IsTrue)
+
+	// even a higher priority operator cannot replace it.
+	op2 := operator.NewOperator("test", "test", 1, epoch, operator.OpRegion, operator.RemovePeer{FromStore: 2})
+	op2.SetPriorityLevel(core.HighPriority)
+	added, err := oc.TryAddWaitingOperator(op2)
+	c.Assert(added, Equals, 0)
+	c.Assert(errors.ErrorEqual(err, errs.ErrRegionLocked.FastGenByArgs(1, "test")), IsTrue)
+	c.Assert(op2.Status(), Equals, operator.CANCELED)
+	c.Assert(oc.GetOperator(1), Equals, op1)
+
+	// the admin operator can break the lock.
+	op3 := operator.NewOperator("test", "test", 1, epoch, operator.OpRegion|operator.OpAdmin, operator.RemovePeer{FromStore: 2})
+	added, err = oc.TryAddWaitingOperator(op3)
+	c.Assert(err, IsNil)
+	c.Assert(added, Equals, 1)
+	c.Assert(oc.GetOperator(1), Equals, op3)
+
+	// a single step operator does not lock the region.
+	c.Assert(oc.RemoveOperator(op3), IsTrue)
+	op4 := operator.NewOperator("test", "test", 1, epoch, operator.OpRegion, operator.RemovePeer{FromStore: 2})
+	c.Assert(oc.AddOperator(op4), IsTrue)
+	op5 := operator.NewOperator("test", "test", 1, epoch, operator.OpRegion, operator.RemovePeer{FromStore: 2})
+	op5.SetPriorityLevel(core.HighPriority)
+	c.Assert(oc.AddWaitingOperator(op5), Equals, 1)
+	c.Assert(oc.GetOperator(1), Equals, op5)
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/movingaverage"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultSurgeThresholdRatio is the default ratio of the operator generation
+	// rate to the rolling average above which the rate is regarded as a surge.
+	DefaultSurgeThresholdRatio = 3.0
+	// DefaultSurgeWindow is the default duration a surge needs to last before
+	// it is reported.
+	DefaultSurgeWindow = 10 * time.Second
+	// minSurgeSamples is the min number of seconds observed before judging a
+	// surge, which avoids the noise when the rolling average is not stable yet.
+	minSurgeSamples = 10
+	// maxSurgeIdleSamples bounds the number of idle seconds added to the rolling
+	// average after a long time without operators.
+	maxSurgeIdleSamples = 60
+)
+
+// OperatorSurgeDetector detects the spike of the operator generation rate. It
+// counts the operators added in each second and compares the count with the
+// rolling average of the previous seconds.
+type OperatorSurgeDetector struct {
+	sync.Mutex
+	thresholdRatio float64
+	window         time.Duration
+
+	avg     *movingaverage.EMA
+	samples int
+	// bucketStart is the start of the current second and bucketCount is the
+	// number of operators added in it.
+	bucketStart time.Time
+	bucketCount int
+	// surgeStart is the start of the current surge, it is zero if there is no surge.
+	surgeStart time.Time
+	reported   bool
+}
+
+// NewOperatorSurgeDetector creates an OperatorSurgeDetector.
+func NewOperatorSurgeDetector(thresholdRatio float64, window time.Duration) *OperatorSurgeDetector {
+	return &OperatorSurgeDetector{
+		thresholdRatio: thresholdRatio,
+		window:         window,
+		avg:            movingaverage.NewEMA(),
+	}
+}
+
+// SetThreshold updates the threshold ratio and the window of the detector.
+func (d *OperatorSurgeDetector) SetThreshold(thresholdRatio float64, window time.Duration) {
+	d.Lock()
+	defer d.Unlock()
+	d.thresholdRatio = thresholdRatio
+	d.window = window
+}
+
+// Record records an operator added at the given time. It returns true if a
+// surge is reported by this call.
+func (d *OperatorSurgeDetector) Record(now time.Time) bool {
+	d.Lock()
+	defer d.Unlock()
+	if d.bucketStart.IsZero() {
+		d.bucketStart = now.Truncate(time.Second)
+	}
+	reported := false
+	if elapsed := now.Sub(d.bucketStart); elapsed >= time.Second {
+		reported = d.closeBucket(now)
+		// the seconds without any operator.
+		idle := int(elapsed/time.Second) - 1
+		if idle > maxSurgeIdleSamples {
+			idle = maxSurgeIdleSamples
+		}
+		for i := 0; i < idle; i++ {
+			d.addSample(0)
+		}
+		if idle > 0 {
+			d.surgeStart, d.reported = time.Time{}, false
+		}
+		d.bucketStart, d.bucketCount = now.Truncate(time.Second), 0
+	}
+	d.bucketCount++
+	return reported
+}
+
+// closeBucket judges the rate of the current second. The rate is added to the
+// rolling average only if it is not a surge, so that the average is not
+// dragged up by the surge itself.
+func (d *OperatorSurgeDetector) closeBucket(now time.Time) bool {
+	rate := float64(d.bucketCount)
+	avg := d.avg.Get()
+	if d.samples >= minSurgeSamples && avg > 0 && rate > d.thresholdRatio*avg {
+		if d.surgeStart.IsZero() {
+			d.surgeStart = d.bucketStart
+		}
+		if !d.reported && now.Sub(d.surgeStart) > d.window {
+			log.Warn("operator generation surges",
+				zap.Float64("rate", rate),
+				zap.Float64("rolling-average", avg),
+				zap.Duration("duration", now.Sub(d.surgeStart)))
+			operatorSurgeCounter.Inc()
+			d.reported = true
+			return true
+		}
+		return false
+	}
+	d.surgeStart, d.reported = time.Time{}, false
+	d.addSample(rate)
+	return false
+}
+
+func (d *OperatorSurgeDetector) addSample(rate float64) {
+	d.avg.Add(rate)
+	d.samples++
+}
@@ -0,0 +1,73 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testOperatorSurgeSuite{})
+
+type testOperatorSurgeSuite struct{}
+
+func (s *testOperatorSurgeSuite) TestOperatorSurgeDetector(c *C) {
+	d := NewOperatorSurgeDetector(DefaultSurgeThresholdRatio, DefaultSurgeWindow)
+	start := time.Now().Truncate(time.Second)
+	at := func(sec int, i int) time.Time {
+		return start.Add(time.Duration(sec)*time.Second + time.Duration(i)*100*time.Millisecond)
+	}
+	// 1 operator per second.
+	for sec := 0; sec < 20; sec++ {
+		c.Assert(d.Record(at(sec, 0)), IsFalse)
+	}
+	// 5 operators per second, which is reported only once after it lasts
+	// for more than the window.
+	var reported []int
+	for sec := 20; sec < 40; sec++ {
+		for i := 0; i < 5; i++ {
+			if d.Record(at(sec, i)) {
+				reported = append(reported, sec)
+			}
+		}
+	}
+	c.Assert(reported, DeepEquals, []int{31})
+
+	// a surge shorter than the window is not reported.
+	d = NewOperatorSurgeDetector(DefaultSurgeThresholdRatio, DefaultSurgeWindow)
+	for sec := 0; sec < 20; sec++ {
+		c.Assert(d.Record(at(sec, 0)), IsFalse)
+	}
+	for sec := 20; sec < 25; sec++ {
+		for i := 0; i < 5; i++ {
+			c.Assert(d.Record(at(sec, i)), IsFalse)
+		}
+	}
+	for sec := 25; sec < 45; sec++ {
+		c.Assert(d.Record(at(sec, 0)), IsFalse)
+	}
+
+	// a rate below the threshold ratio is not a surge.
+	d = NewOperatorSurgeDetector(DefaultSurgeThresholdRatio, DefaultSurgeWindow)
+	d.SetThreshold(6.0, DefaultSurgeWindow)
+	for sec := 0; sec < 20; sec++ {
+		c.Assert(d.Record(at(sec, 0)), IsFalse)
+	}
+	for sec := 20; sec < 40; sec++ {
+		for i := 0; i < 5; i++ {
+			c.Assert(d.Record(at(sec, i)), IsFalse)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"sort"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
 )
 
 // PeerRoleType is the expected peer type of the placement rule.
@@ -47,6 +48,35 @@ func (s PeerRoleType) MetaPeerRole() metapb.PeerRole {
 	return metapb.PeerRole_Voter
 }
 
+// SplitPolicy is the policy used to split regions at the boundaries of the rule.
+type SplitPolicy string
+
+const (
+	// SplitUseKey splits the region by the keys of the rule boundaries.
+	SplitUseKey SplitPolicy = "USEKEY"
+	// SplitApproximate lets TiKV split the region by the approximate middle key.
+	SplitApproximate SplitPolicy = "APPROXIMATE"
+	// SplitScan lets TiKV split the region by scanning the keys.
+	SplitScan SplitPolicy = "SCAN"
+)
+
+func validateSplitPolicy(p SplitPolicy) bool {
+	return p == "" || p == SplitUseKey || p == SplitApproximate || p == SplitScan
+}
+
+// CheckPolicy converts placement.SplitPolicy to pdpb.CheckPolicy. USEKEY is
+// used if the policy is not set.
+func (p SplitPolicy) CheckPolicy() pdpb.CheckPolicy {
+	switch p {
+	case SplitApproximate:
+		return pdpb.CheckPolicy_APPROXIMATE
+	case SplitScan:
+		return pdpb.CheckPolicy_SCAN
+	default:
+		return pdpb.CheckPolicy_USEKEY
+	}
+}
+
 // Rule is the placement rule that can be checked against a region. When
 // applying rules (apply means schedule regions to match selected rules), the
 // apply order is defined by the tuple [GroupIndex, GroupID, Index, ID].
@@ -64,6 +94,7 @@ type Rule struct {
 	LabelConstraints []LabelConstraint `json:"label_constraints,omitempty"` // used to select stores to place peers
 	LocationLabels   []string          `json:"location_labels,omitempty"`   // used to make peers isolated physically
 	IsolationLevel   string            `json:"isolation_level,omitempty"`   // used to isolate replicas explicitly and forcibly
+	SplitPolicy      SplitPolicy       `json:"split_policy,omitempty"`      // used to split regions at the rule boundaries
 
 	group *RuleGroup // only set at runtime, no need to {,un}marshal or persist.
 }
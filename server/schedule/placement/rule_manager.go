@@ -190,6 +190,9 @@ func (m *RuleManager) adjustRule(r *Rule, groupID string) (err error) {
 	if !validateRole(r.Role) {
 		return errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("invalid role %s", r.Role))
 	}
+	if !validateSplitPolicy(r.SplitPolicy) {
+		return errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("invalid split policy %s", r.SplitPolicy))
+	}
 	if r.Count <= 0 {
 		return errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("invalid count %d", r.Count))
 	}
@@ -378,6 +381,30 @@ func (m *RuleManager) SetRules(rules []*Rule) error {
 	return nil
 }
 
+// ReplaceRules replaces all the rules with the given ones. The configs of the
+// groups which still have rules are kept. Either all the rules are replaced or
+// nothing is changed in memory.
+func (m *RuleManager) ReplaceRules(rules []*Rule) error {
+	m.Lock()
+	defer m.Unlock()
+	p := m.beginPatch()
+	for k := range m.ruleConfig.rules {
+		p.deleteRule(k[0], k[1])
+	}
+	for _, r := range rules {
+		if err := m.adjustRule(r, ""); err != nil {
+			return err
+		}
+		p.setRule(r)
+	}
+	if err := m.tryCommitPatch(p); err != nil {
+		return err
+	}
+
+	log.Info("placement rules replaced", zap.String("rules", fmt.Sprint(rules)))
+	return nil
+}
+
 // RuleOpType indicates the operation type
 type RuleOpType string
 
@@ -580,6 +607,63 @@ func (m *RuleManager) SetAllGroupBundles(groups []GroupBundle, override bool) er
 	return nil
 }
 
+// ValidateGroupBundles checks if the groups can be used to reset all rules and
+// groups like SetAllGroupBundles, but nothing is applied. It returns all the
+// problems found instead of the first one.
+func (m *RuleManager) ValidateGroupBundles(groups []GroupBundle) []error {
+	m.RLock()
+	defer m.RUnlock()
+	var problems []error
+	p := m.beginPatch()
+	for k := range m.ruleConfig.rules {
+		p.deleteRule(k[0], k[1])
+	}
+	for id := range m.ruleConfig.groups {
+		p.deleteGroup(id)
+	}
+	groupIDs := make(map[string]struct{})
+	for _, g := range groups {
+		if g.ID == "" {
+			problems = append(problems, errs.ErrRuleContent.FastGenByArgs("group ID should not be empty"))
+			continue
+		}
+		if _, ok := groupIDs[g.ID]; ok {
+			problems = append(problems, errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("duplicated group ID %s", g.ID)))
+			continue
+		}
+		groupIDs[g.ID] = struct{}{}
+		if g.Index < 0 {
+			problems = append(problems, errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("invalid index %d of group %s", g.Index, g.ID)))
+		}
+		p.setGroup(&RuleGroup{
+			ID:       g.ID,
+			Index:    g.Index,
+			Override: g.Override,
+		})
+		ruleIDs := make(map[string]struct{})
+		for _, r := range g.Rules {
+			if err := m.adjustRule(r, g.ID); err != nil {
+				problems = append(problems, err)
+				continue
+			}
+			if _, ok := ruleIDs[r.ID]; ok {
+				problems = append(problems, errs.ErrRuleContent.FastGenByArgs(fmt.Sprintf("duplicated rule ID %s in group %s", r.ID, g.ID)))
+				continue
+			}
+			ruleIDs[r.ID] = struct{}{}
+			p.setRule(r)
+		}
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+	p.adjust()
+	if _, err := buildRuleList(p); err != nil {
+		problems = append(problems, err)
+	}
+	return problems
+}
+
 // SetGroupBundle resets a Group and all rules belong to it. All old rules
 // belong to the Group are dropped.
 func (m *RuleManager) SetGroupBundle(group GroupBundle) error {
@@ -650,6 +734,38 @@ func (m *RuleManager) IsInitialized() bool {
 	return m.initialized
 }
 
+// CheckRuleConflicts merges the rules into the current rules the same way as
+// SetRules, and returns the pairs of the merged rules which involve any of the
+// given rules and cannot be satisfied by the stores at the same time. Nothing
+// is saved.
+func (m *RuleManager) CheckRuleConflicts(rules []*Rule, stores []*core.StoreInfo) ([]RuleConflict, error) {
+	m.Lock()
+	defer m.Unlock()
+	p := m.beginPatch()
+	for _, r := range rules {
+		if err := m.adjustRule(r, ""); err != nil {
+			return nil, err
+		}
+		p.setRule(r)
+	}
+	p.adjust()
+	if _, err := buildRuleList(p); err != nil {
+		return nil, err
+	}
+
+	var merged []*Rule
+	p.iterateRules(func(r *Rule) { merged = append(merged, r) })
+	var conflicts []RuleConflict
+	for _, conflict := range ValidateRuleSet(merged, stores) {
+		if _, ok := p.mut.rules[conflict.Rules[0].Key()]; ok {
+			conflicts = append(conflicts, conflict)
+		} else if _, ok := p.mut.rules[conflict.Rules[1].Key()]; ok {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	return conflicts, nil
+}
+
 // checkRule check the rule whether will have RuleFit after FitRegion
 // in order to reduce the calculation.
 func checkRule(rule *Rule, stores []*core.StoreInfo) bool {
@@ -62,6 +62,7 @@ func (s *testManagerSuite) TestAdjustRule(c *C) {
 		{GroupID: "group", ID: "id", StartKeyHex: "123abc", EndKeyHex: "123abf", Role: "voter", Count: 0},
 		{GroupID: "group", ID: "id", StartKeyHex: "123abc", EndKeyHex: "123abf", Role: "voter", Count: -1},
 		{GroupID: "group", ID: "id", StartKeyHex: "123abc", EndKeyHex: "123abf", Role: "voter", Count: 3, LabelConstraints: []LabelConstraint{{Op: "foo"}}},
+		{GroupID: "group", ID: "id", StartKeyHex: "123abc", EndKeyHex: "123abf", Role: "voter", Count: 3, SplitPolicy: "foo"},
 	}
 	c.Assert(s.manager.adjustRule(&rules[0], "group"), IsNil)
 	c.Assert(rules[0].StartKey, DeepEquals, []byte{0x12, 0x3a, 0xbc})
@@ -255,6 +256,34 @@ func (s *testManagerSuite) TestRangeGap(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (s *testManagerSuite) TestReplaceRules(c *C) {
+	err := s.manager.SetRules([]*Rule{
+		{GroupID: "foo", ID: "1", Role: "voter", Count: 1},
+		{GroupID: "foo", ID: "2", Role: "voter", Count: 1},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(s.manager.GetAllRules(), HasLen, 3)
+
+	// the rules which are not given are removed.
+	err = s.manager.ReplaceRules([]*Rule{
+		{GroupID: "pd", ID: "default", Role: "voter", Count: 3},
+		{GroupID: "bar", ID: "1", Role: "learner", Count: 1},
+	})
+	c.Assert(err, IsNil)
+	rules := s.manager.GetAllRules()
+	c.Assert(rules, HasLen, 2)
+	c.Assert(rules[0].Key(), Equals, [2]string{"bar", "1"})
+	c.Assert(rules[1].Key(), Equals, [2]string{"pd", "default"})
+	c.Assert(s.manager.GetRuleGroup("foo"), IsNil)
+
+	// nothing is changed if the rules leave a range without rules.
+	err = s.manager.ReplaceRules([]*Rule{
+		{GroupID: "pd", ID: "foo", StartKeyHex: "", EndKeyHex: "abcd", Role: "voter", Count: 3},
+	})
+	c.Assert(err, NotNil)
+	c.Assert(s.manager.GetAllRules(), DeepEquals, rules)
+}
+
 func (s *testManagerSuite) TestGroupConfig(c *C) {
 	// group pd
 	pd1 := &RuleGroup{ID: "pd"}
@@ -352,6 +381,62 @@ func (s *testManagerSuite) TestCheckApplyRules(c *C) {
 	c.Assert(err, ErrorMatches, "needs at least one leader or voter")
 }
 
+func (s *testManagerSuite) TestValidateGroupBundles(c *C) {
+	valid := []GroupBundle{
+		{ID: "pd", Rules: []*Rule{{ID: "default", Role: "voter", Count: 3}}},
+		{ID: "foo", Index: 1, Rules: []*Rule{{GroupID: "foo", ID: "bar", Role: "learner", Count: 1}}},
+	}
+	c.Assert(s.manager.ValidateGroupBundles(valid), HasLen, 0)
+	// nothing is applied.
+	c.Assert(s.manager.GetRuleGroup("foo"), IsNil)
+	c.Assert(s.manager.GetAllRules(), HasLen, 1)
+
+	invalid := []GroupBundle{
+		{ID: "pd", Rules: []*Rule{{ID: "default", Role: "voter", Count: 3}}},
+		{ID: "pd"},
+		{ID: "foo", Index: -1, Rules: []*Rule{
+			{GroupID: "bar", ID: "baz", Role: "voter", Count: 1},
+			{ID: "baz", Role: "voter", Count: 1},
+			{ID: "baz", Role: "voter", Count: 1},
+		}},
+	}
+	// duplicated group, invalid index, mismatched group and duplicated rule.
+	c.Assert(s.manager.ValidateGroupBundles(invalid), HasLen, 4)
+	// no rule left.
+	c.Assert(s.manager.ValidateGroupBundles([]GroupBundle{{ID: "pd"}}), HasLen, 1)
+}
+
+func (s *testManagerSuite) TestCheckRuleConflicts(c *C) {
+	stores := []*core.StoreInfo{
+		core.NewStoreInfoWithLabel(1, 0, map[string]string{"zone": "z1"}),
+		core.NewStoreInfoWithLabel(2, 0, map[string]string{"zone": "z2"}),
+		core.NewStoreInfoWithLabel(3, 0, map[string]string{"zone": "z3"}),
+	}
+	// the new rule conflicts with the default rule, which requires all the stores.
+	conflicts, err := s.manager.CheckRuleConflicts([]*Rule{{GroupID: "g", ID: "1", Role: "voter", Count: 1}}, stores)
+	c.Assert(err, IsNil)
+	c.Assert(conflicts, HasLen, 1)
+	c.Assert(conflicts[0].Rules[0].Key(), Equals, [2]string{"g", "1"})
+	c.Assert(conflicts[0].Rules[1].Key(), Equals, [2]string{"pd", "default"})
+	// nothing is saved.
+	c.Assert(s.manager.GetRule("g", "1"), IsNil)
+
+	// the rule is not applied with the default rule once its group overrides group pd.
+	err = s.manager.SetRuleGroup(&RuleGroup{ID: "g", Index: 1, Override: true})
+	c.Assert(err, IsNil)
+	conflicts, err = s.manager.CheckRuleConflicts([]*Rule{{GroupID: "g", ID: "1", Role: "voter", Count: 1}}, stores)
+	c.Assert(err, IsNil)
+	c.Assert(conflicts, HasLen, 0)
+
+	// the rule in the batch replaces the current one.
+	conflicts, err = s.manager.CheckRuleConflicts([]*Rule{{GroupID: "pd", ID: "default", Role: "voter", Count: 2}, {GroupID: "pd", ID: "2", Role: "voter", Count: 1}}, stores)
+	c.Assert(err, IsNil)
+	c.Assert(conflicts, HasLen, 0)
+	conflicts, err = s.manager.CheckRuleConflicts([]*Rule{{GroupID: "pd", ID: "2", Role: "voter", Count: 1}}, stores)
+	c.Assert(err, IsNil)
+	c.Assert(conflicts, HasLen, 1)
+}
+
 func (s *testManagerSuite) dhex(hk string) []byte {
 	k, err := hex.DecodeString(hk)
 	if err != nil {
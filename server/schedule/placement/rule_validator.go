@@ -0,0 +1,87 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	"bytes"
+
+	"github.com/tikv/pd/server/core"
+)
+
+// RuleConflict is a pair of rules with overlapping key ranges, which cannot
+// be satisfied at the same time because too few stores match them.
+type RuleConflict struct {
+	Rules [2]*Rule `json:"rules"`
+	// EligibleStores is the number of stores matching either of the rules.
+	EligibleStores int `json:"eligible_stores"`
+	// RequiredStores is the number of peers required by the two rules, each
+	// of them needs a different store.
+	RequiredStores int `json:"required_stores"`
+}
+
+// ValidateRuleSet returns the pairs of rules whose combined label constraints
+// leave fewer eligible stores than the peers they require. The rules should
+// have been adjusted with their groups set up, and a rule overridden by the
+// other is not regarded as a conflict since they are never applied together.
+func ValidateRuleSet(rules []*Rule, stores []*core.StoreInfo) []RuleConflict {
+	sorted := append(rules[:0:0], rules...)
+	sortRules(sorted)
+	eligible := make([]map[uint64]struct{}, len(sorted))
+	for i, r := range sorted {
+		eligible[i] = make(map[uint64]struct{})
+		for _, store := range stores {
+			if store.IsUp() && MatchLabelConstraints(store, r.LabelConstraints) {
+				eligible[i][store.GetID()] = struct{}{}
+			}
+		}
+	}
+
+	var conflicts []RuleConflict
+	for i, a := range sorted {
+		for j := i + 1; j < len(sorted); j++ {
+			b := sorted[j]
+			if !isRangeOverlapped(a, b) || isOverriddenBy(a, b) {
+				continue
+			}
+			union := len(eligible[i])
+			for id := range eligible[j] {
+				if _, ok := eligible[i][id]; !ok {
+					union++
+				}
+			}
+			if required := a.Count + b.Count; union < required {
+				conflicts = append(conflicts, RuleConflict{
+					Rules:          [2]*Rule{a, b},
+					EligibleStores: union,
+					RequiredStores: required,
+				})
+			}
+		}
+	}
+	return conflicts
+}
+
+func isRangeOverlapped(a, b *Rule) bool {
+	return (len(b.EndKey) == 0 || bytes.Compare(a.StartKey, b.EndKey) < 0) &&
+		(len(a.EndKey) == 0 || bytes.Compare(b.StartKey, a.EndKey) < 0)
+}
+
+// isOverriddenBy checks if rule a is overridden by rule b, b must be sorted
+// after a. See prepareRulesForApply.
+func isOverriddenBy(a, b *Rule) bool {
+	if a.GroupID == b.GroupID {
+		return b.Override
+	}
+	return b.group != nil && b.group.Override
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/server/core"
+)
+
+var _ = Suite(&testRuleValidatorSuite{})
+
+type testRuleValidatorSuite struct{}
+
+func (s *testRuleValidatorSuite) TestValidateRuleSet(c *C) {
+	stores := []*core.StoreInfo{
+		core.NewStoreInfoWithLabel(1, 0, map[string]string{"zone": "z1"}),
+		core.NewStoreInfoWithLabel(2, 0, map[string]string{"zone": "z1"}),
+		core.NewStoreInfoWithLabel(3, 0, map[string]string{"zone": "z2"}),
+		core.NewStoreInfoWithLabel(4, 0, map[string]string{"zone": "z3"}),
+	}
+	inZone := func(zones ...string) []LabelConstraint {
+		return []LabelConstraint{{Key: "zone", Op: In, Values: zones}}
+	}
+	voters := &Rule{GroupID: "pd", ID: "voters", Role: Voter, Count: 2, LabelConstraints: inZone("z1", "z2")}
+	learner := &Rule{GroupID: "pd", ID: "learner", Role: Learner, Count: 1, LabelConstraints: inZone("z2")}
+	c.Assert(ValidateRuleSet([]*Rule{voters, learner}, stores), HasLen, 0)
+
+	// 3 voters and 1 learner need 4 stores, but only 3 stores are in z1 and z2.
+	voters.Count = 3
+	conflicts := ValidateRuleSet([]*Rule{voters, learner}, stores)
+	c.Assert(conflicts, HasLen, 1)
+	c.Assert(conflicts[0].Rules, DeepEquals, [2]*Rule{learner, voters})
+	c.Assert(conflicts[0].EligibleStores, Equals, 3)
+	c.Assert(conflicts[0].RequiredStores, Equals, 4)
+
+	// the rules are not applied together if the key ranges are not overlapped.
+	voters.EndKey, learner.StartKey = []byte("b"), []byte("b")
+	c.Assert(ValidateRuleSet([]*Rule{voters, learner}, stores), HasLen, 0)
+
+	// the rules are not applied together if one overrides the other.
+	voters.EndKey, learner.StartKey = nil, nil
+	learner.Index, learner.Override = 1, true
+	c.Assert(ValidateRuleSet([]*Rule{voters, learner}, stores), HasLen, 0)
+}
@@ -14,9 +14,12 @@
 package schedule
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
 	"plugin"
 	"sync"
+	"time"
 
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/errs"
@@ -25,14 +28,22 @@ import (
 
 // PluginInterface is used to manage all plugin.
 type PluginInterface struct {
-	pluginMap     map[string]*plugin.Plugin
+	pluginMap map[string]*plugin.Plugin
+	// pluginFiles records the stat of the plugin files when they are opened.
+	pluginFiles   map[string]pluginFile
 	pluginMapLock sync.RWMutex
 }
 
+type pluginFile struct {
+	modTime time.Time
+	size    int64
+}
+
 // NewPluginInterface create a plugin interface
 func NewPluginInterface() *PluginInterface {
 	return &PluginInterface{
 		pluginMap:     make(map[string]*plugin.Plugin),
+		pluginFiles:   make(map[string]pluginFile),
 		pluginMapLock: sync.RWMutex{},
 	}
 }
@@ -53,6 +64,9 @@ func (p *PluginInterface) GetFunction(path string, funcName string) (plugin.Symb
 			return nil, errs.ErrLoadPlugin.Wrap(err).FastGenWithCause()
 		}
 		p.pluginMap[path] = plugin
+		if info, err := os.Stat(filePath); err == nil {
+			p.pluginFiles[path] = pluginFile{modTime: info.ModTime(), size: info.Size()}
+		}
 	}
 	//get func from plugin
 	f, err := p.pluginMap[path].Lookup(funcName)
@@ -61,3 +75,43 @@ func (p *PluginInterface) GetFunction(path string, funcName string) (plugin.Symb
 	}
 	return f, nil
 }
+
+// HealthCheck checks if the plugin loaded from path is still usable. A plugin
+// cannot be reloaded once opened, so it is unhealthy if the plugin file has been
+// removed or replaced since then, or if SchedulerType is no longer callable.
+func (p *PluginInterface) HealthCheck(path string) (err error) {
+	p.pluginMapLock.RLock()
+	_, loaded := p.pluginMap[path]
+	file, recorded := p.pluginFiles[path]
+	p.pluginMapLock.RUnlock()
+	if !loaded {
+		return errs.ErrCheckPlugin.FastGenByArgs(path, "it is not loaded")
+	}
+	filePath, err := filepath.Abs(path)
+	if err != nil {
+		return errs.ErrFilePathAbs.Wrap(err).FastGenWithCause()
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return errs.ErrCheckPlugin.FastGenByArgs(path, err.Error())
+	}
+	if recorded && (!info.ModTime().Equal(file.modTime) || info.Size() != file.size) {
+		return errs.ErrCheckPlugin.FastGenByArgs(path, "the plugin file has been changed since loaded")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errs.ErrCheckPlugin.FastGenByArgs(path, fmt.Sprintf("SchedulerType panics: %v", r))
+		}
+	}()
+	f, err := p.GetFunction(path, "SchedulerType")
+	if err != nil {
+		return err
+	}
+	schedulerType, ok := f.(func() string)
+	if !ok {
+		return errs.ErrCheckPlugin.FastGenByArgs(path, fmt.Sprintf("unexpected SchedulerType type %T", f))
+	}
+	schedulerType()
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/errs"
+)
+
+var _ = Suite(&testPluginInterfaceSuite{})
+
+type testPluginInterfaceSuite struct{}
+
+func (s *testPluginInterfaceSuite) TestHealthCheck(c *C) {
+	p := NewPluginInterface()
+	err := p.HealthCheck("./not-exist.so")
+	c.Assert(errs.ErrCheckPlugin.Equal(err), IsTrue)
+	_, err = p.GetFunction("./not-exist.so", "SchedulerType")
+	c.Assert(err, NotNil)
+	err = p.HealthCheck("./not-exist.so")
+	c.Assert(errs.ErrCheckPlugin.Equal(err), IsTrue)
+}
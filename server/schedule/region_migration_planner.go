@@ -0,0 +1,159 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/core/storelimit"
+	"github.com/tikv/pd/server/schedule/filter"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/schedule/opt"
+)
+
+const regionMigrationPlannerName = "region-migration-planner"
+
+// EvacuationPlan is the plan to move all regions off a store.
+type EvacuationPlan struct {
+	StoreID uint64
+	// Operators are sorted by the approximate size of the region, the smaller the earlier.
+	// Each region only moves the peer on the evacuated store, so no extra data is moved.
+	Operators []*operator.Operator
+	// TargetStores records how many peers each store will receive.
+	TargetStores map[uint64]int
+	// TotalSize is the approximate size in MB of data to be moved.
+	TotalSize int64
+	// EstimatedTime is estimated by the store limit of the stores involved.
+	EstimatedTime time.Duration
+	// FailedRegions records the regions which cannot be moved and the reason.
+	FailedRegions map[uint64]error
+}
+
+// RegionMigrationPlanner computes how to move regions off a store.
+// Unlike RegionScatterer, it picks destinations deterministically.
+type RegionMigrationPlanner struct {
+	name    string
+	cluster opt.Cluster
+}
+
+// NewRegionMigrationPlanner creates a region migration planner.
+func NewRegionMigrationPlanner(cluster opt.Cluster) *RegionMigrationPlanner {
+	return &RegionMigrationPlanner{
+		name:    regionMigrationPlannerName,
+		cluster: cluster,
+	}
+}
+
+// PlanStoreEvacuation computes the operators to move all regions off the given store.
+// The operators are only returned and never added to the operator controller.
+func (p *RegionMigrationPlanner) PlanStoreEvacuation(storeID uint64) (*EvacuationPlan, error) {
+	source := p.cluster.GetStore(storeID)
+	if source == nil {
+		return nil, errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	var regions []*core.RegionInfo
+	for _, region := range p.cluster.ScanRegions(nil, nil, -1) {
+		if region.GetStorePeer(storeID) != nil {
+			regions = append(regions, region)
+		}
+	}
+	sort.SliceStable(regions, func(i, j int) bool {
+		return regions[i].GetApproximateSize() < regions[j].GetApproximateSize()
+	})
+
+	plan := &EvacuationPlan{
+		StoreID:       storeID,
+		TargetStores:  make(map[uint64]int),
+		FailedRegions: make(map[uint64]error),
+	}
+	// plannedSize records the size in MB which is planned to move into each store.
+	plannedSize := make(map[uint64]int64)
+	engineFilter := filter.NewOrdinaryEngineFilter(p.name)
+	if engine := source.GetLabelValue(filter.EngineKey); engine != "" {
+		engineFilter = filter.NewEngineFilter(p.name, engine)
+	}
+	for _, region := range regions {
+		target := p.selectTarget(region, source, engineFilter, plannedSize)
+		if target == 0 {
+			plan.FailedRegions[region.GetID()] = errors.Errorf("no store to move region %d", region.GetID())
+			continue
+		}
+		oldPeer := region.GetStorePeer(storeID)
+		newPeer := &metapb.Peer{StoreId: target, Role: oldPeer.GetRole()}
+		op, err := operator.CreateMovePeerOperator("evacuate-store", p.cluster, region, operator.OpRegion, storeID, newPeer)
+		if err != nil {
+			plan.FailedRegions[region.GetID()] = err
+			continue
+		}
+		plan.Operators = append(plan.Operators, op)
+		plan.TargetStores[target]++
+		plannedSize[target] += region.GetApproximateSize()
+		plan.TotalSize += region.GetApproximateSize()
+	}
+	plan.EstimatedTime = p.estimateTime(storeID, plan)
+	return plan, nil
+}
+
+// selectTarget selects the store which still has the most available space after
+// receiving the planned regions.
+func (p *RegionMigrationPlanner) selectTarget(region *core.RegionInfo, source *core.StoreInfo, engineFilter filter.Filter, plannedSize map[uint64]int64) uint64 {
+	filters := []filter.Filter{
+		filter.NewExcludedFilter(p.name, nil, region.GetStoreIds()),
+		&filter.StoreStateFilter{ActionScope: p.name, MoveRegion: true, ScatterRegion: true},
+		engineFilter,
+		filter.NewPlacementSafeguard(p.name, p.cluster, region, source),
+	}
+	var (
+		target       uint64
+		maxAvailable int64
+	)
+	opts := p.cluster.GetOpts()
+	for _, store := range p.cluster.GetStores() {
+		if !filter.Target(opts, store, filters) {
+			continue
+		}
+		available := int64(store.GetAvailable()/(1<<20)) - plannedSize[store.GetID()] - region.GetApproximateSize()
+		if available <= 0 {
+			continue
+		}
+		if target == 0 || available > maxAvailable || (available == maxAvailable && store.GetID() < target) {
+			target, maxAvailable = store.GetID(), available
+		}
+	}
+	return target
+}
+
+// estimateTime estimates the duration to finish the plan according to the
+// remove-peer limit of the evacuated store and the add-peer limit of the targets.
+func (p *RegionMigrationPlanner) estimateTime(storeID uint64, plan *EvacuationPlan) time.Duration {
+	opts := p.cluster.GetOpts()
+	duration := func(count int, ratePerMin float64) time.Duration {
+		if count == 0 || ratePerMin <= 0 || ratePerMin >= storelimit.Unlimited {
+			return 0
+		}
+		return time.Duration(float64(count) * float64(time.Minute) / ratePerMin)
+	}
+	estimated := duration(len(plan.Operators), opts.GetStoreLimitByType(storeID, storelimit.RemovePeer))
+	for target, count := range plan.TargetStores {
+		if d := duration(count, opts.GetStoreLimitByType(target, storelimit.AddPeer)); d > estimated {
+			estimated = d
+		}
+	}
+	return estimated
+}
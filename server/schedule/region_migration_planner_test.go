@@ -0,0 +1,72 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/server/config"
+)
+
+var _ = Suite(&testRegionMigrationPlannerSuite{})
+
+type testRegionMigrationPlannerSuite struct{}
+
+func (s *testRegionMigrationPlannerSuite) TestPlanStoreEvacuation(c *C) {
+	opt := config.NewTestOptions()
+	opt.SetPlacementRuleEnabled(false)
+	tc := mockcluster.NewCluster(opt)
+	for i := uint64(1); i <= 5; i++ {
+		tc.AddRegionStore(i, 0)
+	}
+	for i := uint64(1); i <= 4; i++ {
+		tc.AddLeaderRegion(i, 1, 2, 3)
+	}
+	tc.AddLeaderRegion(5, 2, 3, 4)
+
+	planner := NewRegionMigrationPlanner(tc)
+	plan, err := planner.PlanStoreEvacuation(1)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Operators, HasLen, 4)
+	c.Assert(plan.FailedRegions, HasLen, 0)
+	for i, op := range plan.Operators {
+		c.Assert(op.RegionID(), Equals, uint64(i+1))
+	}
+	// the destinations are spread by the available space.
+	c.Assert(plan.TargetStores, DeepEquals, map[uint64]int{4: 2, 5: 2})
+	c.Assert(plan.TotalSize, Equals, 4*tc.GetRegion(1).GetApproximateSize())
+	// 4 peers are removed from store 1 with the limit of 60 per minute.
+	c.Assert(plan.EstimatedTime, Equals, 4*time.Second)
+
+	_, err = planner.PlanStoreEvacuation(10)
+	c.Assert(err, NotNil)
+}
+
+func (s *testRegionMigrationPlannerSuite) TestPlanStoreEvacuationNoTarget(c *C) {
+	opt := config.NewTestOptions()
+	opt.SetPlacementRuleEnabled(false)
+	tc := mockcluster.NewCluster(opt)
+	for i := uint64(1); i <= 3; i++ {
+		tc.AddRegionStore(i, 0)
+	}
+	tc.AddLeaderRegion(1, 1, 2, 3)
+
+	plan, err := NewRegionMigrationPlanner(tc).PlanStoreEvacuation(1)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Operators, HasLen, 0)
+	c.Assert(plan.FailedRegions, HasLen, 1)
+	c.Assert(plan.EstimatedTime, Equals, time.Duration(0))
+}
@@ -17,6 +17,8 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
@@ -93,6 +95,25 @@ func (s *selectedStores) getDistributionByGroupLocked(group string) (map[uint64]
 	return nil, false
 }
 
+// clone copies the distribution of all groups, the copy is collected by the gc of ctx.
+func (s *selectedStores) clone(ctx context.Context) *selectedStores {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	clone := newSelectedStores(ctx)
+	for _, group := range s.groupDistribution.GetAllID() {
+		distribution, ok := s.getDistributionByGroupLocked(group)
+		if !ok {
+			continue
+		}
+		copied := make(map[uint64]uint64, len(distribution))
+		for id, count := range distribution {
+			copied[id] = count
+		}
+		clone.groupDistribution.Put(group, copied)
+	}
+	return clone
+}
+
 func (s *selectedStores) totalCountByStore(storeID uint64) uint64 {
 	groups := s.groupDistribution.GetAllID()
 	totalCount := uint64(0)
@@ -117,15 +138,25 @@ type RegionScatterer struct {
 	cluster        opt.Cluster
 	ordinaryEngine engineContext
 	specialEngines map[string]engineContext
+	// r is used to break the tie between the stores with the same count.
+	// It is protected by rMu since the scatterer may be called concurrently.
+	rMu sync.Mutex
+	r   *rand.Rand
+	// dryRun is set for the scatterer which only plans the scatter, it neither
+	// reports the metrics nor marks the regions as suspect.
+	dryRun bool
 }
 
 // NewRegionScatterer creates a region scatterer.
 // RegionScatter is used for the `Lightning`, it will scatter the specified regions before import data.
-func NewRegionScatterer(ctx context.Context, cluster opt.Cluster) *RegionScatterer {
+// The seed is used to choose among the equally good stores, the same seed and the same
+// cluster produce the same scatter result, which makes it reproducible in tests.
+func NewRegionScatterer(ctx context.Context, cluster opt.Cluster, seed int64) *RegionScatterer {
 	return &RegionScatterer{
 		ctx:            ctx,
 		name:           regionScatterName,
 		cluster:        cluster,
+		r:              rand.New(rand.NewSource(seed)),
 		ordinaryEngine: newEngineContext(ctx, filter.NewOrdinaryEngineFilter(regionScatterName)),
 		specialEngines: make(map[string]engineContext),
 	}
@@ -137,6 +168,14 @@ type engineContext struct {
 	selectedLeader *selectedStores
 }
 
+func (c engineContext) clone(ctx context.Context) engineContext {
+	return engineContext{
+		filters:        c.filters,
+		selectedPeer:   c.selectedPeer.clone(ctx),
+		selectedLeader: c.selectedLeader.clone(ctx),
+	}
+}
+
 func newEngineContext(ctx context.Context, filters ...filter.Filter) engineContext {
 	filters = append(filters, &filter.StoreStateFilter{ActionScope: regionScatterName, MoveRegion: true, ScatterRegion: true})
 	return engineContext{
@@ -243,24 +282,84 @@ func (r *RegionScatterer) ScatterRegions(regions map[uint64]*core.RegionInfo, fa
 	return ops, nil
 }
 
+// ScatterRegionsDryRun plans the scatter of the regions in the same way as ScatterRegions,
+// but the distribution recorded by the scatterer is left untouched, so the returned
+// operators are only for inspection and should not be added to the operator controller.
+// The regions are not retried, the ones which fail or need no change have no operator.
+func (r *RegionScatterer) ScatterRegionsDryRun(regions map[uint64]*core.RegionInfo, group string) (map[uint64]*operator.Operator, error) {
+	if len(regions) < 1 {
+		return nil, errors.New("empty region")
+	}
+	ctx, cancel := context.WithCancel(r.ctx)
+	defer cancel()
+	planner := r.dryRunClone(ctx)
+
+	ids := make([]uint64, 0, len(regions))
+	for id := range regions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	ops := make(map[uint64]*operator.Operator, len(regions))
+	for _, id := range ids {
+		op, err := planner.Scatter(regions[id], group)
+		if err != nil {
+			log.Debug("region cannot be scattered in dry run", zap.Uint64("region-id", id), errs.ZapError(err))
+			continue
+		}
+		if op != nil {
+			ops[id] = op
+		}
+	}
+	return ops, nil
+}
+
+// dryRunClone returns a scatterer starting with the same distribution as r, the
+// scatter on it does not affect r.
+func (r *RegionScatterer) dryRunClone(ctx context.Context) *RegionScatterer {
+	r.rMu.Lock()
+	seed := r.r.Int63()
+	r.rMu.Unlock()
+	clone := &RegionScatterer{
+		ctx:            ctx,
+		name:           r.name,
+		cluster:        r.cluster,
+		r:              rand.New(rand.NewSource(seed)),
+		ordinaryEngine: r.ordinaryEngine.clone(ctx),
+		specialEngines: make(map[string]engineContext, len(r.specialEngines)),
+		dryRun:         true,
+	}
+	for engine, context := range r.specialEngines {
+		clone.specialEngines[engine] = context.clone(ctx)
+	}
+	return clone
+}
+
+func (r *RegionScatterer) incScatterCounter(event, reason string) {
+	if !r.dryRun {
+		scatterCounter.WithLabelValues(event, reason).Inc()
+	}
+}
+
 // Scatter relocates the region. If the group is defined, the regions' leader with the same group would be scattered
 // in a group level instead of cluster level.
 func (r *RegionScatterer) Scatter(region *core.RegionInfo, group string) (*operator.Operator, error) {
 	if !opt.IsRegionReplicated(r.cluster, region) {
-		r.cluster.AddSuspectRegions(region.GetID())
-		scatterCounter.WithLabelValues("skip", "not-replicated").Inc()
+		if !r.dryRun {
+			r.cluster.AddSuspectRegions(region.GetID())
+		}
+		r.incScatterCounter("skip", "not-replicated")
 		log.Warn("region not replicated during scatter", zap.Uint64("region-id", region.GetID()))
 		return nil, errors.Errorf("region %d is not fully replicated", region.GetID())
 	}
 
 	if region.GetLeader() == nil {
-		scatterCounter.WithLabelValues("skip", "no-leader").Inc()
+		r.incScatterCounter("skip", "no-leader")
 		log.Warn("region no leader during scatter", zap.Uint64("region-id", region.GetID()))
 		return nil, errors.Errorf("region %d has no leader", region.GetID())
 	}
 
 	if r.cluster.IsRegionHot(region) {
-		scatterCounter.WithLabelValues("skip", "hot").Inc()
+		r.incScatterCounter("skip", "hot")
 		log.Warn("region too hot during scatter", zap.Uint64("region-id", region.GetID()))
 		return nil, errors.Errorf("region %d is hot", region.GetID())
 	}
@@ -289,7 +388,7 @@ func (r *RegionScatterer) scatterRegion(region *core.RegionInfo, group string) *
 	targetPeers := make(map[uint64]*metapb.Peer)
 	selectedStores := make(map[uint64]struct{})
 	scatterWithSameEngine := func(peers map[uint64]*metapb.Peer, context engineContext) {
-		for _, peer := range peers {
+		for _, peer := range sortedPeers(peers) {
 			candidates := r.selectCandidates(region, peer.GetStoreId(), selectedStores, context)
 			newPeer := r.selectStore(group, peer, peer.GetStoreId(), candidates, context)
 			targetPeers[newPeer.GetStoreId()] = newPeer
@@ -314,7 +413,7 @@ func (r *RegionScatterer) scatterRegion(region *core.RegionInfo, group string) *
 
 	op, err := operator.CreateScatterRegionOperator("scatter-region", r.cluster, region, targetPeers, targetLeader)
 	if err != nil {
-		scatterCounter.WithLabelValues("fail", "").Inc()
+		r.incScatterCounter("fail", "")
 		for _, peer := range region.GetPeers() {
 			targetPeers[peer.GetStoreId()] = peer
 		}
@@ -323,7 +422,7 @@ func (r *RegionScatterer) scatterRegion(region *core.RegionInfo, group string) *
 		return nil
 	}
 	if op != nil {
-		scatterCounter.WithLabelValues("success", "").Inc()
+		r.incScatterCounter("success", "")
 		r.Put(targetPeers, targetLeader, group)
 		op.SetPriorityLevel(core.HighPriority)
 	}
@@ -343,10 +442,13 @@ func (r *RegionScatterer) selectCandidates(region *core.RegionInfo, sourceStoreI
 	filters = append(filters, context.filters...)
 	filters = append(filters, scoreGuard)
 	stores := r.cluster.GetStores()
+	if label := r.cluster.GetOpts().GetScatterPartitionLabel(); label != "" {
+		stores = r.selectPartitionStores(stores, label, sourceStore.GetLabelValue(label), filters)
+	}
 	candidates := make([]uint64, 0)
 	maxStoreTotalCount := uint64(0)
 	minStoreTotalCount := uint64(math.MaxUint64)
-	for _, store := range r.cluster.GetStores() {
+	for _, store := range stores {
 		count := context.selectedPeer.totalCountByStore(store.GetID())
 		if count > maxStoreTotalCount {
 			maxStoreTotalCount = count
@@ -366,9 +468,38 @@ func (r *RegionScatterer) selectCandidates(region *core.RegionInfo, sourceStoreI
 			}
 		}
 	}
+	r.shuffle(candidates)
 	return candidates
 }
 
+// selectPartitionStores returns the stores in the same partition as the source
+// store. If none of them can accept the peer, which means the placement rules
+// or the lack of stores force the peer to leave the partition, all stores are
+// returned.
+func (r *RegionScatterer) selectPartitionStores(stores []*core.StoreInfo, label, partition string, filters []filter.Filter) []*core.StoreInfo {
+	if partition == "" {
+		return stores
+	}
+	var (
+		partitionStores []*core.StoreInfo
+		hasTarget       bool
+	)
+	for _, store := range stores {
+		if store.GetLabelValue(label) != partition {
+			continue
+		}
+		partitionStores = append(partitionStores, store)
+		if !hasTarget && filter.Target(r.cluster.GetOpts(), store, filters) {
+			hasTarget = true
+		}
+	}
+	if !hasTarget {
+		r.incScatterCounter("cross-partition", "")
+		return stores
+	}
+	return partitionStores
+}
+
 func (r *RegionScatterer) selectStore(group string, peer *metapb.Peer, sourceStoreID uint64, candidates []uint64, context engineContext) *metapb.Peer {
 	if len(candidates) < 1 {
 		return peer
@@ -408,6 +539,7 @@ func (r *RegionScatterer) selectAvailableLeaderStores(group string, peers map[ui
 			leaderCandidateStores = append(leaderCandidateStores, storeID)
 		}
 	}
+	r.shuffle(leaderCandidateStores)
 	minStoreGroupLeader := uint64(math.MaxUint64)
 	id := uint64(0)
 	for _, storeID := range leaderCandidateStores {
@@ -420,6 +552,27 @@ func (r *RegionScatterer) selectAvailableLeaderStores(group string, peers map[ui
 	return id
 }
 
+// shuffle sorts the store IDs and then shuffles them with the seeded source, so
+// the order only depends on the seed rather than the iteration order of maps.
+func (r *RegionScatterer) shuffle(storeIDs []uint64) {
+	sort.Slice(storeIDs, func(i, j int) bool { return storeIDs[i] < storeIDs[j] })
+	r.rMu.Lock()
+	defer r.rMu.Unlock()
+	r.r.Shuffle(len(storeIDs), func(i, j int) {
+		storeIDs[i], storeIDs[j] = storeIDs[j], storeIDs[i]
+	})
+}
+
+// sortedPeers returns the peers sorted by the peer ID.
+func sortedPeers(peers map[uint64]*metapb.Peer) []*metapb.Peer {
+	res := make([]*metapb.Peer, 0, len(peers))
+	for _, peer := range peers {
+		res = append(res, peer)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].GetId() < res[j].GetId() })
+	return res
+}
+
 // Put put the final distribution in the context no matter the operator was created
 func (r *RegionScatterer) Put(peers map[uint64]*metapb.Peer, leaderStoreID uint64, group string) {
 	ordinaryFilter := filter.NewOrdinaryEngineFilter(r.name)
@@ -429,22 +582,22 @@ func (r *RegionScatterer) Put(peers map[uint64]*metapb.Peer, leaderStoreID uint6
 		store := r.cluster.GetStore(storeID)
 		if ordinaryFilter.Target(r.cluster.GetOpts(), store) {
 			r.ordinaryEngine.selectedPeer.Put(storeID, group)
-			scatterDistributionCounter.WithLabelValues(
-				fmt.Sprintf("%v", storeID),
-				fmt.Sprintf("%v", false),
-				filter.EngineTiKV).Inc()
+			r.incDistributionCounter(storeID, false, filter.EngineTiKV)
 		} else {
 			engine := store.GetLabelValue(filter.EngineKey)
 			r.specialEngines[engine].selectedPeer.Put(storeID, group)
-			scatterDistributionCounter.WithLabelValues(
-				fmt.Sprintf("%v", storeID),
-				fmt.Sprintf("%v", false),
-				engine).Inc()
+			r.incDistributionCounter(storeID, false, engine)
 		}
 	}
 	r.ordinaryEngine.selectedLeader.Put(leaderStoreID, group)
-	scatterDistributionCounter.WithLabelValues(
-		fmt.Sprintf("%v", leaderStoreID),
-		fmt.Sprintf("%v", true),
-		filter.EngineTiKV).Inc()
+	r.incDistributionCounter(leaderStoreID, true, filter.EngineTiKV)
+}
+
+func (r *RegionScatterer) incDistributionCounter(storeID uint64, isLeader bool, engine string) {
+	if !r.dryRun {
+		scatterDistributionCounter.WithLabelValues(
+			fmt.Sprintf("%v", storeID),
+			fmt.Sprintf("%v", isLeader),
+			engine).Inc()
+	}
 }
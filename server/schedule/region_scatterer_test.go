@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"sort"
 	"time"
 
 	. "github.com/pingcap/check"
@@ -103,7 +104,7 @@ func (s *testScatterRegionSuite) scatter(c *C, numStores, numRegions uint64, use
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	scatterer := NewRegionScatterer(ctx, tc)
+	scatterer := NewRegionScatterer(ctx, tc, 1)
 
 	for i := uint64(1); i <= numRegions; i++ {
 		region := tc.GetRegion(i)
@@ -172,7 +173,7 @@ func (s *testScatterRegionSuite) scatterSpecial(c *C, numOrdinaryStores, numSpec
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	scatterer := NewRegionScatterer(ctx, tc)
+	scatterer := NewRegionScatterer(ctx, tc, 1)
 
 	for i := uint64(1); i <= numRegions; i++ {
 		region := tc.GetRegion(i)
@@ -217,6 +218,43 @@ func (s *testScatterRegionSuite) scatterSpecial(c *C, numOrdinaryStores, numSpec
 	}
 }
 
+func (s *testScatterRegionSuite) TestScatterPartition(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	tc.DisableFeature(versioninfo.JointConsensus)
+	tc.SetScatterPartitionLabel("partition")
+	// stores 1-4 are in partition p1 and stores 5-8 are in partition p2.
+	partition := func(storeID uint64) string {
+		return fmt.Sprintf("p%d", (storeID-1)/4+1)
+	}
+	for i := uint64(1); i <= 8; i++ {
+		tc.AddLabelsStore(i, 0, map[string]string{"partition": partition(i)})
+	}
+	for i := uint64(1); i <= 40; i++ {
+		tc.AddLeaderRegion(i, 1, 2, 5)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scatterer := NewRegionScatterer(ctx, tc, 1)
+	countPeers := make(map[uint64]uint64)
+	for i := uint64(1); i <= 40; i++ {
+		if op, _ := scatterer.Scatter(tc.GetRegion(i), ""); op != nil {
+			ApplyOperator(tc, op)
+		}
+		region := tc.GetRegion(i)
+		var partitions []string
+		for _, peer := range region.GetPeers() {
+			countPeers[peer.GetStoreId()]++
+			partitions = append(partitions, partition(peer.GetStoreId()))
+		}
+		sort.Strings(partitions)
+		c.Assert(partitions, DeepEquals, []string{"p1", "p1", "p2"})
+	}
+	// the replicas are still scattered within the partitions.
+	c.Assert(countPeers, HasLen, 8)
+}
+
 func (s *testScatterRegionSuite) TestStoreLimit(c *C) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -238,7 +276,7 @@ func (s *testScatterRegionSuite) TestStoreLimit(c *C) {
 		tc.AddLeaderRegion(i, seq.next(), seq.next(), seq.next())
 	}
 
-	scatterer := NewRegionScatterer(ctx, tc)
+	scatterer := NewRegionScatterer(ctx, tc, 1)
 
 	for i := uint64(1); i <= 5; i++ {
 		region := tc.GetRegion(i)
@@ -279,7 +317,7 @@ func (s *testScatterRegionSuite) TestScatterCheck(c *C) {
 	for _, testcase := range testcases {
 		c.Logf(testcase.name)
 		ctx, cancel := context.WithCancel(context.Background())
-		scatterer := NewRegionScatterer(ctx, tc)
+		scatterer := NewRegionScatterer(ctx, tc, 1)
 		_, err := scatterer.Scatter(testcase.checkRegion, "")
 		if testcase.needFix {
 			c.Assert(err, NotNil)
@@ -323,7 +361,7 @@ func (s *testScatterRegionSuite) TestScatterGroupInConcurrency(c *C) {
 	for _, testcase := range testcases {
 		c.Logf(testcase.name)
 		ctx, cancel := context.WithCancel(context.Background())
-		scatterer := NewRegionScatterer(ctx, tc)
+		scatterer := NewRegionScatterer(ctx, tc, 1)
 		regionID := 1
 		for i := 0; i < 100; i++ {
 			for j := 0; j < testcase.groupCount; j++ {
@@ -383,7 +421,7 @@ func (s *testScatterRegionSuite) TestScattersGroup(c *C) {
 	group := "group"
 	for _, testcase := range testcases {
 		ctx, cancel := context.WithCancel(context.Background())
-		scatterer := NewRegionScatterer(ctx, tc)
+		scatterer := NewRegionScatterer(ctx, tc, 1)
 		regions := map[uint64]*core.RegionInfo{}
 		for i := 1; i <= 100; i++ {
 			regions[uint64(i)] = tc.AddLeaderRegion(uint64(i), 1, 2, 3)
@@ -454,7 +492,7 @@ func (s *testScatterRegionSuite) TestRegionFromDifferentGroups(c *C) {
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	scatterer := NewRegionScatterer(ctx, tc)
+	scatterer := NewRegionScatterer(ctx, tc, 1)
 	regionCount := 50
 	for i := 1; i <= regionCount; i++ {
 		p := rand.Perm(storeCount)
@@ -476,3 +514,59 @@ func (s *testScatterRegionSuite) TestRegionFromDifferentGroups(c *C) {
 	}
 	check(scatterer.ordinaryEngine.selectedPeer)
 }
+
+func (s *testScatterRegionSuite) TestScatterWithSeed(c *C) {
+	scatter := func(seed int64) []string {
+		opt := config.NewTestOptions()
+		tc := mockcluster.NewCluster(opt)
+		for i := uint64(1); i <= 6; i++ {
+			tc.AddRegionStore(i, 0)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		scatterer := NewRegionScatterer(ctx, tc, seed)
+		var steps []string
+		for i := uint64(1); i <= 20; i++ {
+			op := scatterer.scatterRegion(tc.AddLeaderRegion(i, 1, 2, 3), "")
+			if op == nil {
+				steps = append(steps, "")
+				continue
+			}
+			for j := 0; j < op.Len(); j++ {
+				steps = append(steps, op.Step(j).String())
+			}
+		}
+		return steps
+	}
+	// The same seed gives the same result.
+	c.Assert(scatter(1), DeepEquals, scatter(1))
+	c.Assert(scatter(42), DeepEquals, scatter(42))
+}
+
+func (s *testScatterRegionSuite) TestScatterDryRun(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	for i := uint64(1); i <= 6; i++ {
+		tc.AddRegionStore(i, 0)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scatterer := NewRegionScatterer(ctx, tc, 1)
+	regions := make(map[uint64]*core.RegionInfo)
+	for i := uint64(1); i <= 10; i++ {
+		regions[i] = tc.AddLeaderRegion(i, 1, 2, 3)
+	}
+	ops, err := scatterer.ScatterRegionsDryRun(regions, "group")
+	c.Assert(err, IsNil)
+	c.Assert(len(ops), Greater, 0)
+	for id, op := range ops {
+		c.Assert(op.RegionID(), Equals, id)
+	}
+	// The distribution of the scatterer is untouched.
+	for i := uint64(1); i <= 6; i++ {
+		c.Assert(scatterer.ordinaryEngine.selectedPeer.totalCountByStore(i), Equals, uint64(0))
+		c.Assert(scatterer.ordinaryEngine.selectedLeader.totalCountByStore(i), Equals, uint64(0))
+	}
+	_, err = scatterer.ScatterRegionsDryRun(nil, "group")
+	c.Assert(err, NotNil)
+}
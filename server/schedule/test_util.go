@@ -53,6 +53,16 @@ func ApplyOperatorStep(region *core.RegionInfo, op *operator.Operator) *core.Reg
 				panic("Cannot remove the leader peer")
 			}
 			region = region.Clone(core.WithRemoveStorePeer(s.FromStore))
+		case operator.BatchRemovePeer:
+			for _, storeID := range s.FromStores {
+				if region.GetStorePeer(storeID) == nil {
+					panic("Remove peer that doesn't exist")
+				}
+				if region.GetLeader().GetStoreId() == storeID {
+					panic("Cannot remove the leader peer")
+				}
+				region = region.Clone(core.WithRemoveStorePeer(storeID))
+			}
 		case operator.AddLearner:
 			if region.GetStorePeer(s.ToStore) != nil {
 				panic("Add learner that exists")
@@ -27,6 +27,7 @@ var PriorityWeight = []float64{1.0, 4.0, 9.0}
 type WaitingOperator interface {
 	PutOperator(op *operator.Operator)
 	GetOperator() []*operator.Operator
+	AppendOperator(ops []*operator.Operator) []*operator.Operator
 	ListOperator() []*operator.Operator
 }
 
@@ -77,8 +78,14 @@ func (b *RandBuckets) ListOperator() []*operator.Operator {
 
 // GetOperator gets an operator from the random buckets.
 func (b *RandBuckets) GetOperator() []*operator.Operator {
+	return b.AppendOperator(nil)
+}
+
+// AppendOperator gets an operator from the random buckets like GetOperator,
+// and appends it to ops, so that the caller can reuse the slice.
+func (b *RandBuckets) AppendOperator(ops []*operator.Operator) []*operator.Operator {
 	if b.totalWeight == 0 {
-		return nil
+		return ops
 	}
 	r := rand.Float64()
 	var sum float64
@@ -89,11 +96,10 @@ func (b *RandBuckets) GetOperator() []*operator.Operator {
 		}
 		proportion := bucket.weight / b.totalWeight
 		if r >= sum && r < sum+proportion {
-			var res []*operator.Operator
-			res = append(res, bucket.ops[0])
+			ops = append(ops, bucket.ops[0])
 			// Merge operation has two operators, and thus it should be handled specifically.
 			if bucket.ops[0].Kind()&operator.OpMerge != 0 {
-				res = append(res, bucket.ops[1])
+				ops = append(ops, bucket.ops[1])
 				bucket.ops = bucket.ops[2:]
 			} else {
 				bucket.ops = bucket.ops[1:]
@@ -101,11 +107,11 @@ func (b *RandBuckets) GetOperator() []*operator.Operator {
 			if len(bucket.ops) == 0 {
 				b.totalWeight -= bucket.weight
 			}
-			return res
+			return ops
 		}
 		sum += proportion
 	}
-	return nil
+	return ops
 }
 
 // WaitingOperatorStatus is used to limit the count of each kind of operators.
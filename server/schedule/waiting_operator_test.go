@@ -34,6 +34,18 @@ func (s *testWaitingOperatorSuite) TestRandBuckets(c *C) {
 	c.Assert(rb.GetOperator(), IsNil)
 }
 
+func (s *testWaitingOperatorSuite) TestAppendOperator(c *C) {
+	rb := NewRandBuckets()
+	addOperators(rb)
+	buf := make([]*operator.Operator, 0, 2)
+	for i := 0; i < 3; i++ {
+		ops := rb.AppendOperator(buf[:0])
+		c.Assert(ops, HasLen, 1)
+		c.Assert(&ops[0], Equals, &buf[:1][0])
+	}
+	c.Assert(rb.AppendOperator(buf[:0]), HasLen, 0)
+}
+
 func addOperators(wop WaitingOperator) {
 	op := operator.NewOperator("testOperatorNormal", "test", uint64(1), &metapb.RegionEpoch{}, operator.OpRegion, []operator.OpStep{
 		operator.RemovePeer{FromStore: uint64(1)},
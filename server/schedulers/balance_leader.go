@@ -14,8 +14,10 @@
 package schedulers
 
 import (
+	"net/http"
 	"sort"
 	"strconv"
+	"sync"
 
 	"github.com/pingcap/log"
 	"github.com/prometheus/client_golang/prometheus"
@@ -55,7 +57,7 @@ func init() {
 	})
 
 	schedule.RegisterScheduler(BalanceLeaderType, func(opController *schedule.OperatorController, storage *core.Storage, decoder schedule.ConfigDecoder) (schedule.Scheduler, error) {
-		conf := &balanceLeaderSchedulerConfig{}
+		conf := &balanceLeaderSchedulerConfig{storage: storage}
 		if err := decoder(conf); err != nil {
 			return nil, err
 		}
@@ -64,8 +66,46 @@ func init() {
 }
 
 type balanceLeaderSchedulerConfig struct {
+	mu      sync.RWMutex
+	storage *core.Storage
+
 	Name   string          `json:"name"`
 	Ranges []core.KeyRange `json:"ranges"`
+	// BalanceTriggerThresholdRatio skips scheduling when the imbalance ratio of
+	// the leader scores is less than it. 0 means always schedule.
+	BalanceTriggerThresholdRatio float64 `json:"balance-trigger-threshold-ratio,omitempty"`
+}
+
+func (conf *balanceLeaderSchedulerConfig) getTriggerThresholdRatio() float64 {
+	conf.mu.RLock()
+	defer conf.mu.RUnlock()
+	return conf.BalanceTriggerThresholdRatio
+}
+
+func (conf *balanceLeaderSchedulerConfig) setTriggerThresholdRatio(ratio float64) error {
+	conf.mu.Lock()
+	defer conf.mu.Unlock()
+	old := conf.BalanceTriggerThresholdRatio
+	conf.BalanceTriggerThresholdRatio = ratio
+	if err := conf.persist(); err != nil {
+		conf.BalanceTriggerThresholdRatio = old // revert
+		return err
+	}
+	return nil
+}
+
+func (conf *balanceLeaderSchedulerConfig) encodeConfig() ([]byte, error) {
+	conf.mu.RLock()
+	defer conf.mu.RUnlock()
+	return schedule.EncodeConfig(conf)
+}
+
+func (conf *balanceLeaderSchedulerConfig) persist() error {
+	data, err := schedule.EncodeConfig(conf)
+	if err != nil {
+		return err
+	}
+	return conf.storage.SaveScheduleConfig(conf.Name, data)
 }
 
 type balanceLeaderScheduler struct {
@@ -74,6 +114,7 @@ type balanceLeaderScheduler struct {
 	opController *schedule.OperatorController
 	filters      []filter.Filter
 	counter      *prometheus.CounterVec
+	handler      http.Handler
 }
 
 // newBalanceLeaderScheduler creates a scheduler that tends to keep leaders on
@@ -86,6 +127,7 @@ func newBalanceLeaderScheduler(opController *schedule.OperatorController, conf *
 		conf:          conf,
 		opController:  opController,
 		counter:       balanceLeaderCounter,
+		handler:       newBalanceTriggerHandler(conf.getTriggerThresholdRatio, conf.setTriggerThresholdRatio),
 	}
 	for _, option := range options {
 		option(s)
@@ -123,15 +165,30 @@ func (l *balanceLeaderScheduler) GetType() string {
 }
 
 func (l *balanceLeaderScheduler) EncodeConfig() ([]byte, error) {
-	return schedule.EncodeConfig(l.conf)
+	return l.conf.encodeConfig()
+}
+
+func (l *balanceLeaderScheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.handler.ServeHTTP(w, r)
 }
 
 func (l *balanceLeaderScheduler) IsScheduleAllowed(cluster opt.Cluster) bool {
 	allowed := l.opController.OperatorCount(operator.OpLeader) < cluster.GetOpts().GetLeaderScheduleLimit()
 	if !allowed {
 		operator.OperatorLimitCounter.WithLabelValues(l.GetType(), operator.OpLeader.String()).Inc()
+		return false
+	}
+	if ratio := l.conf.getTriggerThresholdRatio(); ratio > 0 {
+		policy, opts := l.opController.GetLeaderSchedulePolicy(), cluster.GetOpts()
+		imbalance := getImbalanceRatio(cluster, l.filters, func(store *core.StoreInfo) float64 {
+			return normalizedLeaderScore(opts, store, policy, 0)
+		})
+		if imbalance < ratio {
+			schedulerCounter.WithLabelValues(l.GetName(), "below-trigger-threshold").Inc()
+			return false
+		}
 	}
-	return allowed
+	return true
 }
 
 func (l *balanceLeaderScheduler) Schedule(cluster opt.Cluster) []*operator.Operator {
@@ -143,17 +200,18 @@ func (l *balanceLeaderScheduler) Schedule(cluster opt.Cluster) []*operator.Opera
 	targets := filter.SelectTargetStores(stores, l.filters, cluster.GetOpts())
 	opInfluence := l.opController.GetOpInfluence(cluster)
 	kind := core.NewScheduleKind(core.LeaderKind, leaderSchedulePolicy)
+	opts := cluster.GetOpts()
 	sort.Slice(sources, func(i, j int) bool {
 		iOp := opInfluence.GetStoreInfluence(sources[i].GetID()).ResourceProperty(kind)
 		jOp := opInfluence.GetStoreInfluence(sources[j].GetID()).ResourceProperty(kind)
-		return sources[i].LeaderScore(leaderSchedulePolicy, iOp) >
-			sources[j].LeaderScore(leaderSchedulePolicy, jOp)
+		return normalizedLeaderScore(opts, sources[i], leaderSchedulePolicy, iOp) >
+			normalizedLeaderScore(opts, sources[j], leaderSchedulePolicy, jOp)
 	})
 	sort.Slice(targets, func(i, j int) bool {
 		iOp := opInfluence.GetStoreInfluence(targets[i].GetID()).ResourceProperty(kind)
 		jOp := opInfluence.GetStoreInfluence(targets[j].GetID()).ResourceProperty(kind)
-		return targets[i].LeaderScore(leaderSchedulePolicy, iOp) <
-			targets[j].LeaderScore(leaderSchedulePolicy, jOp)
+		return normalizedLeaderScore(opts, targets[i], leaderSchedulePolicy, iOp) <
+			normalizedLeaderScore(opts, targets[j], leaderSchedulePolicy, jOp)
 	})
 
 	for i := 0; i < len(sources) || i < len(targets); i++ {
@@ -208,11 +266,12 @@ func (l *balanceLeaderScheduler) transferLeaderOut(cluster opt.Cluster, source *
 	}
 	targets = filter.SelectTargetStores(targets, finalFilters, cluster.GetOpts())
 	leaderSchedulePolicy := l.opController.GetLeaderSchedulePolicy()
+	opts := cluster.GetOpts()
 	sort.Slice(targets, func(i, j int) bool {
 		kind := core.NewScheduleKind(core.LeaderKind, leaderSchedulePolicy)
 		iOp := opInfluence.GetStoreInfluence(targets[i].GetID()).ResourceProperty(kind)
 		jOp := opInfluence.GetStoreInfluence(targets[j].GetID()).ResourceProperty(kind)
-		return targets[i].LeaderScore(leaderSchedulePolicy, iOp) < targets[j].LeaderScore(leaderSchedulePolicy, jOp)
+		return normalizedLeaderScore(opts, targets[i], leaderSchedulePolicy, iOp) < normalizedLeaderScore(opts, targets[j], leaderSchedulePolicy, jOp)
 	})
 	for _, target := range targets {
 		if op := l.createOperator(cluster, region, source, target); len(op) > 0 {
@@ -278,7 +337,7 @@ func (l *balanceLeaderScheduler) createOperator(cluster opt.Cluster, region *cor
 
 	opInfluence := l.opController.GetOpInfluence(cluster)
 	kind := core.NewScheduleKind(core.LeaderKind, cluster.GetOpts().GetLeaderSchedulePolicy())
-	shouldBalance, sourceScore, targetScore := shouldBalance(cluster, source, target, region, kind, opInfluence, l.GetName())
+	shouldBalance, sourceScore, targetScore := shouldBalance(cluster, source, target, region, kind, opInfluence, l.GetName(), nil)
 	if !shouldBalance {
 		schedulerCounter.WithLabelValues(l.GetName(), "skip").Inc()
 		return nil
@@ -14,8 +14,10 @@
 package schedulers
 
 import (
+	"net/http"
 	"sort"
 	"strconv"
+	"sync"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/log"
@@ -46,7 +48,7 @@ func init() {
 		}
 	})
 	schedule.RegisterScheduler(BalanceRegionType, func(opController *schedule.OperatorController, storage *core.Storage, decoder schedule.ConfigDecoder) (schedule.Scheduler, error) {
-		conf := &balanceRegionSchedulerConfig{}
+		conf := &balanceRegionSchedulerConfig{storage: storage}
 		if err := decoder(conf); err != nil {
 			return nil, err
 		}
@@ -64,8 +66,46 @@ const (
 )
 
 type balanceRegionSchedulerConfig struct {
+	mu      sync.RWMutex
+	storage *core.Storage
+
 	Name   string          `json:"name"`
 	Ranges []core.KeyRange `json:"ranges"`
+	// BalanceTriggerThresholdRatio skips scheduling when the imbalance ratio of
+	// the region scores is less than it. 0 means always schedule.
+	BalanceTriggerThresholdRatio float64 `json:"balance-trigger-threshold-ratio,omitempty"`
+}
+
+func (conf *balanceRegionSchedulerConfig) getTriggerThresholdRatio() float64 {
+	conf.mu.RLock()
+	defer conf.mu.RUnlock()
+	return conf.BalanceTriggerThresholdRatio
+}
+
+func (conf *balanceRegionSchedulerConfig) setTriggerThresholdRatio(ratio float64) error {
+	conf.mu.Lock()
+	defer conf.mu.Unlock()
+	old := conf.BalanceTriggerThresholdRatio
+	conf.BalanceTriggerThresholdRatio = ratio
+	if err := conf.persist(); err != nil {
+		conf.BalanceTriggerThresholdRatio = old // revert
+		return err
+	}
+	return nil
+}
+
+func (conf *balanceRegionSchedulerConfig) encodeConfig() ([]byte, error) {
+	conf.mu.RLock()
+	defer conf.mu.RUnlock()
+	return schedule.EncodeConfig(conf)
+}
+
+func (conf *balanceRegionSchedulerConfig) persist() error {
+	data, err := schedule.EncodeConfig(conf)
+	if err != nil {
+		return err
+	}
+	return conf.storage.SaveScheduleConfig(conf.Name, data)
 }
 
 type balanceRegionScheduler struct {
@@ -74,6 +114,10 @@ type balanceRegionScheduler struct {
 	opController *schedule.OperatorController
 	filters      []filter.Filter
 	counter      *prometheus.CounterVec
+	handler      http.Handler
+	// tolerantTuner estimates the tolerant size ratio when it is not set in
+	// the config.
+	tolerantTuner *tolerantRatioTuner
 }
 
 // newBalanceRegionScheduler creates a scheduler that tends to keep regions on
@@ -85,6 +129,8 @@ func newBalanceRegionScheduler(opController *schedule.OperatorController, conf *
 		conf:          conf,
 		opController:  opController,
 		counter:       balanceRegionCounter,
+		tolerantTuner: newTolerantRatioTuner(),
+		handler:       newBalanceTriggerHandler(conf.getTriggerThresholdRatio, conf.setTriggerThresholdRatio),
 	}
 	for _, setOption := range opts {
 		setOption(scheduler)
@@ -122,29 +168,48 @@ func (s *balanceRegionScheduler) GetType() string {
 }
 
 func (s *balanceRegionScheduler) EncodeConfig() ([]byte, error) {
-	return schedule.EncodeConfig(s.conf)
+	return s.conf.encodeConfig()
+}
+
+func (s *balanceRegionScheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
 }
 
 func (s *balanceRegionScheduler) IsScheduleAllowed(cluster opt.Cluster) bool {
 	allowed := s.opController.OperatorCount(operator.OpRegion)-s.opController.OperatorCount(operator.OpMerge) < cluster.GetOpts().GetRegionScheduleLimit()
 	if !allowed {
 		operator.OperatorLimitCounter.WithLabelValues(s.GetType(), operator.OpRegion.String()).Inc()
+		return false
+	}
+	if ratio := s.conf.getTriggerThresholdRatio(); ratio > 0 {
+		opts := cluster.GetOpts()
+		imbalance := getImbalanceRatio(cluster, s.filters, func(store *core.StoreInfo) float64 {
+			return normalizedRegionScore(opts, store, 0, 0)
+		})
+		if imbalance < ratio {
+			schedulerCounter.WithLabelValues(s.GetName(), "below-trigger-threshold").Inc()
+			return false
+		}
 	}
-	return allowed
+	return true
 }
 
 func (s *balanceRegionScheduler) Schedule(cluster opt.Cluster) []*operator.Operator {
 	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
 	stores := cluster.GetStores()
 	opts := cluster.GetOpts()
+	if opts.GetTolerantSizeRatio() == 0 {
+		s.tolerantTuner.observe(cluster, s.filters, func(store *core.StoreInfo) float64 {
+			return normalizedRegionScore(opts, store, 0, 0)
+		})
+	}
 	stores = filter.SelectSourceStores(stores, s.filters, opts)
 	opInfluence := s.opController.GetOpInfluence(cluster)
 	kind := core.NewScheduleKind(core.RegionKind, core.BySize)
 	sort.Slice(stores, func(i, j int) bool {
 		iOp := opInfluence.GetStoreInfluence(stores[i].GetID()).ResourceProperty(kind)
 		jOp := opInfluence.GetStoreInfluence(stores[j].GetID()).ResourceProperty(kind)
-		return stores[i].RegionScore(opts.GetRegionScoreFormulaVersion(), opts.GetHighSpaceRatio(), opts.GetLowSpaceRatio(), iOp, -1) >
-			stores[j].RegionScore(opts.GetRegionScoreFormulaVersion(), opts.GetHighSpaceRatio(), opts.GetLowSpaceRatio(), jOp, -1)
+		return normalizedRegionScore(opts, stores[i], iOp, -1) > normalizedRegionScore(opts, stores[j], jOp, -1)
 	})
 	for _, source := range stores {
 		sourceID := source.GetID()
@@ -213,7 +278,7 @@ func (s *balanceRegionScheduler) transferPeer(cluster opt.Cluster, region *core.
 
 	candidates := filter.NewCandidates(cluster.GetStores()).
 		FilterTarget(cluster.GetOpts(), filters...).
-		Sort(filter.RegionScoreComparer(cluster.GetOpts()))
+		Sort(normalizedRegionScoreComparer(cluster.GetOpts()))
 
 	for _, target := range candidates.Stores {
 		regionID := region.GetID()
@@ -223,7 +288,7 @@ func (s *balanceRegionScheduler) transferPeer(cluster opt.Cluster, region *core.
 
 		opInfluence := s.opController.GetOpInfluence(cluster)
 		kind := core.NewScheduleKind(core.RegionKind, core.BySize)
-		shouldBalance, sourceScore, targetScore := shouldBalance(cluster, source, target, region, kind, opInfluence, s.GetName())
+		shouldBalance, sourceScore, targetScore := shouldBalance(cluster, source, target, region, kind, opInfluence, s.GetName(), s.tolerantTuner)
 		if !shouldBalance {
 			schedulerCounter.WithLabelValues(s.GetName(), "skip").Inc()
 			continue
@@ -246,6 +311,7 @@ func (s *balanceRegionScheduler) transferPeer(cluster opt.Cluster, region *core.
 		)
 		op.AdditionalInfos["sourceScore"] = strconv.FormatFloat(sourceScore, 'f', 2, 64)
 		op.AdditionalInfos["targetScore"] = strconv.FormatFloat(targetScore, 'f', 2, 64)
+		s.tolerantTuner.recordMove(sourceID, targetID)
 		return op
 	}
 
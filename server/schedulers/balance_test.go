@@ -18,6 +18,9 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -117,7 +120,7 @@ func (s *testBalanceSuite) TestShouldBalance(c *C) {
 		tc.PutRegion(region)
 		tc.SetLeaderSchedulePolicy(t.kind.String())
 		kind := core.NewScheduleKind(core.LeaderKind, t.kind)
-		shouldBalance, _, _ := shouldBalance(tc, source, target, region, kind, oc.GetOpInfluence(tc), "")
+		shouldBalance, _, _ := shouldBalance(tc, source, target, region, kind, oc.GetOpInfluence(tc), "", nil)
 		c.Assert(shouldBalance, Equals, t.expectedResult)
 	}
 
@@ -130,12 +133,96 @@ func (s *testBalanceSuite) TestShouldBalance(c *C) {
 			region := tc.GetRegion(1).Clone(core.SetApproximateSize(t.regionSize))
 			tc.PutRegion(region)
 			kind := core.NewScheduleKind(core.RegionKind, t.kind)
-			shouldBalance, _, _ := shouldBalance(tc, source, target, region, kind, oc.GetOpInfluence(tc), "")
+			shouldBalance, _, _ := shouldBalance(tc, source, target, region, kind, oc.GetOpInfluence(tc), "", nil)
 			c.Assert(shouldBalance, Equals, t.expectedResult)
 		}
 	}
 }
 
+func (s *testBalanceSuite) TestBalanceHysteresis(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	tc.SetTolerantSizeRatio(2.5)
+	tc.SetLeaderSchedulePolicy(core.ByCount.String())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	oc := schedule.NewOperatorController(ctx, nil, nil)
+	tc.AddLeaderStore(1, 10)
+	tc.AddLeaderStore(2, 5)
+	tc.AddLeaderRegion(1, 1, 2)
+	source, target, region := tc.GetStore(1), tc.GetStore(2), tc.GetRegion(1)
+	kind := core.NewScheduleKind(core.LeaderKind, core.ByCount)
+
+	// source score is 10-2=8, target score is 5+2=7.
+	balance, _, _ := shouldBalance(tc, source, target, region, kind, oc.GetOpInfluence(tc), "", nil)
+	c.Assert(balance, IsTrue)
+	// 8 is in the noise band of 7*(1+0.2).
+	tc.SetBalanceHysteresisRatio(0.2)
+	balance, _, _ = shouldBalance(tc, source, target, region, kind, oc.GetOpInfluence(tc), "", nil)
+	c.Assert(balance, IsFalse)
+	tc.SetBalanceHysteresisRatio(0.1)
+	balance, _, _ = shouldBalance(tc, source, target, region, kind, oc.GetOpInfluence(tc), "", nil)
+	c.Assert(balance, IsTrue)
+}
+
+func (s *testBalanceSuite) TestBalanceTriggerThreshold(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	oc := schedule.NewOperatorController(ctx, tc, nil)
+	tc.AddLeaderStore(1, 10)
+	tc.AddLeaderStore(2, 8)
+	lb := newBalanceLeaderScheduler(oc, &balanceLeaderSchedulerConfig{Name: BalanceLeaderName})
+	c.Assert(lb.IsScheduleAllowed(tc), IsTrue)
+
+	// the imbalance ratio is (10-8)/10=0.2.
+	lb = newBalanceLeaderScheduler(oc, &balanceLeaderSchedulerConfig{Name: BalanceLeaderName, BalanceTriggerThresholdRatio: 0.5})
+	c.Assert(lb.IsScheduleAllowed(tc), IsFalse)
+	tc.UpdateLeaderCount(2, 2)
+	c.Assert(lb.IsScheduleAllowed(tc), IsTrue)
+
+	// the region scores of the stores are the same.
+	rb := newBalanceRegionScheduler(oc, &balanceRegionSchedulerConfig{Name: BalanceRegionName, BalanceTriggerThresholdRatio: 0.5})
+	c.Assert(rb.IsScheduleAllowed(tc), IsFalse)
+}
+
+func (s *testBalanceSuite) TestBalanceTriggerThresholdConfig(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	oc := schedule.NewOperatorController(ctx, tc, nil)
+	tc.AddLeaderStore(1, 10)
+	tc.AddLeaderStore(2, 8)
+	storage := core.NewStorage(kv.NewMemoryKV())
+	lb, err := schedule.CreateScheduler(BalanceLeaderType, oc, storage, schedule.ConfigSliceDecoder(BalanceLeaderType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		lb.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+	c.Assert(lb.IsScheduleAllowed(tc), IsTrue)
+
+	// the invalid ratios are rejected.
+	for _, body := range []string{`{}`, `{"balance-trigger-threshold-ratio": "0.5"}`, `{"balance-trigger-threshold-ratio": -0.1}`, `{"balance-trigger-threshold-ratio": 1.5}`} {
+		c.Assert(serve(http.MethodPost, "/config", body).Code, Equals, http.StatusBadRequest)
+	}
+	c.Assert(serve(http.MethodPost, "/config", `{"balance-trigger-threshold-ratio": 0.5}`).Code, Equals, http.StatusOK)
+	c.Assert(serve(http.MethodGet, "/list", "").Body.String(), Matches, `(?s).*"balance-trigger-threshold-ratio": 0.5.*`)
+	// the imbalance ratio is (10-8)/10=0.2.
+	c.Assert(lb.IsScheduleAllowed(tc), IsFalse)
+
+	// the ratio is persisted.
+	_, configs, err := storage.LoadAllScheduleConfig()
+	c.Assert(err, IsNil)
+	c.Assert(configs, HasLen, 1)
+	lb, err = schedule.CreateScheduler(BalanceLeaderType, oc, storage, schedule.ConfigJSONDecoder([]byte(configs[0])))
+	c.Assert(err, IsNil)
+	c.Assert(lb.IsScheduleAllowed(tc), IsFalse)
+}
+
 func (s *testBalanceSuite) TestBalanceLimit(c *C) {
 	opt := config.NewTestOptions()
 	tc := mockcluster.NewCluster(opt)
@@ -160,16 +247,68 @@ func (s *testBalanceSuite) TestTolerantRatio(c *C) {
 	region := tc.GetRegion(1).Clone(core.SetApproximateSize(regionSize))
 
 	tc.SetTolerantSizeRatio(0)
-	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.LeaderKind, Policy: core.ByCount}), Equals, int64(leaderTolerantSizeRatio))
-	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.LeaderKind, Policy: core.BySize}), Equals, int64(adjustTolerantRatio(tc)*float64(regionSize)))
-	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.RegionKind, Policy: core.ByCount}), Equals, int64(adjustTolerantRatio(tc)*float64(regionSize)))
-	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.RegionKind, Policy: core.BySize}), Equals, int64(adjustTolerantRatio(tc)*float64(regionSize)))
+	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.LeaderKind, Policy: core.ByCount}, nil), Equals, int64(leaderTolerantSizeRatio))
+	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.LeaderKind, Policy: core.BySize}, nil), Equals, int64(adjustTolerantRatio(tc, nil)*float64(regionSize)))
+	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.RegionKind, Policy: core.ByCount}, nil), Equals, int64(adjustTolerantRatio(tc, nil)*float64(regionSize)))
+	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.RegionKind, Policy: core.BySize}, nil), Equals, int64(adjustTolerantRatio(tc, nil)*float64(regionSize)))
 
 	tc.SetTolerantSizeRatio(10)
-	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.LeaderKind, Policy: core.ByCount}), Equals, int64(tc.GetScheduleConfig().TolerantSizeRatio))
-	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.LeaderKind, Policy: core.BySize}), Equals, int64(adjustTolerantRatio(tc)*float64(regionSize)))
-	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.RegionKind, Policy: core.ByCount}), Equals, int64(adjustTolerantRatio(tc)*float64(regionSize)))
-	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.RegionKind, Policy: core.BySize}), Equals, int64(adjustTolerantRatio(tc)*float64(regionSize)))
+	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.LeaderKind, Policy: core.ByCount}, nil), Equals, int64(tc.GetScheduleConfig().TolerantSizeRatio))
+	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.LeaderKind, Policy: core.BySize}, nil), Equals, int64(adjustTolerantRatio(tc, nil)*float64(regionSize)))
+	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.RegionKind, Policy: core.ByCount}, nil), Equals, int64(adjustTolerantRatio(tc, nil)*float64(regionSize)))
+	c.Assert(getTolerantResource(tc, region, core.ScheduleKind{Resource: core.RegionKind, Policy: core.BySize}, nil), Equals, int64(adjustTolerantRatio(tc, nil)*float64(regionSize)))
+}
+
+func (s *testBalanceSuite) TestTolerantRatioTuner(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	tc.SetTolerantSizeRatio(0)
+	tc.AddRegionStore(1, 1000)
+	tc.AddRegionStore(2, 1000)
+	tuner := newTolerantRatioTuner()
+	scores := map[uint64]float64{}
+	score := func(store *core.StoreInfo) float64 { return scores[store.GetID()] }
+	observe := func(source, target float64) {
+		scores[1], scores[2] = source, target
+		tuner.observe(tc, nil, score)
+	}
+
+	// starts from the ratio estimated by the region count.
+	c.Assert(adjustTolerantRatio(tc, tuner), Equals, 5.0)
+	observe(100, 50)
+	tuner.recordMove(1, 2)
+	observe(80, 70)
+	c.Assert(tuner.getRatio(tc), Equals, 5.0)
+	// the source score drops below the target within 2 rounds.
+	observe(60, 90)
+	c.Assert(tuner.getRatio(tc), Equals, 6.0)
+
+	// decreases after the balance improves for 3 rounds.
+	observe(95, 60)
+	observe(90, 60)
+	observe(85, 60)
+	c.Assert(tuner.getRatio(tc), Equals, 6.0)
+	observe(80, 60)
+	c.Assert(math.Abs(tuner.getRatio(tc)-4.8) < 1e-9, IsTrue)
+
+	// the moves out of 2 rounds are not checked.
+	ratio := tuner.getRatio(tc)
+	tuner.recordMove(1, 2)
+	observe(100, 50)
+	observe(100, 50)
+	observe(40, 100)
+	c.Assert(tuner.getRatio(tc), Equals, ratio)
+
+	// the ratio is bounded.
+	for i := 0; i < 20; i++ {
+		tuner.recordMove(1, 2)
+		observe(40, 100)
+	}
+	c.Assert(tuner.getRatio(tc), Equals, 5.0*maxTolerantRatioFactor)
+
+	// the ratio in the config is preferred.
+	tc.SetTolerantSizeRatio(2.5)
+	c.Assert(adjustTolerantRatio(tc, tuner), Equals, 2.5)
 }
 
 var _ = Suite(&testBalanceLeaderSchedulerSuite{})
@@ -201,6 +340,24 @@ func (s *testBalanceLeaderSchedulerSuite) schedule() []*operator.Operator {
 	return s.lb.Schedule(s.tc)
 }
 
+func (s *testBalanceLeaderSchedulerSuite) TestStoreLabelWeights(c *C) {
+	s.opt.SetPlacementRuleEnabled(false)
+	s.tc.SetTolerantSizeRatio(1)
+	s.tc.AddLabelsStore(1, 10, map[string]string{"tier": "hdd"})
+	s.tc.AddLabelsStore(2, 20, map[string]string{"tier": "nvme"})
+	s.tc.UpdateLeaderCount(1, 10)
+	s.tc.UpdateLeaderCount(2, 20)
+	s.tc.AddLeaderRegion(1, 2, 1)
+	s.tc.AddLeaderRegion(2, 1, 2)
+
+	// Without the label weights, the store with more leaders is the source.
+	testutil.CheckTransferLeader(c, s.schedule()[0], operator.OpKind(0), 2, 1)
+
+	// The nvme store can hold more leaders, so it becomes the target.
+	s.tc.SetStoreLabelWeights(map[string]float64{"tier=nvme": 0.25})
+	testutil.CheckTransferLeader(c, s.schedule()[0], operator.OpKind(0), 1, 2)
+}
+
 func (s *testBalanceLeaderSchedulerSuite) TestBalanceLimit(c *C) {
 	s.tc.SetTolerantSizeRatio(2.5)
 	// Stores:     1    2    3    4
@@ -614,6 +771,34 @@ func (s *testBalanceRegionSchedulerSuite) TestBalance(c *C) {
 	c.Assert(sb.Schedule(tc), NotNil)
 }
 
+func (s *testBalanceRegionSchedulerSuite) TestStoreLabelWeights(c *C) {
+	opt := config.NewTestOptions()
+	opt.SetPlacementRuleEnabled(false)
+	tc := mockcluster.NewCluster(opt)
+	tc.DisableFeature(versioninfo.JointConsensus)
+	tc.SetTolerantSizeRatio(1)
+	tc.SetRegionScoreFormulaVersion("v1")
+	oc := schedule.NewOperatorController(s.ctx, nil, nil)
+
+	sb, err := schedule.CreateScheduler(BalanceRegionType, oc, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(BalanceRegionType, []string{"", ""}))
+	c.Assert(err, IsNil)
+
+	opt.SetMaxReplicas(1)
+	tc.AddLabelsStore(1, 6, map[string]string{"tier": "hdd"})
+	tc.AddLabelsStore(2, 10, map[string]string{"tier": "nvme"})
+	tc.AddLeaderRegion(1, 2)
+	tc.AddLeaderRegion(2, 1)
+
+	// Without the label weights, the store with more regions is balanced.
+	testutil.CheckTransferPeerWithLeaderTransfer(c, sb.Schedule(tc)[0], operator.OpKind(0), 2, 1)
+
+	// The nvme store can hold more regions.
+	tc.SetStoreLabelWeights(map[string]float64{"tier=nvme": 0.25})
+	c.Assert(opt.NormalizationFactor(tc.GetStore(1)), Equals, 1.0)
+	c.Assert(opt.NormalizationFactor(tc.GetStore(2)), Equals, 0.25)
+	testutil.CheckTransferPeerWithLeaderTransfer(c, sb.Schedule(tc)[0], operator.OpKind(0), 1, 2)
+}
+
 func (s *testBalanceRegionSchedulerSuite) TestReplicas3(c *C) {
 	opt := config.NewTestOptions()
 	//TODO: enable placementrules
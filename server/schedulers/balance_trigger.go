@@ -0,0 +1,70 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/unrolled/render"
+)
+
+const balanceTriggerThresholdRatioKey = "balance-trigger-threshold-ratio"
+
+// balanceTriggerHandler serves the config API of the balance schedulers, which
+// gets and sets the trigger threshold ratio.
+type balanceTriggerHandler struct {
+	rd  *render.Render
+	get func() float64
+	set func(float64) error
+}
+
+func newBalanceTriggerHandler(get func() float64, set func(float64) error) http.Handler {
+	h := &balanceTriggerHandler{
+		rd:  render.New(render.Options{IndentJSON: true}),
+		get: get,
+		set: set,
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/list", h.handleGetConfig).Methods("GET")
+	router.HandleFunc("/config", h.handleSetConfig).Methods("POST")
+	return router
+}
+
+func (h *balanceTriggerHandler) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+	h.rd.JSON(w, http.StatusOK, map[string]float64{balanceTriggerThresholdRatioKey: h.get()})
+}
+
+func (h *balanceTriggerHandler) handleSetConfig(w http.ResponseWriter, r *http.Request) {
+	input := make(map[string]interface{})
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	ratio, ok := input[balanceTriggerThresholdRatioKey].(float64)
+	if !ok {
+		h.rd.JSON(w, http.StatusBadRequest, "missing or invalid "+balanceTriggerThresholdRatioKey)
+		return
+	}
+	// The imbalance ratio is between 0 and 1, so a larger threshold never triggers.
+	if ratio < 0 || ratio > 1 {
+		h.rd.JSON(w, http.StatusBadRequest, balanceTriggerThresholdRatioKey+" should be between 0 and 1")
+		return
+	}
+	if err := h.set(ratio); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "success")
+}
@@ -95,6 +95,10 @@ type hotScheduler struct {
 	// this records regionID which have pending Operator by operation type. During filterHotPeers, the hot peers won't
 	// be selected if its owner region is tracked in this attribute.
 	regionPendings map[uint64][2]*operator.Operator
+	// movedRegions stores [rwType] regionID -> movedRegion
+	// this records the regions moved by the scheduler. The regions won't be scheduled in the same rwType again
+	// until the cooldown duration passes after the operator finishes.
+	movedRegions [2]map[uint64]*movedRegion
 
 	// temporary states but exported to API or metrics
 	stLoadInfos [resourceTypeLen]map[uint64]*storeLoadDetail
@@ -121,6 +125,9 @@ func newHotScheduler(opController *schedule.OperatorController, conf *hotRegionS
 		ret.pendings[ty] = map[*pendingInfluence]struct{}{}
 		ret.stLoadInfos[ty] = map[uint64]*storeLoadDetail{}
 	}
+	for _, rw := range []rwType{write, read} {
+		ret.movedRegions[rw] = make(map[uint64]*movedRegion)
+	}
 	return ret
 }
 
@@ -161,8 +168,28 @@ func (h *hotScheduler) IsScheduleAllowed(cluster opt.Cluster) bool {
 	return h.allowBalanceLeader(cluster) || h.allowBalanceRegion(cluster)
 }
 
+// getHotRegionScheduleLimit returns the effective hot region schedule limit,
+// which scales with the number of stores. A zero limit still disables the hot
+// region scheduling.
+func getHotRegionScheduleLimit(cluster opt.Cluster) uint64 {
+	limit := cluster.GetOpts().GetHotRegionScheduleLimit()
+	if limit == 0 {
+		return 0
+	}
+	var storeCount int
+	for _, store := range cluster.GetStores() {
+		if !store.IsTombstone() {
+			storeCount++
+		}
+	}
+	if scaled := uint64(cluster.GetOpts().GetHotRegionScheduleLimitPerStore() * float64(storeCount)); scaled > limit {
+		return scaled
+	}
+	return limit
+}
+
 func (h *hotScheduler) allowBalanceLeader(cluster opt.Cluster) bool {
-	hotRegionAllowed := h.OpController.OperatorCount(operator.OpHotRegion) < cluster.GetOpts().GetHotRegionScheduleLimit()
+	hotRegionAllowed := h.OpController.OperatorCount(operator.OpHotRegion) < getHotRegionScheduleLimit(cluster)
 	leaderAllowed := h.OpController.OperatorCount(operator.OpLeader) < cluster.GetOpts().GetLeaderScheduleLimit()
 	if !hotRegionAllowed {
 		operator.OperatorLimitCounter.WithLabelValues(h.GetType(), operator.OpHotRegion.String()).Inc()
@@ -174,7 +201,7 @@ func (h *hotScheduler) allowBalanceLeader(cluster opt.Cluster) bool {
 }
 
 func (h *hotScheduler) allowBalanceRegion(cluster opt.Cluster) bool {
-	allowed := h.OpController.OperatorCount(operator.OpHotRegion) < cluster.GetOpts().GetHotRegionScheduleLimit()
+	allowed := h.OpController.OperatorCount(operator.OpHotRegion) < getHotRegionScheduleLimit(cluster)
 	if !allowed {
 		operator.OperatorLimitCounter.WithLabelValues(h.GetType(), operator.OpHotRegion.String()).Inc()
 	}
@@ -205,6 +232,7 @@ func (h *hotScheduler) dispatch(typ rwType, cluster opt.Cluster) []*operator.Ope
 // each store
 func (h *hotScheduler) prepareForBalance(cluster opt.Cluster) {
 	h.summaryPendingInfluence()
+	h.gcMovedRegions(cluster.GetOpts().GetHotRegionCooldownDuration())
 
 	storesLoads := cluster.GetStoresLoads()
 
@@ -267,6 +295,52 @@ func (h *hotScheduler) gcRegionPendings() {
 	}
 }
 
+// movedRegion records a region moved by the hot region scheduler.
+type movedRegion struct {
+	op *operator.Operator
+	// version is the region epoch version when the region is moved. The cooldown is
+	// reset if it changes, which means the region has been split or merged.
+	version uint64
+}
+
+// lastMovedTime returns the time when the region finished moving, and whether
+// the cooldown of the region is still valid.
+func (m *movedRegion) lastMovedTime() (time.Time, bool) {
+	if !m.op.IsEnd() {
+		return time.Time{}, true
+	}
+	if m.op.Status() != operator.SUCCESS {
+		return time.Time{}, false
+	}
+	return m.op.GetReachTimeOf(operator.SUCCESS), true
+}
+
+// gcMovedRegions removes the regions whose cooldown has passed or whose operator failed.
+func (h *hotScheduler) gcMovedRegions(cooldown time.Duration) {
+	for rw, regions := range h.movedRegions {
+		for regionID, moved := range regions {
+			if movedTime, ok := moved.lastMovedTime(); !ok || (!movedTime.IsZero() && time.Since(movedTime) >= cooldown) {
+				delete(regions, regionID)
+			}
+		}
+		schedulerStatus.WithLabelValues(h.GetName(), "cooldown_regions_"+rwType(rw).String()).Set(float64(len(regions)))
+	}
+}
+
+// isRegionInCooldown checks whether the region was moved in the given rwType within the cooldown duration.
+func (h *hotScheduler) isRegionInCooldown(region *core.RegionInfo, rw rwType, cooldown time.Duration) bool {
+	moved, ok := h.movedRegions[rw][region.GetID()]
+	if !ok {
+		return false
+	}
+	if moved.version != region.GetRegionEpoch().GetVersion() {
+		delete(h.movedRegions[rw], region.GetID())
+		return false
+	}
+	movedTime, ok := moved.lastMovedTime()
+	return ok && !movedTime.IsZero() && time.Since(movedTime) < cooldown
+}
+
 // summaryStoresLoad Load information of all available stores.
 // it will filtered the hot peer and calculate the current and future stat(byte/key rate,count) for each store
 func summaryStoresLoad(
@@ -396,6 +470,7 @@ func (h *hotScheduler) addPendingInfluence(op *operator.Operator, srcStore, dstS
 		tmp[opTy] = op
 		h.regionPendings[regionID] = tmp
 	}
+	h.movedRegions[rwTy][regionID] = &movedRegion{op: op, version: op.RegionEpoch().GetVersion()}
 
 	schedulerStatus.WithLabelValues(h.GetName(), "pending_op_infos").Inc()
 	return true
@@ -615,8 +690,14 @@ func (bs *balanceSolver) filterHotPeers() []*statistics.HotPeerStat {
 	// Return at most MaxPeerNum peers, to prevent balanceSolver.solve() too slow.
 	maxPeerNum := bs.sche.conf.GetMaxPeerNumber()
 
-	// filter pending region
+	greenZoneRatio := bs.sche.conf.GetGreenZoneThresholdRatio()
+
+	// filter pending region and the region in green zone
 	appendItem := func(items []*statistics.HotPeerStat, item *statistics.HotPeerStat) []*statistics.HotPeerStat {
+		if !isAboveGreenZone(item, greenZoneRatio) {
+			schedulerCounter.WithLabelValues(bs.sche.GetName(), "in-green-zone").Inc()
+			return items
+		}
 		minHotDegree := bs.cluster.GetOpts().GetHotRegionCacheHitsThreshold()
 		if _, ok := bs.sche.regionPendings[item.ID()]; !ok && !item.IsNeedCoolDownTransferLeader(minHotDegree) {
 			// no in pending operator and no need cool down after transfer leader
@@ -669,6 +750,13 @@ func (bs *balanceSolver) filterHotPeers() []*statistics.HotPeerStat {
 	return ret
 }
 
+// isAboveGreenZone checks whether the byte rate or key rate of the peer exceeds
+// its hot thresholds * ratio.
+func isAboveGreenZone(peer *statistics.HotPeerStat, ratio float64) bool {
+	thresholds := peer.GetThresholds() // [byte rate threshold, key rate threshold]
+	return peer.GetByteRate() >= thresholds[0]*ratio || peer.GetKeyRate() >= thresholds[1]*ratio
+}
+
 // isRegionAvailable checks whether the given region is not available to schedule.
 func (bs *balanceSolver) isRegionAvailable(region *core.RegionInfo) bool {
 	if region == nil {
@@ -686,6 +774,11 @@ func (bs *balanceSolver) isRegionAvailable(region *core.RegionInfo) bool {
 		}
 	}
 
+	if bs.sche.isRegionInCooldown(region, bs.rwTy, bs.cluster.GetOpts().GetHotRegionCooldownDuration()) {
+		schedulerCounter.WithLabelValues(bs.sche.GetName(), "in-cooldown").Inc()
+		return false
+	}
+
 	if !opt.IsHealthyAllowPending(bs.cluster, region) {
 		schedulerCounter.WithLabelValues(bs.sche.GetName(), "unhealthy-replica").Inc()
 		return false
@@ -856,6 +949,15 @@ func (bs *balanceSolver) betterThan(old *solution) bool {
 	if bs.cur.srcPeerStat != old.srcPeerStat {
 		// compare region
 
+		// prefer the region which is getting hotter
+		if threshold := bs.sche.conf.GetTrendThreshold(); threshold > 0 {
+			curRising := bs.cur.srcPeerStat.Trend() >= threshold
+			oldRising := old.srcPeerStat.Trend() >= threshold
+			if curRising != oldRising {
+				return curRising
+			}
+		}
+
 		if bs.rwTy == write && bs.opTy == transferLeader {
 			switch {
 			case bs.cur.srcPeerStat.GetKeyRate() > old.srcPeerStat.GetKeyRate():
@@ -1042,6 +1144,11 @@ func (bs *balanceSolver) buildOperators() ([]*operator.Operator, []Influence) {
 		KeyRate:  bs.cur.srcPeerStat.GetKeyRate(),
 		Count:    1,
 	}
+	// Moving a write peer moves the compaction of its writes too, while
+	// transferring the leader does not change the disk cost.
+	if bs.rwTy == write && bs.opTy == movePeer {
+		infl.ByteRate *= bs.cluster.GetOpts().GetWriteAmplificationFactor()
+	}
 
 	return []*operator.Operator{op}, []Influence{infl}
 }
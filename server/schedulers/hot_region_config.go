@@ -33,17 +33,18 @@ import (
 // params about hot region.
 func initHotRegionScheduleConfig() *hotRegionSchedulerConfig {
 	return &hotRegionSchedulerConfig{
-		MinHotByteRate:        100,
-		MinHotKeyRate:         10,
-		MaxZombieRounds:       3,
-		ByteRateRankStepRatio: 0.05,
-		KeyRateRankStepRatio:  0.05,
-		CountRankStepRatio:    0.01,
-		GreatDecRatio:         0.95,
-		MinorDecRatio:         0.99,
-		MaxPeerNum:            1000,
-		SrcToleranceRatio:     1.05, // Tolerate 5% difference
-		DstToleranceRatio:     1.05, // Tolerate 5% difference
+		MinHotByteRate:          100,
+		MinHotKeyRate:           10,
+		MaxZombieRounds:         3,
+		ByteRateRankStepRatio:   0.05,
+		KeyRateRankStepRatio:    0.05,
+		CountRankStepRatio:      0.01,
+		GreatDecRatio:           0.95,
+		MinorDecRatio:           0.99,
+		MaxPeerNum:              1000,
+		SrcToleranceRatio:       1.05, // Tolerate 5% difference
+		DstToleranceRatio:       1.05, // Tolerate 5% difference
+		GreenZoneThresholdRatio: 1.2,
 	}
 }
 
@@ -65,6 +66,13 @@ type hotRegionSchedulerConfig struct {
 	MinorDecRatio         float64 `json:"minor-dec-ratio"`
 	SrcToleranceRatio     float64 `json:"src-tolerance-ratio"`
 	DstToleranceRatio     float64 `json:"dst-tolerance-ratio"`
+	// the peers whose rates are below the hot thresholds * green zone ratio are not scheduled,
+	// which prevents scheduling the peers going in and out of the hot cache frequently.
+	GreenZoneThresholdRatio float64 `json:"green-zone-threshold-ratio"`
+	// the peers whose byte rate trends are not less than the trend threshold are
+	// preferred to be scheduled, since they are still getting hotter.
+	// 0 means no preference.
+	TrendThreshold float64 `json:"trend-threshold"`
 }
 
 func (conf *hotRegionSchedulerConfig) EncodeConfig() ([]byte, error) {
@@ -109,6 +117,12 @@ func (conf *hotRegionSchedulerConfig) SetDstToleranceRatio(tol float64) {
 	conf.DstToleranceRatio = tol
 }
 
+func (conf *hotRegionSchedulerConfig) GetTrendThreshold() float64 {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.TrendThreshold
+}
+
 func (conf *hotRegionSchedulerConfig) GetByteRankStepRatio() float64 {
 	conf.RLock()
 	defer conf.RUnlock()
@@ -139,6 +153,12 @@ func (conf *hotRegionSchedulerConfig) GetMinorGreatDecRatio() float64 {
 	return conf.MinorDecRatio
 }
 
+func (conf *hotRegionSchedulerConfig) GetGreenZoneThresholdRatio() float64 {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.GreenZoneThresholdRatio
+}
+
 func (conf *hotRegionSchedulerConfig) GetMinHotKeyRate() float64 {
 	conf.RLock()
 	defer conf.RUnlock()
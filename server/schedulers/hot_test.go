@@ -119,6 +119,85 @@ func (s *testHotSchedulerSuite) TestGCPendingOpInfos(c *C) {
 	}
 }
 
+func (s *testHotSchedulerSuite) TestHotRegionScheduleLimitPerStore(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	tc.SetHotRegionScheduleLimit(4)
+	for id := uint64(1); id <= 50; id++ {
+		tc.AddRegionStore(id, 0)
+	}
+	// 0.1 * 50 stores
+	c.Assert(getHotRegionScheduleLimit(tc), Equals, uint64(5))
+	tc.SetHotRegionScheduleLimitPerStore(0.2)
+	c.Assert(getHotRegionScheduleLimit(tc), Equals, uint64(10))
+	// the tombstone stores are not counted.
+	for id := uint64(26); id <= 50; id++ {
+		tc.PutStore(tc.GetStore(id).Clone(core.TombstoneStore()))
+	}
+	c.Assert(getHotRegionScheduleLimit(tc), Equals, uint64(5))
+	tc.SetHotRegionScheduleLimitPerStore(0)
+	c.Assert(getHotRegionScheduleLimit(tc), Equals, uint64(4))
+	// the hot region scheduling stays disabled with a zero limit.
+	tc.SetHotRegionScheduleLimitPerStore(0.2)
+	tc.SetHotRegionScheduleLimit(0)
+	c.Assert(getHotRegionScheduleLimit(tc), Equals, uint64(0))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	hb, err := schedule.CreateScheduler(HotRegionType, schedule.NewOperatorController(ctx, tc, nil), core.NewStorage(kv.NewMemoryKV()), schedule.ConfigJSONDecoder([]byte("null")))
+	c.Assert(err, IsNil)
+	c.Assert(hb.IsScheduleAllowed(tc), IsFalse)
+}
+
+func (s *testHotSchedulerSuite) TestHotRegionCooldown(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	for id := uint64(1); id <= 3; id++ {
+		tc.PutStoreWithLabels(id)
+	}
+	sche, err := schedule.CreateScheduler(HotRegionType, schedule.NewOperatorController(ctx, tc, nil), core.NewStorage(kv.NewMemoryKV()), schedule.ConfigJSONDecoder([]byte("null")))
+	c.Assert(err, IsNil)
+	hb := sche.(*hotScheduler)
+	cooldown := opt.GetHotRegionCooldownDuration()
+	c.Assert(cooldown, Equals, 30*time.Second)
+
+	region := newTestRegion(1)
+	op, err := operator.CreateTransferLeaderOperator("transfer-leader-test", tc, region, 1, 2, operator.OpHotRegion)
+	c.Assert(err, IsNil)
+	c.Assert(hb.addPendingInfluence(op, 1, 2, Influence{}, read, transferLeader), IsTrue)
+	// The cooldown starts after the operator finishes.
+	c.Assert(hb.isRegionInCooldown(region, read, cooldown), IsFalse)
+	c.Assert(op.Start(), IsTrue)
+	op.Check(region.Clone(core.WithLeader(region.GetStorePeer(2))))
+	c.Assert(op.CheckSuccess(), IsTrue)
+	c.Assert(hb.isRegionInCooldown(region, read, cooldown), IsTrue)
+	// The cooldown is per direction.
+	c.Assert(hb.isRegionInCooldown(region, write, cooldown), IsFalse)
+	hb.gcMovedRegions(cooldown)
+	c.Assert(hb.movedRegions[read], HasLen, 1)
+
+	// The cooldown is reset after the region is split or merged.
+	c.Assert(hb.isRegionInCooldown(region.Clone(core.SetRegionVersion(1)), read, cooldown), IsFalse)
+	c.Assert(hb.movedRegions[read], HasLen, 0)
+
+	// The cooldown expires.
+	hb.movedRegions[read][1] = &movedRegion{op: op}
+	operator.SetOperatorStatusReachTime(op, operator.SUCCESS, time.Now().Add(-cooldown))
+	c.Assert(hb.isRegionInCooldown(region, read, cooldown), IsFalse)
+	hb.gcMovedRegions(cooldown)
+	c.Assert(hb.movedRegions[read], HasLen, 0)
+
+	// The canceled operator does not start the cooldown.
+	op, err = operator.CreateTransferLeaderOperator("transfer-leader-test", tc, region, 1, 2, operator.OpHotRegion)
+	c.Assert(err, IsNil)
+	hb.movedRegions[write][1] = &movedRegion{op: op}
+	c.Assert(op.Cancel(), IsTrue)
+	c.Assert(hb.isRegionInCooldown(region, write, cooldown), IsFalse)
+	hb.gcMovedRegions(cooldown)
+	c.Assert(hb.movedRegions[write], HasLen, 0)
+}
+
 func newTestRegion(id uint64) *core.RegionInfo {
 	peers := []*metapb.Peer{{Id: id*100 + 1, StoreId: 1}, {Id: id*100 + 2, StoreId: 2}, {Id: id*100 + 3, StoreId: 3}}
 	return core.NewRegionInfo(&metapb.Region{Id: id, Peers: peers}, peers[0])
@@ -308,6 +387,51 @@ func (s *testHotWriteRegionSchedulerSuite) checkByteRateOnly(c *C, tc *mockclust
 	hb.(*hotScheduler).clearPendingInfluence()
 }
 
+func (s *testHotWriteRegionSchedulerSuite) TestWriteAmplificationFactor(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	statistics.Denoising = false
+	opt := config.NewTestOptions()
+	opt.SetPlacementRuleEnabled(false)
+	tc := mockcluster.NewCluster(opt)
+	tc.SetMaxReplicas(3)
+	tc.DisableFeature(versioninfo.JointConsensus)
+	tc.SetHotRegionCacheHitsThreshold(0)
+	for i := uint64(1); i <= 5; i++ {
+		tc.AddRegionStore(i, 2)
+	}
+	tc.UpdateStorageWrittenBytes(1, 7.5*MB*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageWrittenBytes(2, 4.5*MB*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageWrittenBytes(3, 4.5*MB*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageWrittenBytes(4, 0)
+	tc.UpdateStorageWrittenBytes(5, 0)
+	addRegionInfo(tc, write, []testRegionInfo{
+		{1, []uint64{1, 2, 3}, 512 * KB, 0},
+		{2, []uint64{1, 2, 3}, 512 * KB, 0},
+		{3, []uint64{1, 2, 3}, 512 * KB, 0},
+	})
+
+	// pendingByteRate returns the byte rate of the pending influence of a hot peer to move.
+	pendingByteRate := func() float64 {
+		for i := 0; i < 100; i++ {
+			hb, err := schedule.CreateScheduler(HotWriteRegionType, schedule.NewOperatorController(ctx, nil, nil), core.NewStorage(kv.NewMemoryKV()), nil)
+			c.Assert(err, IsNil)
+			if ops := hb.Schedule(tc); len(ops) == 0 || ops[0].Len() == 1 {
+				continue
+			}
+			for p := range hb.(*hotScheduler).pendings[writePeer] {
+				return p.origin.ByteRate
+			}
+		}
+		c.Fatal("no hot peer is moved")
+		return 0
+	}
+	byteRate := pendingByteRate()
+	c.Assert(byteRate, Greater, 0.0)
+	tc.SetWriteAmplificationFactor(10)
+	c.Assert(pendingByteRate(), Equals, byteRate*10)
+}
+
 func (s *testHotWriteRegionSchedulerSuite) TestWithKeyRate(c *C) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -628,6 +752,37 @@ var _ = Suite(&testHotReadRegionSchedulerSuite{})
 
 type testHotReadRegionSchedulerSuite struct{}
 
+func (s *testHotReadRegionSchedulerSuite) TestGreenZone(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	tc.DisableFeature(versioninfo.JointConsensus)
+	hb, err := schedule.CreateScheduler(HotReadRegionType, schedule.NewOperatorController(ctx, nil, nil), core.NewStorage(kv.NewMemoryKV()), nil)
+	c.Assert(err, IsNil)
+	tc.SetHotRegionCacheHitsThreshold(0)
+
+	tc.AddRegionStore(1, 3)
+	tc.AddRegionStore(2, 3)
+	tc.AddRegionStore(3, 3)
+	tc.UpdateStorageReadBytes(1, 30*KB*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageReadBytes(2, 0)
+	tc.UpdateStorageReadBytes(3, 0)
+
+	// The byte rate of the regions is just above the min hot threshold of read flow, 8KB,
+	// but below the green zone threshold, 8KB * 1.2.
+	addRegionInfo(tc, read, []testRegionInfo{
+		{1, []uint64{1, 2, 3}, 9 * KB, 0},
+		{2, []uint64{1, 2, 3}, 9 * KB, 0},
+		{3, []uint64{1, 2, 3}, 9 * KB, 0},
+	})
+	c.Assert(tc.IsRegionHot(tc.GetRegion(1)), IsTrue)
+	c.Assert(hb.Schedule(tc), HasLen, 0)
+
+	hb.(*hotScheduler).conf.GreenZoneThresholdRatio = 1
+	c.Assert(hb.Schedule(tc), HasLen, 1)
+}
+
 func (s *testHotReadRegionSchedulerSuite) TestByteRateOnly(c *C) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -0,0 +1,214 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/filter"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/schedule/opt"
+	"go.uber.org/zap"
+)
+
+func init() {
+	// args: [start-key, end-key, start-key, end-key, ...], one pair for each group.
+	schedule.RegisterSliceDecoderBuilder(RegionGroupSizeBalancerType, func(args []string) schedule.ConfigDecoder {
+		return func(v interface{}) error {
+			conf, ok := v.(*regionGroupSizeBalancerConfig)
+			if !ok {
+				return errs.ErrScheduleConfigNotExist.FastGenByArgs()
+			}
+			if len(args) < 2 {
+				return errs.ErrSchedulerConfig.FastGenByArgs("group ranges")
+			}
+			ranges, err := getKeyRanges(args)
+			if err != nil {
+				return err
+			}
+			conf.GroupRanges = ranges
+			return nil
+		}
+	})
+	schedule.RegisterScheduler(RegionGroupSizeBalancerType, func(opController *schedule.OperatorController, storage *core.Storage, decoder schedule.ConfigDecoder) (schedule.Scheduler, error) {
+		conf := &regionGroupSizeBalancerConfig{}
+		if err := decoder(conf); err != nil {
+			return nil, err
+		}
+		return newRegionGroupSizeBalancer(opController, conf), nil
+	})
+}
+
+const (
+	// RegionGroupSizeBalancerName is region group size balancer name.
+	RegionGroupSizeBalancerName = "region-group-size-balancer"
+	// RegionGroupSizeBalancerType is region group size balancer type.
+	RegionGroupSizeBalancerType = "region-group-size-balancer"
+
+	regionGroupInfoKey = "group"
+)
+
+type regionGroupSizeBalancerConfig struct {
+	// GroupRanges are the key ranges of the region groups. A region belongs to
+	// a group if it overlaps the range of the group.
+	GroupRanges []core.KeyRange `json:"group-ranges"`
+}
+
+// regionGroupSizeBalancer balances the data size of each region group across
+// the stores. The groups are balanced independently, so a group with more
+// data is never moved to make room for another one.
+type regionGroupSizeBalancer struct {
+	*BaseScheduler
+	conf    *regionGroupSizeBalancerConfig
+	filters []filter.Filter
+}
+
+func newRegionGroupSizeBalancer(opController *schedule.OperatorController, conf *regionGroupSizeBalancerConfig) schedule.Scheduler {
+	return &regionGroupSizeBalancer{
+		BaseScheduler: NewBaseScheduler(opController),
+		conf:          conf,
+		filters: []filter.Filter{
+			&filter.StoreStateFilter{ActionScope: RegionGroupSizeBalancerName, MoveRegion: true},
+			filter.NewSpecialUseFilter(RegionGroupSizeBalancerName),
+		},
+	}
+}
+
+func (s *regionGroupSizeBalancer) GetName() string {
+	return RegionGroupSizeBalancerName
+}
+
+func (s *regionGroupSizeBalancer) GetType() string {
+	return RegionGroupSizeBalancerType
+}
+
+func (s *regionGroupSizeBalancer) EncodeConfig() ([]byte, error) {
+	return schedule.EncodeConfig(s.conf)
+}
+
+func (s *regionGroupSizeBalancer) IsScheduleAllowed(cluster opt.Cluster) bool {
+	allowed := s.OpController.OperatorCount(operator.OpRegion)-s.OpController.OperatorCount(operator.OpMerge) < cluster.GetOpts().GetRegionScheduleLimit()
+	if !allowed {
+		operator.OperatorLimitCounter.WithLabelValues(s.GetType(), operator.OpRegion.String()).Inc()
+	}
+	return allowed
+}
+
+func (s *regionGroupSizeBalancer) Schedule(cluster opt.Cluster) []*operator.Operator {
+	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	stores := filter.SelectSourceStores(cluster.GetStores(), s.filters, cluster.GetOpts())
+	if len(stores) < 2 {
+		return nil
+	}
+	influences := s.groupInfluences(cluster)
+	for i, group := range s.conf.GroupRanges {
+		sizes := s.groupSizes(cluster, group, influences[i])
+		if op := s.balanceGroup(cluster, i, group, stores, sizes); op != nil {
+			op.Counters = append(op.Counters, schedulerCounter.WithLabelValues(s.GetName(), "new-operator"))
+			return []*operator.Operator{op}
+		}
+	}
+	schedulerCounter.WithLabelValues(s.GetName(), "balanced").Inc()
+	return nil
+}
+
+// groupSizes returns the approximate size of the regions of the group on each
+// store from the region statistics, including the influence of the unfinished
+// operators of the group.
+func (s *regionGroupSizeBalancer) groupSizes(cluster opt.Cluster, group core.KeyRange, influence operator.OpInfluence) map[uint64]int64 {
+	sizes := make(map[uint64]int64)
+	for storeID, size := range cluster.GetRegionStats(group.StartKey, group.EndKey).StorePeerSize {
+		sizes[storeID] = size
+	}
+	for storeID, inf := range influence.StoresInfluence {
+		sizes[storeID] += inf.RegionSize
+	}
+	return sizes
+}
+
+// groupInfluences returns the influence of the unfinished operators created
+// by the scheduler for each group.
+func (s *regionGroupSizeBalancer) groupInfluences(cluster opt.Cluster) []operator.OpInfluence {
+	influences := make([]operator.OpInfluence, len(s.conf.GroupRanges))
+	for i := range influences {
+		influences[i] = operator.OpInfluence{StoresInfluence: make(map[uint64]*operator.StoreInfluence)}
+	}
+	for _, op := range s.OpController.GetOperators() {
+		if op.Desc() != RegionGroupSizeBalancerType {
+			continue
+		}
+		i, err := strconv.Atoi(op.AdditionalInfos[regionGroupInfoKey])
+		if err != nil || i < 0 || i >= len(influences) {
+			continue
+		}
+		if region := cluster.GetRegion(op.RegionID()); region != nil {
+			op.UnfinishedInfluence(influences[i], region)
+		}
+	}
+	return influences
+}
+
+// balanceGroup moves a region of the group from the store with the most data
+// of the group to the one with the least, if it narrows the gap between them.
+func (s *regionGroupSizeBalancer) balanceGroup(cluster opt.Cluster, index int, group core.KeyRange, stores []*core.StoreInfo, sizes map[uint64]int64) *operator.Operator {
+	sort.Slice(stores, func(i, j int) bool {
+		return sizes[stores[i].GetID()] > sizes[stores[j].GetID()]
+	})
+	source := stores[0]
+	ranges := []core.KeyRange{group}
+	region := cluster.RandFollowerRegion(source.GetID(), ranges, opt.HealthRegion(cluster), opt.ReplicatedRegion(cluster))
+	if region == nil {
+		region = cluster.RandLeaderRegion(source.GetID(), ranges, opt.HealthRegion(cluster), opt.ReplicatedRegion(cluster))
+	}
+	if region == nil {
+		schedulerCounter.WithLabelValues(s.GetName(), "no-region").Inc()
+		return nil
+	}
+
+	filters := []filter.Filter{
+		filter.NewExcludedFilter(s.GetName(), nil, region.GetStoreIds()),
+		filter.NewPlacementSafeguard(s.GetName(), cluster, region, source),
+		filter.NewSpecialUseFilter(s.GetName()),
+		&filter.StoreStateFilter{ActionScope: s.GetName(), MoveRegion: true},
+	}
+	// the stores are sorted by the size of the group, so try the smallest first.
+	for i := len(stores) - 1; i > 0; i-- {
+		target := stores[i]
+		if !filter.Target(cluster.GetOpts(), target, filters) {
+			continue
+		}
+		// moving the region narrows the gap only if the gap is larger than the region.
+		if sizes[source.GetID()]-sizes[target.GetID()] <= region.GetApproximateSize() {
+			return nil
+		}
+		oldPeer := region.GetStorePeer(source.GetID())
+		newPeer := &metapb.Peer{StoreId: target.GetID(), Role: oldPeer.GetRole()}
+		op, err := operator.CreateMovePeerOperator(RegionGroupSizeBalancerType, cluster, region, operator.OpRegion, source.GetID(), newPeer)
+		if err != nil {
+			log.Debug("fail to create move peer operator", zap.String("scheduler", s.GetName()), errs.ZapError(err))
+			schedulerCounter.WithLabelValues(s.GetName(), "create-operator-fail").Inc()
+			return nil
+		}
+		op.AdditionalInfos[regionGroupInfoKey] = strconv.Itoa(index)
+		return op
+	}
+	schedulerCounter.WithLabelValues(s.GetName(), "no-target-store").Inc()
+	return nil
+}
@@ -555,3 +555,46 @@ func (s *testBalanceLeaderSchedulerWithRuleEnabledSuite) TestBalanceLeaderWithCo
 		}
 	}
 }
+
+var _ = Suite(&testRegionGroupSizeBalancerSuite{})
+
+type testRegionGroupSizeBalancerSuite struct{}
+
+func (s *testRegionGroupSizeBalancerSuite) TestBalanceGroup(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opt := config.NewTestOptions()
+	opt.SetPlacementRuleEnabled(false)
+	tc := mockcluster.NewCluster(opt)
+	for i := uint64(1); i <= 4; i++ {
+		tc.AddRegionStore(i, 0)
+	}
+	// The regions of the group ["m", "") are balanced.
+	tc.AddLeaderRegionWithRange(1, "m", "n", 1, 2, 3)
+	tc.AddLeaderRegionWithRange(2, "n", "o", 2, 3, 4)
+	tc.AddLeaderRegionWithRange(3, "o", "p", 3, 4, 1)
+	tc.AddLeaderRegionWithRange(4, "p", "", 4, 1, 2)
+	// No region of the group ["", "m") is on store 4.
+	tc.AddLeaderRegionWithRange(5, "a", "b", 1, 2, 3)
+	tc.AddLeaderRegionWithRange(6, "b", "c", 1, 2, 3)
+	tc.AddLeaderRegionWithRange(7, "c", "d", 1, 2, 3)
+
+	oc := schedule.NewOperatorController(ctx, nil, nil)
+	sb, err := schedule.CreateScheduler(RegionGroupSizeBalancerType, oc, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(RegionGroupSizeBalancerType, []string{"m", "", "", "m"}))
+	c.Assert(err, IsNil)
+	c.Assert(sb.IsScheduleAllowed(tc), IsTrue)
+	ops := sb.Schedule(tc)
+	c.Assert(ops, HasLen, 1)
+	c.Assert(ops[0].RegionID() >= 5, IsTrue)
+	c.Assert(ops[0].AdditionalInfos[regionGroupInfoKey], Equals, "1")
+	c.Assert(ops[0].Step(0).(operator.AddLearner).ToStore, Equals, uint64(4))
+
+	// The group only covering the balanced regions needs no scheduling.
+	sb, err = schedule.CreateScheduler(RegionGroupSizeBalancerType, oc, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(RegionGroupSizeBalancerType, []string{"m", ""}))
+	c.Assert(err, IsNil)
+	c.Assert(sb.Schedule(tc), IsNil)
+
+	// The group ranges are required.
+	_, err = schedule.CreateScheduler(RegionGroupSizeBalancerType, oc, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(RegionGroupSizeBalancerType, nil))
+	c.Assert(err, NotNil)
+}
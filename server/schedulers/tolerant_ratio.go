@@ -0,0 +1,107 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"math"
+
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/filter"
+	"github.com/tikv/pd/server/schedule/opt"
+)
+
+const (
+	// oscillationRounds is the number of rounds in which a move is checked
+	// for oscillation, which means the source score drops below the target.
+	oscillationRounds = 2
+	// improvingRounds is the number of rounds in which the imbalance must
+	// keep dropping before the ratio is decreased.
+	improvingRounds = 3
+	// tolerantRatioStep is the relative step to adjust the ratio.
+	tolerantRatioStep = 0.2
+	// maxTolerantRatioFactor bounds the ratio by the factor of the ratio
+	// estimated by the region count.
+	maxTolerantRatioFactor = 4
+)
+
+type balanceMove struct {
+	source, target uint64
+	rounds         int
+}
+
+// tolerantRatioTuner estimates the tolerant size ratio online by the outcome
+// of the previous moves. The ratio grows when the moves overshoot and the
+// stores swap their places, and shrinks while the balance keeps improving.
+// It is not thread safe and should be only used by one scheduler.
+type tolerantRatioTuner struct {
+	autoTolerantSizeRatio float64
+	moves                 []balanceMove
+	lastImbalance         float64
+	improving             int
+}
+
+func newTolerantRatioTuner() *tolerantRatioTuner {
+	return &tolerantRatioTuner{}
+}
+
+// getRatio returns the estimated ratio, which starts from the ratio estimated
+// by the region count.
+func (t *tolerantRatioTuner) getRatio(cluster opt.Cluster) float64 {
+	if t.autoTolerantSizeRatio == 0 {
+		t.autoTolerantSizeRatio = estimateTolerantRatio(cluster)
+	}
+	return t.autoTolerantSizeRatio
+}
+
+// recordMove records a move created in the current round.
+func (t *tolerantRatioTuner) recordMove(source, target uint64) {
+	t.moves = append(t.moves, balanceMove{source: source, target: target})
+}
+
+// observe checks the outcome of the previous moves and adjusts the ratio. It
+// should be called once at the beginning of each round.
+func (t *tolerantRatioTuner) observe(cluster opt.Cluster, filters []filter.Filter, score func(*core.StoreInfo) float64) {
+	ratio := t.getRatio(cluster)
+	oscillated := false
+	moves := t.moves[:0]
+	for _, m := range t.moves {
+		source, target := cluster.GetStore(m.source), cluster.GetStore(m.target)
+		if source == nil || target == nil {
+			continue
+		}
+		if score(source) < score(target) {
+			oscillated = true
+			break
+		}
+		if m.rounds++; m.rounds < oscillationRounds {
+			moves = append(moves, m)
+		}
+	}
+	imbalance := getImbalanceRatio(cluster, filters, score)
+	switch {
+	case oscillated:
+		ratio *= 1 + tolerantRatioStep
+		moves, t.improving = moves[:0], 0
+	case imbalance > 0 && imbalance < t.lastImbalance:
+		if t.improving++; t.improving >= improvingRounds {
+			ratio *= 1 - tolerantRatioStep
+			t.improving = 0
+		}
+	default:
+		t.improving = 0
+	}
+	t.moves, t.lastImbalance = moves, imbalance
+	maxRatio := math.Max(estimateTolerantRatio(cluster), minTolerantSizeRatio) * maxTolerantRatioFactor
+	t.autoTolerantSizeRatio = math.Min(math.Max(ratio, minTolerantSizeRatio), maxRatio)
+}
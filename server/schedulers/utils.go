@@ -22,7 +22,9 @@ import (
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/typeutil"
+	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/filter"
 	"github.com/tikv/pd/server/schedule/operator"
 	"github.com/tikv/pd/server/schedule/opt"
 	"github.com/tikv/pd/server/statistics"
@@ -36,24 +38,24 @@ const (
 	minTolerantSizeRatio    float64 = 1.0
 )
 
-func shouldBalance(cluster opt.Cluster, source, target *core.StoreInfo, region *core.RegionInfo, kind core.ScheduleKind, opInfluence operator.OpInfluence, scheduleName string) (shouldBalance bool, sourceScore float64, targetScore float64) {
+func shouldBalance(cluster opt.Cluster, source, target *core.StoreInfo, region *core.RegionInfo, kind core.ScheduleKind, opInfluence operator.OpInfluence, scheduleName string, tuner *tolerantRatioTuner) (shouldBalance bool, sourceScore float64, targetScore float64) {
 	// The reason we use max(regionSize, averageRegionSize) to check is:
 	// 1. prevent moving small regions between stores with close scores, leading to unnecessary balance.
 	// 2. prevent moving huge regions, leading to over balance.
 	sourceID := source.GetID()
 	targetID := target.GetID()
-	tolerantResource := getTolerantResource(cluster, region, kind)
+	tolerantResource := getTolerantResource(cluster, region, kind, tuner)
 	sourceInfluence := opInfluence.GetStoreInfluence(sourceID).ResourceProperty(kind)
 	targetInfluence := opInfluence.GetStoreInfluence(targetID).ResourceProperty(kind)
 	sourceDelta, targetDelta := sourceInfluence-tolerantResource, targetInfluence+tolerantResource
 	opts := cluster.GetOpts()
 	switch kind.Resource {
 	case core.LeaderKind:
-		sourceScore = source.LeaderScore(kind.Policy, sourceDelta)
-		targetScore = target.LeaderScore(kind.Policy, targetDelta)
+		sourceScore = normalizedLeaderScore(opts, source, kind.Policy, sourceDelta)
+		targetScore = normalizedLeaderScore(opts, target, kind.Policy, targetDelta)
 	case core.RegionKind:
-		sourceScore = source.RegionScore(opts.GetRegionScoreFormulaVersion(), opts.GetHighSpaceRatio(), opts.GetLowSpaceRatio(), sourceDelta, -1)
-		targetScore = target.RegionScore(opts.GetRegionScoreFormulaVersion(), opts.GetHighSpaceRatio(), opts.GetLowSpaceRatio(), targetDelta, 1)
+		sourceScore = normalizedRegionScore(opts, source, sourceDelta, -1)
+		targetScore = normalizedRegionScore(opts, target, targetDelta, 1)
 	}
 	if opts.IsDebugMetricsEnabled() {
 		opInfluenceStatus.WithLabelValues(scheduleName, strconv.FormatUint(sourceID, 10), "source").Set(float64(sourceInfluence))
@@ -61,7 +63,12 @@ func shouldBalance(cluster opt.Cluster, source, target *core.StoreInfo, region *
 		tolerantResourceStatus.WithLabelValues(scheduleName, strconv.FormatUint(sourceID, 10), strconv.FormatUint(targetID, 10)).Set(float64(tolerantResource))
 	}
 	// Make sure after move, source score is still greater than target score.
-	shouldBalance = sourceScore > targetScore
+	// If the hysteresis is set, the difference should be out of the noise band.
+	if ratio := opts.GetBalanceHysteresisRatio(); ratio > 0 {
+		shouldBalance = sourceScore > targetScore*(1+ratio)
+	} else {
+		shouldBalance = sourceScore > targetScore
+	}
 
 	if !shouldBalance {
 		log.Debug("skip balance "+kind.Resource.String(),
@@ -76,7 +83,54 @@ func shouldBalance(cluster opt.Cluster, source, target *core.StoreInfo, region *
 	return shouldBalance, sourceScore, targetScore
 }
 
-func getTolerantResource(cluster opt.Cluster, region *core.RegionInfo, kind core.ScheduleKind) int64 {
+// normalizedRegionScore returns the region score of the store normalized by the
+// label weights, so the stores on different hardware can be compared.
+func normalizedRegionScore(opts *config.PersistOptions, store *core.StoreInfo, delta int64, deviation int) float64 {
+	return store.RegionScore(opts.GetRegionScoreFormulaVersion(), opts.GetHighSpaceRatio(), opts.GetLowSpaceRatio(), delta, deviation) * opts.NormalizationFactor(store)
+}
+
+// normalizedLeaderScore returns the leader score of the store normalized by the
+// label weights.
+func normalizedLeaderScore(opts *config.PersistOptions, store *core.StoreInfo, policy core.SchedulePolicy, delta int64) float64 {
+	return store.LeaderScore(policy, delta) * opts.NormalizationFactor(store)
+}
+
+// normalizedRegionScoreComparer is like filter.RegionScoreComparer, but compares
+// the normalized region scores.
+func normalizedRegionScoreComparer(opts *config.PersistOptions) filter.StoreComparer {
+	return func(a, b *core.StoreInfo) int {
+		sa, sb := normalizedRegionScore(opts, a, 0, 0), normalizedRegionScore(opts, b, 0, 0)
+		switch {
+		case sa > sb:
+			return 1
+		case sa < sb:
+			return -1
+		default:
+			return 0
+		}
+	}
+}
+
+// getImbalanceRatio returns how imbalanced the stores are, which is the score
+// difference between the highest source store and the lowest target store
+// divided by the highest score. 0 means there is nothing to balance.
+func getImbalanceRatio(cluster opt.Cluster, filters []filter.Filter, score func(*core.StoreInfo) float64) float64 {
+	stores := cluster.GetStores()
+	opts := cluster.GetOpts()
+	maxScore, minScore := math.Inf(-1), math.Inf(1)
+	for _, store := range filter.SelectSourceStores(stores, filters, opts) {
+		maxScore = math.Max(maxScore, score(store))
+	}
+	for _, store := range filter.SelectTargetStores(stores, filters, opts) {
+		minScore = math.Min(minScore, score(store))
+	}
+	if maxScore <= 0 || maxScore <= minScore || math.IsInf(minScore, 1) {
+		return 0
+	}
+	return (maxScore - minScore) / maxScore
+}
+
+func getTolerantResource(cluster opt.Cluster, region *core.RegionInfo, kind core.ScheduleKind, tuner *tolerantRatioTuner) int64 {
 	if kind.Resource == core.LeaderKind && kind.Policy == core.ByCount {
 		tolerantSizeRatio := cluster.GetOpts().GetTolerantSizeRatio()
 		if tolerantSizeRatio == 0 {
@@ -90,26 +144,38 @@ func getTolerantResource(cluster opt.Cluster, region *core.RegionInfo, kind core
 	if regionSize < cluster.GetAverageRegionSize() {
 		regionSize = cluster.GetAverageRegionSize()
 	}
-	regionSize = int64(float64(regionSize) * adjustTolerantRatio(cluster))
+	regionSize = int64(float64(regionSize) * adjustTolerantRatio(cluster, tuner))
 	return regionSize
 }
 
-func adjustTolerantRatio(cluster opt.Cluster) float64 {
-	tolerantSizeRatio := cluster.GetOpts().GetTolerantSizeRatio()
-	if tolerantSizeRatio == 0 {
-		var maxRegionCount float64
-		stores := cluster.GetStores()
-		for _, store := range stores {
-			regionCount := float64(cluster.GetStoreRegionCount(store.GetID()))
-			if maxRegionCount < regionCount {
-				maxRegionCount = regionCount
-			}
-		}
-		tolerantSizeRatio = maxRegionCount * adjustRatio
-		if tolerantSizeRatio < minTolerantSizeRatio {
-			tolerantSizeRatio = minTolerantSizeRatio
+// adjustTolerantRatio returns the tolerant size ratio. If it is not set in the
+// config, the ratio estimated online by the tuner is used, or the ratio
+// estimated by the region count if there is no tuner.
+func adjustTolerantRatio(cluster opt.Cluster, tuner *tolerantRatioTuner) float64 {
+	if tolerantSizeRatio := cluster.GetOpts().GetTolerantSizeRatio(); tolerantSizeRatio != 0 {
+		return tolerantSizeRatio
+	}
+	if tuner != nil {
+		return tuner.getRatio(cluster)
+	}
+	return estimateTolerantRatio(cluster)
+}
+
+// estimateTolerantRatio estimates the tolerant size ratio by the max region
+// count of the stores.
+func estimateTolerantRatio(cluster opt.Cluster) float64 {
+	var maxRegionCount float64
+	stores := cluster.GetStores()
+	for _, store := range stores {
+		regionCount := float64(cluster.GetStoreRegionCount(store.GetID()))
+		if maxRegionCount < regionCount {
+			maxRegionCount = regionCount
 		}
 	}
+	tolerantSizeRatio := maxRegionCount * adjustRatio
+	if tolerantSizeRatio < minTolerantSizeRatio {
+		tolerantSizeRatio = minTolerantSizeRatio
+	}
 	return tolerantSizeRatio
 }
 
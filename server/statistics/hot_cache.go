@@ -14,8 +14,11 @@
 package statistics
 
 import (
+	"encoding/json"
 	"math/rand"
+	"time"
 
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/server/core"
 )
 
@@ -47,6 +50,33 @@ func (w *HotCache) CheckRead(region *core.RegionInfo) []*HotPeerStat {
 	return w.readFlow.CheckRegionFlow(region)
 }
 
+// SetDenoiseWindowSize sets the min interval in seconds of a flow sample. The
+// heartbeats with shorter intervals are accumulated until the window is filled.
+func (w *HotCache) SetDenoiseWindowSize(size uint64) {
+	w.writeFlow.SetDenoiseWindowSize(size)
+	w.readFlow.SetDenoiseWindowSize(size)
+}
+
+// SetThresholdStabilityWindow sets the number of consecutive intervals the TopN
+// minimum of a store must stay stable before its hot thresholds are updated.
+func (w *HotCache) SetThresholdStabilityWindow(window int) {
+	w.writeFlow.SetThresholdStabilityWindow(window)
+	w.readFlow.SetThresholdStabilityWindow(window)
+}
+
+// SetThresholdRecalibrationInterval sets the min interval between two calculations
+// of the hot thresholds of a store.
+func (w *HotCache) SetThresholdRecalibrationInterval(interval time.Duration) {
+	w.writeFlow.SetThresholdRecalibrationInterval(interval)
+	w.readFlow.SetThresholdRecalibrationInterval(interval)
+}
+
+// SetSamplingRate sets the ratio of the heartbeats of the cold regions to check.
+func (w *HotCache) SetSamplingRate(rate float64) {
+	w.writeFlow.SetSamplingRate(rate)
+	w.readFlow.SetSamplingRate(rate)
+}
+
 // Update updates the cache.
 func (w *HotCache) Update(item *HotPeerStat) {
 	switch item.Kind {
@@ -65,6 +95,12 @@ func (w *HotCache) Update(item *HotPeerStat) {
 	}
 }
 
+// TransferHeat moves the write heat of the region from the old leader store
+// to the new leader store.
+func (w *HotCache) TransferHeat(oldStoreID, newStoreID, regionID uint64) {
+	w.writeFlow.TransferHeat(oldStoreID, newStoreID, regionID)
+}
+
 // RegionStats returns hot items according to kind
 func (w *HotCache) RegionStats(kind FlowKind, minHotDegree int) map[uint64][]*HotPeerStat {
 	switch kind {
@@ -90,6 +126,18 @@ func (w *HotCache) IsRegionHot(region *core.RegionInfo, minHotDegree int) bool {
 		w.readFlow.IsRegionHot(region, minHotDegree)
 }
 
+// GetStoreHotPeerCountTimeSeries returns the number of hot peers of the store
+// sampled in the window.
+func (w *HotCache) GetStoreHotPeerCountTimeSeries(storeID uint64, kind FlowKind, window time.Duration) []TimeSeriesPoint {
+	switch kind {
+	case WriteFlow:
+		return w.writeFlow.countHistory.get(storeID, window, time.Now())
+	case ReadFlow:
+		return w.readFlow.countHistory.get(storeID, window, time.Now())
+	}
+	return nil
+}
+
 // CollectMetrics collects the hot cache metrics.
 func (w *HotCache) CollectMetrics() {
 	w.writeFlow.CollectMetrics("write")
@@ -111,6 +159,49 @@ func (w *HotCache) incMetrics(name string, storeID uint64, kind FlowKind) {
 	}
 }
 
+// hotCacheSnapshot is the persisted form of HotCache.
+type hotCacheSnapshot struct {
+	WriteFlow json.RawMessage `json:"write"`
+	ReadFlow  json.RawMessage `json:"read"`
+}
+
+// Export serializes both the write and read hot peers so that a new PD leader
+// can warm up its cache by Import.
+func (w *HotCache) Export() ([]byte, error) {
+	write, err := w.writeFlow.Export()
+	if err != nil {
+		return nil, err
+	}
+	read, err := w.readFlow.Export()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(&hotCacheSnapshot{WriteFlow: write, ReadFlow: read})
+	if err != nil {
+		return nil, errs.ErrJSONMarshal.Wrap(err).GenWithStackByArgs()
+	}
+	return data, nil
+}
+
+// Import restores the hot peers serialized by Export.
+func (w *HotCache) Import(data []byte) error {
+	var snapshot hotCacheSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByArgs()
+	}
+	if len(snapshot.WriteFlow) > 0 {
+		if err := w.writeFlow.Import(snapshot.WriteFlow); err != nil {
+			return err
+		}
+	}
+	if len(snapshot.ReadFlow) > 0 {
+		if err := w.readFlow.Import(snapshot.ReadFlow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetFilledPeriod returns filled period.
 func (w *HotCache) GetFilledPeriod(kind FlowKind) int {
 	switch kind {
@@ -27,6 +27,43 @@ const (
 	dimLen
 )
 
+// hotPeerHistorySize is the number of the byte rate samples kept to calculate the trend.
+const hotPeerHistorySize = 10
+
+// rateHistory is a ring buffer of the recent byte rates of a hot peer, one
+// sample is recorded for each full rolling window.
+type rateHistory struct {
+	samples [hotPeerHistorySize]float64
+	next    int
+	count   int
+}
+
+func (h *rateHistory) add(rate float64) {
+	h.samples[h.next] = rate
+	h.next = (h.next + 1) % hotPeerHistorySize
+	if h.count < hotPeerHistorySize {
+		h.count++
+	}
+}
+
+// slope returns the least-squares slope of the samples in the order they are added.
+func (h *rateHistory) slope() float64 {
+	if h.count < 2 {
+		return 0
+	}
+	n := float64(h.count)
+	start := (h.next - h.count + hotPeerHistorySize) % hotPeerHistorySize
+	var sumX, sumY, sumXY, sumXX float64
+	for i := 0; i < h.count; i++ {
+		x, y := float64(i), h.samples[(start+i)%hotPeerHistorySize]
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+}
+
 type dimStat struct {
 	typ         int
 	Rolling     *movingaverage.TimeMedian  // it's used to statistic hot degree and average speed.
@@ -81,12 +118,21 @@ type HotPeerStat struct {
 	ByteRate float64  `json:"flow_bytes"`
 	KeyRate  float64  `json:"flow_keys"`
 
+	// ByteRateTrend is the trend of the byte rate, it is only filled in the
+	// cloned stat. Use Trend() instead.
+	ByteRateTrend float64 `json:"trend"`
+
 	// rolling statistics, recording some recently added records.
 	rollingByteRate *dimStat
 	rollingKeyRate  *dimStat
+	// history records the byte rate of the recent rolling windows.
+	history rateHistory
 
 	// LastUpdateTime used to calculate average write
 	LastUpdateTime time.Time `json:"last_update_time"`
+	// lastSeen is the time when the stat is put into the cache for the last
+	// time, which is used to remove the stale stats.
+	lastSeen time.Time
 
 	needDelete             bool
 	isLeader               bool
@@ -173,6 +219,12 @@ func (stat *HotPeerStat) GetKeyRate() float64 {
 	return math.Round(stat.rollingKeyRate.Get())
 }
 
+// Trend returns how fast the byte rate changes per rolling window, which is
+// positive if the peer is getting hotter.
+func (stat *HotPeerStat) Trend() float64 {
+	return stat.history.slope()
+}
+
 // GetThresholds returns thresholds
 func (stat *HotPeerStat) GetThresholds() [dimLen]float64 {
 	return stat.thresholds
@@ -185,6 +237,7 @@ func (stat *HotPeerStat) Clone() *HotPeerStat {
 	ret.rollingByteRate = nil
 	ret.KeyRate = stat.GetKeyRate()
 	ret.rollingKeyRate = nil
+	ret.ByteRateTrend = stat.Trend()
 	return &ret
 }
 
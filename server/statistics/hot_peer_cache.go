@@ -14,11 +14,17 @@
 package statistics
 
 import (
+	"encoding/json"
 	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/movingaverage"
 	"github.com/tikv/pd/server/core"
 	"go.uber.org/zap"
@@ -37,6 +43,28 @@ const (
 	HotRegionReportMinInterval = 3
 
 	hotRegionAntiCount = 2
+
+	// defaultThresholdStabilityWindow is the number of consecutive intervals the
+	// TopN minimum must stay stable before the hot threshold follows it.
+	defaultThresholdStabilityWindow = 5
+	// thresholdStabilityTolerance is the max relative change of the TopN minimum
+	// which is still regarded as stable.
+	thresholdStabilityTolerance = 0.05
+	// thresholdSampleInterval is the length of an interval of the threshold stability window.
+	thresholdSampleInterval = RegionHeartBeatReportInterval * time.Second
+	// thresholdRecalibrationTolerance is the max relative change of the TopN list size
+	// with which the cached thresholds are still used.
+	thresholdRecalibrationTolerance = 0.05
+	// staleHotPeerTTL is the duration after which a hot peer not updated by
+	// the heartbeats is removed, which is shorter than topNTTL so that the
+	// peers left on the old stores of a migrated region are removed faster.
+	staleHotPeerTTL = RegionHeartBeatReportInterval * time.Second * 3 / 2
+	// staleCheckInterval is the min interval between two checks of the stale
+	// hot peers, which avoids scanning the cache on every heartbeat.
+	staleCheckInterval = time.Second
+	// maxExportedHotPeers is the max number of the hot peers of a flow kind
+	// which are exported, so that the snapshot fits in a single etcd request.
+	maxExportedHotPeers = 1000
 )
 
 var (
@@ -57,17 +85,105 @@ type hotPeerCache struct {
 	kind           FlowKind
 	peersOfStore   map[uint64]*TopN               // storeID -> hot peers
 	storesOfRegion map[uint64]map[uint64]struct{} // regionID -> storeIDs
+	// denoiseWindowSize is the min interval in seconds of a flow sample, the heartbeats
+	// with shorter intervals are accumulated until the window is filled.
+	denoiseWindowSize uint64
+	denoiser          *heartbeatDenoiser
+	// thresholdStabilityWindow is the number of consecutive intervals the TopN minimum
+	// must stay stable before the hot thresholds are updated. 0 means no stabilization.
+	thresholdStabilityWindow int64
+	// thresholdMu protects thresholdStates, which is updated when checking the
+	// region flow by the concurrent heartbeats.
+	thresholdMu     sync.Mutex
+	thresholdStates map[uint64]*thresholdState // storeID -> threshold state
+	// recalibrationInterval is the min interval between two calculations of the hot
+	// thresholds of a store, 0 means calculating them every time.
+	recalibrationInterval int64
+	// cachedThresholdsMu protects cachedThresholds, which is updated by the concurrent
+	// heartbeats and the collection of the metrics.
+	cachedThresholdsMu sync.Mutex
+	cachedThresholds   map[uint64]*cachedThresholds // storeID -> cached thresholds
+	// countHistory records the number of hot peers of each store over time.
+	countHistory *hotPeerCountHistory
+	// lastStaleCheckTime is the last time to remove the stale hot peers.
+	lastStaleCheckTime time.Time
+	// samplingRate is the bits of the ratio of the heartbeats of the cold regions to check.
+	samplingRate uint64
+}
+
+// thresholdState records the hot thresholds of a store and how long the TopN
+// minimum has been stable.
+type thresholdState struct {
+	thresholds      [dimLen]float64 // the thresholds in use
+	candidate       [dimLen]float64 // the thresholds at the beginning of the stable intervals
+	stableIntervals int
+	lastSampleTime  time.Time
+}
+
+// cachedThresholds records the last calculated hot thresholds of a store.
+type cachedThresholds struct {
+	thresholds [dimLen]float64
+	topNLen    int
+	updateTime time.Time
 }
 
 // NewHotStoresStats creates a HotStoresStats
 func NewHotStoresStats(kind FlowKind) *hotPeerCache {
 	return &hotPeerCache{
-		kind:           kind,
-		peersOfStore:   make(map[uint64]*TopN),
-		storesOfRegion: make(map[uint64]map[uint64]struct{}),
+		kind:              kind,
+		peersOfStore:      make(map[uint64]*TopN),
+		storesOfRegion:    make(map[uint64]map[uint64]struct{}),
+		denoiseWindowSize: HotRegionReportMinInterval,
+		denoiser:          newHeartbeatDenoiser(),
+
+		thresholdStabilityWindow: defaultThresholdStabilityWindow,
+		thresholdStates:          make(map[uint64]*thresholdState),
+		cachedThresholds:         make(map[uint64]*cachedThresholds),
+		countHistory:             newHotPeerCountHistory(),
+		samplingRate:             math.Float64bits(1),
 	}
 }
 
+// SetDenoiseWindowSize sets the min interval in seconds of a flow sample.
+func (f *hotPeerCache) SetDenoiseWindowSize(size uint64) {
+	atomic.StoreUint64(&f.denoiseWindowSize, size)
+}
+
+// SetThresholdStabilityWindow sets the number of consecutive intervals the TopN
+// minimum must stay stable before the hot thresholds are updated.
+func (f *hotPeerCache) SetThresholdStabilityWindow(window int) {
+	atomic.StoreInt64(&f.thresholdStabilityWindow, int64(window))
+}
+
+// SetThresholdRecalibrationInterval sets the min interval between two calculations
+// of the hot thresholds of a store.
+func (f *hotPeerCache) SetThresholdRecalibrationInterval(interval time.Duration) {
+	atomic.StoreInt64(&f.recalibrationInterval, int64(interval))
+}
+
+// SetSamplingRate sets the ratio of the heartbeats of the cold regions to check.
+func (f *hotPeerCache) SetSamplingRate(rate float64) {
+	atomic.StoreUint64(&f.samplingRate, math.Float64bits(rate))
+}
+
+// GetAntiCountDistribution returns how many hot peers have each AntiCount, the
+// index of the result is the AntiCount. It helps to tune hotRegionAntiCount.
+func (f *hotPeerCache) GetAntiCountDistribution() [hotRegionAntiCount + 1]int {
+	var dist [hotRegionAntiCount + 1]int
+	for _, peers := range f.peersOfStore {
+		for _, v := range peers.GetAll() {
+			antiCount := v.(*HotPeerStat).AntiCount
+			if antiCount < 0 {
+				antiCount = 0
+			} else if antiCount > hotRegionAntiCount {
+				antiCount = hotRegionAntiCount
+			}
+			dist[antiCount]++
+		}
+	}
+	return dist
+}
+
 // RegionStats returns hot items
 func (f *hotPeerCache) RegionStats(minHotDegree int) map[uint64][]*HotPeerStat {
 	res := make(map[uint64][]*HotPeerStat)
@@ -101,6 +217,7 @@ func (f *hotPeerCache) Update(item *HotPeerStat) {
 			peers = NewTopN(dimLen, TopNN, topNTTL)
 			f.peersOfStore[item.StoreID] = peers
 		}
+		item.lastSeen = time.Now()
 		peers.Put(item)
 
 		stores, ok := f.storesOfRegion[item.RegionID]
@@ -111,6 +228,160 @@ func (f *hotPeerCache) Update(item *HotPeerStat) {
 		stores[item.StoreID] = struct{}{}
 		item.Log("region heartbeat update", log.Debug)
 	}
+	// The stale peers are removed here rather than in CheckRegionFlow, because the
+	// cache is only mutated by Update, which the cluster calls under its write lock.
+	if now := time.Now(); now.Sub(f.lastStaleCheckTime) >= staleCheckInterval {
+		f.ExpireStale(now)
+		f.lastStaleCheckTime = now
+	}
+}
+
+// ExpireStale removes the hot peers which are not updated by the heartbeats
+// for staleHotPeerTTL, such as the peers left on the old store after the
+// region is migrated. The pending flows of the regions which stop reporting,
+// such as the merged or removed ones, are dropped as well.
+func (f *hotPeerCache) ExpireStale(now time.Time) {
+	f.denoiser.prune(now, staleHotPeerTTL)
+	for storeID, peers := range f.peersOfStore {
+		for _, item := range peers.GetAll() {
+			stat := item.(*HotPeerStat)
+			if now.Sub(stat.lastSeen) <= staleHotPeerTTL {
+				continue
+			}
+			peers.Remove(stat.RegionID)
+			if stores, ok := f.storesOfRegion[stat.RegionID]; ok {
+				delete(stores, storeID)
+			}
+			stat.Log("remove stale hot peer from cache", log.Debug)
+		}
+	}
+}
+
+// TransferHeat copies the flow of the region from the peer on the old store
+// to the peer on the new store. It is used to keep the heat of the region when
+// the leader moves away from a down store. Nothing is changed if the peer on
+// the new store is already hotter.
+func (f *hotPeerCache) TransferHeat(oldStoreID, newStoreID, regionID uint64) {
+	if oldStoreID == newStoreID {
+		return
+	}
+	oldItem := f.getOldHotPeerStat(regionID, oldStoreID)
+	if oldItem == nil {
+		return
+	}
+	if item := f.getOldHotPeerStat(regionID, newStoreID); item != nil &&
+		item.GetByteRate() >= oldItem.GetByteRate() && item.GetKeyRate() >= oldItem.GetKeyRate() {
+		return
+	}
+	now := time.Now()
+	item := &HotPeerStat{
+		StoreID:                newStoreID,
+		RegionID:               regionID,
+		HotDegree:              oldItem.HotDegree,
+		AntiCount:              oldItem.AntiCount,
+		Kind:                   f.kind,
+		ByteRate:               oldItem.ByteRate,
+		KeyRate:                oldItem.KeyRate,
+		rollingByteRate:        newDimStat(byteDim),
+		rollingKeyRate:         newDimStat(keyDim),
+		LastUpdateTime:         now,
+		isLeader:               true,
+		justTransferLeader:     true,
+		interval:               oldItem.interval,
+		thresholds:             oldItem.thresholds,
+		peers:                  oldItem.peers,
+		history:                oldItem.history,
+		lastTransferLeaderTime: now,
+	}
+	item.rollingByteRate.Rolling.Set(oldItem.GetByteRate())
+	item.rollingKeyRate.Rolling.Set(oldItem.GetKeyRate())
+	f.Update(item)
+}
+
+// hotPeerSnapshot is the persisted form of a HotPeerStat.
+type hotPeerSnapshot struct {
+	StoreID                uint64    `json:"store_id"`
+	RegionID               uint64    `json:"region_id"`
+	HotDegree              int       `json:"hot_degree"`
+	AntiCount              int       `json:"anti_count"`
+	ByteRate               float64   `json:"byte_rate"`
+	KeyRate                float64   `json:"key_rate"`
+	LastUpdateTime         time.Time `json:"last_update_time"`
+	IsLeader               bool      `json:"is_leader"`
+	Peers                  []uint64  `json:"peers"`
+	LastTransferLeaderTime time.Time `json:"last_transfer_leader_time"`
+}
+
+// Export serializes the hot peers in the cache so that they can be restored by Import.
+// Only the denoised rates are kept, the rolling windows start over after importing.
+// At most maxExportedHotPeers peers with the highest hot degrees are exported.
+func (f *hotPeerCache) Export() ([]byte, error) {
+	return f.export(maxExportedHotPeers)
+}
+
+func (f *hotPeerCache) export(limit int) ([]byte, error) {
+	snapshots := make([]*hotPeerSnapshot, 0)
+	for _, peers := range f.peersOfStore {
+		for _, v := range peers.GetAll() {
+			stat := v.(*HotPeerStat)
+			snapshots = append(snapshots, &hotPeerSnapshot{
+				StoreID:                stat.StoreID,
+				RegionID:               stat.RegionID,
+				HotDegree:              stat.HotDegree,
+				AntiCount:              stat.AntiCount,
+				ByteRate:               stat.GetByteRate(),
+				KeyRate:                stat.GetKeyRate(),
+				LastUpdateTime:         stat.LastUpdateTime,
+				IsLeader:               stat.isLeader,
+				Peers:                  stat.peers,
+				LastTransferLeaderTime: stat.lastTransferLeaderTime,
+			})
+		}
+	}
+	if len(snapshots) > limit {
+		sort.Slice(snapshots, func(i, j int) bool {
+			return snapshots[i].HotDegree > snapshots[j].HotDegree
+		})
+		snapshots = snapshots[:limit]
+	}
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return nil, errs.ErrJSONMarshal.Wrap(err).GenWithStackByArgs()
+	}
+	return data, nil
+}
+
+// Import restores the hot peers serialized by Export. The peers which are
+// already in the cache or have expired are skipped.
+func (f *hotPeerCache) Import(data []byte) error {
+	var snapshots []*hotPeerSnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByArgs()
+	}
+	for _, s := range snapshots {
+		if time.Since(s.LastUpdateTime) > topNTTL || f.getOldHotPeerStat(s.RegionID, s.StoreID) != nil {
+			continue
+		}
+		item := &HotPeerStat{
+			StoreID:                s.StoreID,
+			RegionID:               s.RegionID,
+			HotDegree:              s.HotDegree,
+			AntiCount:              s.AntiCount,
+			Kind:                   f.kind,
+			ByteRate:               s.ByteRate,
+			KeyRate:                s.KeyRate,
+			rollingByteRate:        newDimStat(byteDim),
+			rollingKeyRate:         newDimStat(keyDim),
+			LastUpdateTime:         s.LastUpdateTime,
+			isLeader:               s.IsLeader,
+			peers:                  s.Peers,
+			lastTransferLeaderTime: s.LastTransferLeaderTime,
+		}
+		item.rollingByteRate.Rolling.Set(s.ByteRate)
+		item.rollingKeyRate.Rolling.Set(s.KeyRate)
+		f.Update(item)
+	}
+	return nil
 }
 
 func (f *hotPeerCache) collectRegionMetrics(byteRate, keyRate float64, interval uint64) {
@@ -130,17 +401,24 @@ func (f *hotPeerCache) collectRegionMetrics(byteRate, keyRate float64, interval
 
 // CheckRegionFlow checks the flow information of region.
 func (f *hotPeerCache) CheckRegionFlow(region *core.RegionInfo) (ret []*HotPeerStat) {
-
 	bytes := float64(f.getRegionBytes(region))
 	keys := float64(f.getRegionKeys(region))
 
 	reportInterval := region.GetInterval()
 	interval := reportInterval.GetEndTimestamp() - reportInterval.GetStartTimestamp()
 
+	f.collectRegionMetrics(bytes/float64(interval), keys/float64(interval), interval)
+	if f.skipColdRegion(region, bytes/float64(interval), keys/float64(interval)) {
+		return nil
+	}
+
+	// This is used for the simulator and test. Accumulate the flow if report too fast.
+	ready := true
+	if Denoising {
+		bytes, keys, interval, ready = f.denoiser.accumulate(region.GetID(), bytes, keys, interval, atomic.LoadUint64(&f.denoiseWindowSize))
+	}
 	byteRate := bytes / float64(interval)
 	keyRate := keys / float64(interval)
-
-	f.collectRegionMetrics(byteRate, keyRate, interval)
 	// old region is in the front and new region is in the back
 	// which ensures it will hit the cache if moving peer or transfer leader occurs with the same replica number
 
@@ -159,8 +437,7 @@ func (f *hotPeerCache) CheckRegionFlow(region *core.RegionInfo) (ret []*HotPeerS
 			tmpItem = oldItem
 		}
 
-		// This is used for the simulator and test. Ignore if report too fast.
-		if !isExpired && Denoising && interval < HotRegionReportMinInterval {
+		if !isExpired && !ready {
 			continue
 		}
 
@@ -209,6 +486,21 @@ func (f *hotPeerCache) CheckRegionFlow(region *core.RegionInfo) (ret []*HotPeerS
 	return ret
 }
 
+// skipColdRegion returns true if the heartbeat of a cold region is skipped by sampling.
+// A region is cold if none of its peers is cached and both its byte rate and key rate
+// are below the min hot thresholds. Most regions are cold when the load is stable, so sampling them saves
+// the CPU, while the heartbeats of the hot ones are never skipped.
+func (f *hotPeerCache) skipColdRegion(region *core.RegionInfo, byteRate, keyRate float64) bool {
+	rate := math.Float64frombits(atomic.LoadUint64(&f.samplingRate))
+	if rate >= 1 || byteRate >= minHotThresholds[f.kind][byteDim] || keyRate >= minHotThresholds[f.kind][keyDim] {
+		return false
+	}
+	if _, ok := f.storesOfRegion[region.GetID()]; ok {
+		return false
+	}
+	return rand.Float64() >= rate
+}
+
 func (f *hotPeerCache) IsRegionHot(region *core.RegionInfo, hotDegree int) bool {
 	switch f.kind {
 	case WriteFlow:
@@ -220,7 +512,9 @@ func (f *hotPeerCache) IsRegionHot(region *core.RegionInfo, hotDegree int) bool
 }
 
 func (f *hotPeerCache) CollectMetrics(typ string) {
+	now := time.Now()
 	for storeID, peers := range f.peersOfStore {
+		f.countHistory.observe(storeID, peers.Len(), now)
 		store := storeTag(storeID)
 		thresholds := f.calcHotThresholds(storeID)
 		hotCacheStatusGauge.WithLabelValues("total_length", store, typ).Set(float64(peers.Len()))
@@ -274,8 +568,17 @@ func (f *hotPeerCache) calcHotThresholds(storeID uint64) [dimLen]float64 {
 	minThresholds := minHotThresholds[f.kind]
 	tn, ok := f.peersOfStore[storeID]
 	if !ok || tn.Len() < TopNN {
+		f.thresholdMu.Lock()
+		delete(f.thresholdStates, storeID)
+		f.thresholdMu.Unlock()
+		f.removeCachedThresholds(storeID)
 		return minThresholds
 	}
+	interval := time.Duration(atomic.LoadInt64(&f.recalibrationInterval))
+	if cached := f.getCachedThresholds(storeID); cached != nil && interval > 0 &&
+		time.Since(cached.updateTime) < interval && !isTopNLenChanged(cached.topNLen, tn.Len()) {
+		return cached.thresholds
+	}
 	ret := [dimLen]float64{
 		byteDim: tn.GetTopNMin(byteDim).(*HotPeerStat).GetByteRate(),
 		keyDim:  tn.GetTopNMin(keyDim).(*HotPeerStat).GetKeyRate(),
@@ -283,9 +586,84 @@ func (f *hotPeerCache) calcHotThresholds(storeID uint64) [dimLen]float64 {
 	for k := 0; k < dimLen; k++ {
 		ret[k] = math.Max(ret[k]*HotThresholdRatio, minThresholds[k])
 	}
+	ret = f.stabilizeThresholds(storeID, ret)
+	if interval > 0 {
+		f.cachedThresholdsMu.Lock()
+		f.cachedThresholds[storeID] = &cachedThresholds{
+			thresholds: ret,
+			topNLen:    tn.Len(),
+			updateTime: time.Now(),
+		}
+		f.cachedThresholdsMu.Unlock()
+	} else {
+		f.removeCachedThresholds(storeID)
+	}
 	return ret
 }
 
+func (f *hotPeerCache) getCachedThresholds(storeID uint64) *cachedThresholds {
+	f.cachedThresholdsMu.Lock()
+	defer f.cachedThresholdsMu.Unlock()
+	return f.cachedThresholds[storeID]
+}
+
+func (f *hotPeerCache) removeCachedThresholds(storeID uint64) {
+	f.cachedThresholdsMu.Lock()
+	defer f.cachedThresholdsMu.Unlock()
+	delete(f.cachedThresholds, storeID)
+}
+
+// isTopNLenChanged returns true if the size of the TopN list changes so much that
+// the cached thresholds need to be recalculated.
+func isTopNLenChanged(old, cur int) bool {
+	return math.Abs(float64(cur-old)) > float64(old)*thresholdRecalibrationTolerance
+}
+
+// stabilizeThresholds keeps the previous thresholds of the store unless the new
+// thresholds have been stable for thresholdStabilityWindow intervals, so that
+// the thresholds do not oscillate when the TopN list churns.
+func (f *hotPeerCache) stabilizeThresholds(storeID uint64, thresholds [dimLen]float64) [dimLen]float64 {
+	window := int(atomic.LoadInt64(&f.thresholdStabilityWindow))
+	if window <= 0 {
+		return thresholds
+	}
+	f.thresholdMu.Lock()
+	defer f.thresholdMu.Unlock()
+	now := time.Now()
+	state, ok := f.thresholdStates[storeID]
+	if !ok {
+		f.thresholdStates[storeID] = &thresholdState{
+			thresholds:     thresholds,
+			candidate:      thresholds,
+			lastSampleTime: now,
+		}
+		return thresholds
+	}
+	if now.Sub(state.lastSampleTime) < thresholdSampleInterval {
+		return state.thresholds
+	}
+	state.lastSampleTime = now
+	if isThresholdStable(state.candidate, thresholds) {
+		state.stableIntervals++
+	} else {
+		state.candidate = thresholds
+		state.stableIntervals = 0
+	}
+	if state.stableIntervals >= window {
+		state.thresholds = thresholds
+	}
+	return state.thresholds
+}
+
+func isThresholdStable(old, cur [dimLen]float64) bool {
+	for k := 0; k < dimLen; k++ {
+		if math.Abs(cur[k]-old[k]) > old[k]*thresholdStabilityTolerance {
+			return false
+		}
+	}
+	return true
+}
+
 // gets the storeIDs, including old region and new region
 func (f *hotPeerCache) getAllStoreIDs(region *core.RegionInfo) []uint64 {
 	storeIDs := make(map[uint64]struct{})
@@ -401,6 +779,7 @@ func (f *hotPeerCache) updateHotPeerStat(newItem, oldItem *HotPeerStat, bytes, k
 		newItem.rollingByteRate.Add(bytes, interval)
 		newItem.rollingKeyRate.Add(keys, interval)
 		if newItem.rollingKeyRate.isFull() {
+			newItem.history.add(newItem.rollingByteRate.Get())
 			newItem.clearLastAverage()
 		}
 		return newItem
@@ -408,6 +787,7 @@ func (f *hotPeerCache) updateHotPeerStat(newItem, oldItem *HotPeerStat, bytes, k
 
 	newItem.rollingByteRate = oldItem.rollingByteRate
 	newItem.rollingKeyRate = oldItem.rollingKeyRate
+	newItem.history = oldItem.history
 
 	if newItem.justTransferLeader {
 		// skip the first heartbeat flow statistic after transfer leader, because its statistics are calculated by the last leader in this store and are inaccurate
@@ -446,7 +826,62 @@ func (f *hotPeerCache) updateHotPeerStat(newItem, oldItem *HotPeerStat, bytes, k
 				}
 			}
 		}
+		newItem.history.add(newItem.rollingByteRate.Get())
 		newItem.clearLastAverage()
 	}
 	return newItem
 }
+
+// heartbeatDenoiser accumulates the flow of the heartbeats with short intervals
+// into a sample with a longer interval, instead of dropping them.
+type heartbeatDenoiser struct {
+	sync.Mutex
+	pendings map[uint64]*pendingFlow // regionID -> accumulated flow
+}
+
+type pendingFlow struct {
+	bytes    float64
+	keys     float64
+	interval uint64
+	// lastUpdate is the time of the last heartbeat accumulated.
+	lastUpdate time.Time
+}
+
+func newHeartbeatDenoiser() *heartbeatDenoiser {
+	return &heartbeatDenoiser{pendings: make(map[uint64]*pendingFlow)}
+}
+
+// accumulate adds the flow of a heartbeat to the region. It returns the accumulated
+// flow and true once the accumulated interval reaches the window size.
+func (d *heartbeatDenoiser) accumulate(regionID uint64, bytes, keys float64, interval, windowSize uint64) (float64, float64, uint64, bool) {
+	d.Lock()
+	defer d.Unlock()
+	pending, ok := d.pendings[regionID]
+	if !ok {
+		if interval >= windowSize {
+			return bytes, keys, interval, true
+		}
+		pending = &pendingFlow{}
+		d.pendings[regionID] = pending
+	}
+	pending.bytes += bytes
+	pending.keys += keys
+	pending.interval += interval
+	pending.lastUpdate = time.Now()
+	if pending.interval < windowSize {
+		return pending.bytes, pending.keys, pending.interval, false
+	}
+	delete(d.pendings, regionID)
+	return pending.bytes, pending.keys, pending.interval, true
+}
+
+// prune drops the pending flows which have not been accumulated for ttl.
+func (d *heartbeatDenoiser) prune(now time.Time, ttl time.Duration) {
+	d.Lock()
+	defer d.Unlock()
+	for regionID, pending := range d.pendings {
+		if now.Sub(pending.lastUpdate) > ttl {
+			delete(d.pendings, regionID)
+		}
+	}
+}
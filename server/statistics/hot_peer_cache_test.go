@@ -15,6 +15,7 @@ package statistics
 
 import (
 	"math/rand"
+	"sync"
 	"testing"
 	"time"
 
@@ -58,6 +59,110 @@ func (t *testHotPeerCache) TestStoreTimeUnsync(c *C) {
 	}
 }
 
+func (t *testHotPeerCache) TestExportImport(c *C) {
+	cache := NewHotStoresStats(WriteFlow)
+	peers := newPeers(3,
+		func(i int) uint64 { return uint64(10000 + i) },
+		func(i int) uint64 { return uint64(i) })
+	meta := &metapb.Region{
+		Id:          1000,
+		Peers:       peers,
+		StartKey:    []byte(""),
+		EndKey:      []byte(""),
+		RegionEpoch: &metapb.RegionEpoch{ConfVer: 6, Version: 6},
+	}
+	region := core.NewRegionInfo(meta, peers[0],
+		core.SetReportInterval(60),
+		core.SetWrittenBytes(60*100*1024))
+	checkAndUpdate(c, cache, region, 3)
+
+	data, err := cache.Export()
+	c.Assert(err, IsNil)
+	newCache := NewHotStoresStats(WriteFlow)
+	c.Assert(newCache.Import(data), IsNil)
+	for _, peer := range peers {
+		oldItem := cache.getOldHotPeerStat(region.GetID(), peer.GetStoreId())
+		newItem := newCache.getOldHotPeerStat(region.GetID(), peer.GetStoreId())
+		c.Assert(newItem, NotNil)
+		c.Assert(newItem.Kind, Equals, WriteFlow)
+		c.Assert(newItem.HotDegree, Equals, oldItem.HotDegree)
+		c.Assert(newItem.AntiCount, Equals, oldItem.AntiCount)
+		c.Assert(newItem.GetByteRate(), Equals, oldItem.GetByteRate())
+		c.Assert(newItem.GetKeyRate(), Equals, oldItem.GetKeyRate())
+		c.Assert(newItem.isLeader, Equals, oldItem.isLeader)
+		c.Assert(newItem.peers, DeepEquals, oldItem.peers)
+	}
+	c.Assert(newCache.IsRegionHot(region, 1), IsTrue)
+
+	// the next heartbeat hits the imported items.
+	region = core.NewRegionInfo(meta, peers[0],
+		core.SetReportInterval(10),
+		core.SetWrittenBytes(10*100*1024))
+	for _, item := range checkAndUpdate(c, newCache, region, 3) {
+		c.Assert(item.IsNew(), IsFalse)
+	}
+
+	// expired items are not imported.
+	for _, v := range cache.peersOfStore[1].GetAll() {
+		v.(*HotPeerStat).LastUpdateTime = time.Now().Add(-2 * topNTTL)
+	}
+	data, err = cache.Export()
+	c.Assert(err, IsNil)
+	newCache = NewHotStoresStats(WriteFlow)
+	c.Assert(newCache.Import(data), IsNil)
+	c.Assert(newCache.getOldHotPeerStat(region.GetID(), 1), IsNil)
+	c.Assert(newCache.getOldHotPeerStat(region.GetID(), 2), NotNil)
+
+	c.Assert(newCache.Import([]byte("invalid")), NotNil)
+
+	// only the hottest peers are exported if there are too many.
+	cache.getOldHotPeerStat(region.GetID(), 3).HotDegree += 10
+	data, err = cache.export(1)
+	c.Assert(err, IsNil)
+	newCache = NewHotStoresStats(WriteFlow)
+	c.Assert(newCache.Import(data), IsNil)
+	c.Assert(newCache.getOldHotPeerStat(region.GetID(), 2), IsNil)
+	c.Assert(newCache.getOldHotPeerStat(region.GetID(), 3), NotNil)
+}
+
+func (t *testHotPeerCache) TestTransferHeat(c *C) {
+	cache := NewHotStoresStats(WriteFlow)
+	peers := newPeers(3,
+		func(i int) uint64 { return uint64(10000 + i) },
+		func(i int) uint64 { return uint64(i) })
+	meta := &metapb.Region{
+		Id:          1000,
+		Peers:       peers,
+		StartKey:    []byte(""),
+		EndKey:      []byte(""),
+		RegionEpoch: &metapb.RegionEpoch{ConfVer: 6, Version: 6},
+	}
+	region := core.NewRegionInfo(meta, peers[0],
+		core.SetReportInterval(60),
+		core.SetWrittenBytes(60*100*1024))
+	checkAndUpdate(c, cache, region, 3)
+	oldItem := cache.getOldHotPeerStat(region.GetID(), 1)
+
+	// the peer on store 2 is as hot as the old leader.
+	item := cache.getOldHotPeerStat(region.GetID(), 2)
+	cache.TransferHeat(1, 2, region.GetID())
+	c.Assert(cache.getOldHotPeerStat(region.GetID(), 2), Equals, item)
+
+	// the store without hot peer gets the heat.
+	cache.TransferHeat(1, 4, region.GetID())
+	item = cache.getOldHotPeerStat(region.GetID(), 4)
+	c.Assert(item, NotNil)
+	c.Assert(item.isLeader, IsTrue)
+	c.Assert(item.HotDegree, Equals, oldItem.HotDegree)
+	c.Assert(item.GetByteRate(), Equals, oldItem.GetByteRate())
+	c.Assert(item.GetKeyRate(), Equals, oldItem.GetKeyRate())
+	c.Assert(cache.storesOfRegion[region.GetID()], HasLen, 4)
+
+	// nothing to transfer if the old store has no hot peer.
+	cache.TransferHeat(5, 6, region.GetID())
+	c.Assert(cache.getOldHotPeerStat(region.GetID(), 6), IsNil)
+}
+
 type operator int
 
 const (
@@ -215,6 +320,50 @@ func newPeers(n int, pid genID, sid genID) []*metapb.Peer {
 	return peers
 }
 
+func (t *testHotPeerCache) TestDenoiseWindow(c *C) {
+	cache := NewHotStoresStats(WriteFlow)
+	cache.SetDenoiseWindowSize(10)
+	peers := newPeers(3,
+		func(i int) uint64 { return uint64(10000 + i) },
+		func(i int) uint64 { return uint64(i) })
+	meta := &metapb.Region{
+		Id:          1000,
+		Peers:       peers,
+		StartKey:    []byte(""),
+		EndKey:      []byte(""),
+		RegionEpoch: &metapb.RegionEpoch{ConfVer: 6, Version: 6},
+	}
+	newRegion := func(interval uint64) *core.RegionInfo {
+		return core.NewRegionInfo(meta, peers[0],
+			core.SetReportInterval(interval),
+			core.SetWrittenBytes(interval*100*1024))
+	}
+
+	// the short heartbeats are accumulated until the window is filled.
+	checkAndUpdate(c, cache, newRegion(4), 0)
+	checkAndUpdate(c, cache, newRegion(4), 0)
+	items := checkAndUpdate(c, cache, newRegion(4), 3)
+	for _, item := range items {
+		c.Assert(item.interval, Equals, uint64(12))
+		c.Assert(item.ByteRate, Equals, float64(100*1024))
+	}
+	// the window is cleared after a sample is produced.
+	checkAndUpdate(c, cache, newRegion(4), 0)
+	checkAndUpdate(c, cache, newRegion(6), 3)
+
+	// the pending flows of the regions which stop reporting are pruned.
+	checkAndUpdate(c, cache, newRegion(4), 0)
+	c.Assert(cache.denoiser.pendings, HasLen, 1)
+	cache.ExpireStale(time.Now())
+	c.Assert(cache.denoiser.pendings, HasLen, 1)
+	cache.ExpireStale(time.Now().Add(staleHotPeerTTL + time.Second))
+	c.Assert(cache.denoiser.pendings, HasLen, 0)
+
+	// the heartbeats are never accumulated without denoising.
+	cache.SetDenoiseWindowSize(0)
+	checkAndUpdate(c, cache, newRegion(1), 3)
+}
+
 func (t *testHotPeerCache) TestUpdateHotPeerStat(c *C) {
 	cache := NewHotStoresStats(ReadFlow)
 
@@ -268,6 +417,49 @@ func (t *testHotPeerCache) TestUpdateHotPeerStat(c *C) {
 	c.Check(newItem.needDelete, Equals, true)
 }
 
+func (t *testHotPeerCache) TestTrend(c *C) {
+	stat := &HotPeerStat{}
+	c.Assert(stat.Trend(), Equals, 0.0)
+	// only the latest samples are kept.
+	for i := 1; i <= hotPeerHistorySize+5; i++ {
+		stat.history.add(float64(i * 100))
+	}
+	c.Assert(stat.Trend(), Equals, 100.0)
+	for i := 0; i < hotPeerHistorySize; i++ {
+		stat.history.add(float64(1000 - i*50))
+	}
+	c.Assert(stat.Trend(), Equals, -50.0)
+	c.Assert(stat.Clone().ByteRateTrend, Equals, -50.0)
+
+	// a sample is recorded for each full rolling window.
+	cache := NewHotStoresStats(ReadFlow)
+	newItem := &HotPeerStat{thresholds: [2]float64{0.0, 0.0}}
+	newItem = cache.updateHotPeerStat(newItem, nil, 60, 60, 60*time.Second)
+	c.Assert(newItem.history.count, Equals, 1)
+	newItem = cache.updateHotPeerStat(&HotPeerStat{thresholds: newItem.thresholds}, newItem, 60, 60, 30*time.Second)
+	c.Assert(newItem.history.count, Equals, 1)
+	newItem = cache.updateHotPeerStat(&HotPeerStat{thresholds: newItem.thresholds}, newItem, 60, 60, 30*time.Second)
+	c.Assert(newItem.history.count, Equals, 2)
+}
+
+func (t *testHotPeerCache) TestGetAntiCountDistribution(c *C) {
+	cache := NewHotStoresStats(WriteFlow)
+	c.Assert(cache.GetAntiCountDistribution(), Equals, [hotRegionAntiCount + 1]int{})
+
+	cache.Update(&HotPeerStat{StoreID: 1, RegionID: 1, AntiCount: 0})
+	cache.Update(&HotPeerStat{StoreID: 1, RegionID: 2, AntiCount: 1})
+	cache.Update(&HotPeerStat{StoreID: 2, RegionID: 1, AntiCount: hotRegionAntiCount})
+	cache.Update(&HotPeerStat{StoreID: 2, RegionID: 3, AntiCount: hotRegionAntiCount})
+	dist := cache.GetAntiCountDistribution()
+	c.Assert(dist[0], Equals, 1)
+	c.Assert(dist[1], Equals, 1)
+	c.Assert(dist[hotRegionAntiCount], Equals, 2)
+
+	// the deleted peer is not counted.
+	cache.Update(&HotPeerStat{StoreID: 2, RegionID: 3, needDelete: true})
+	c.Assert(cache.GetAntiCountDistribution()[hotRegionAntiCount], Equals, 1)
+}
+
 func (t *testHotPeerCache) TestThresholdWithUpdateHotPeerStat(c *C) {
 	byteRate := minHotThresholds[ReadFlow][byteDim] * 2
 	expectThreshold := byteRate * HotThresholdRatio
@@ -311,6 +503,199 @@ func (t *testHotPeerCache) testMetrics(c *C, interval, byteRate, expectThreshold
 	}
 }
 
+func (t *testHotPeerCache) TestStabilizeThresholds(c *C) {
+	cache := NewHotStoresStats(ReadFlow)
+	storeID := uint64(1)
+	nextInterval := func(thresholds [dimLen]float64) [dimLen]float64 {
+		cache.thresholdStates[storeID].lastSampleTime = time.Now().Add(-thresholdSampleInterval)
+		return cache.stabilizeThresholds(storeID, thresholds)
+	}
+	initial := [dimLen]float64{1000, 100}
+	c.Assert(cache.stabilizeThresholds(storeID, initial), Equals, initial)
+
+	// The threshold is kept when the TopN minimum churns.
+	c.Assert(nextInterval([dimLen]float64{2000, 100}), Equals, initial)
+	c.Assert(nextInterval([dimLen]float64{500, 100}), Equals, initial)
+	// It is also kept within the same interval.
+	c.Assert(cache.stabilizeThresholds(storeID, [dimLen]float64{3000, 100}), Equals, initial)
+
+	// The threshold follows once it is stable for the window.
+	stable := [dimLen]float64{2000, 200}
+	c.Assert(nextInterval(stable), Equals, initial)
+	for i := 1; i < defaultThresholdStabilityWindow; i++ {
+		c.Assert(nextInterval([dimLen]float64{2050, 195}), Equals, initial)
+	}
+	c.Assert(nextInterval(stable), Equals, stable)
+
+	// No stabilization if the window is 0.
+	cache.SetThresholdStabilityWindow(0)
+	c.Assert(cache.stabilizeThresholds(storeID, initial), Equals, initial)
+}
+
+func (t *testHotPeerCache) TestConcurrentCheckRegionFlow(c *C) {
+	cache := NewHotStoresStats(WriteFlow)
+	byteRate := minHotThresholds[WriteFlow][byteDim] * 2
+	for storeID := uint64(1); storeID <= 3; storeID++ {
+		for i := uint64(1); i <= TopNN; i++ {
+			cache.Update(&HotPeerStat{StoreID: storeID, RegionID: i, ByteRate: byteRate, KeyRate: 1000})
+		}
+	}
+	// The heartbeats check the region flow concurrently under the read lock of
+	// the cluster, it should be run with -race.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				cache.CheckRegionFlow(buildRegion(nil, nil, WriteFlow))
+				cache.SetThresholdStabilityWindow(i % 2)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (t *testHotPeerCache) TestThresholdRecalibrationInterval(c *C) {
+	cache := NewHotStoresStats(ReadFlow)
+	cache.SetThresholdStabilityWindow(0)
+	storeID := uint64(1)
+	byteRate := minHotThresholds[ReadFlow][byteDim] * 2
+	put := func(regionID uint64, byteRate float64) {
+		cache.Update(&HotPeerStat{StoreID: storeID, RegionID: regionID, ByteRate: byteRate, KeyRate: 1000})
+	}
+	for i := uint64(1); i <= TopNN; i++ {
+		put(i, byteRate)
+	}
+	thresholds := cache.calcHotThresholds(storeID)
+	c.Assert(thresholds[byteDim], Equals, byteRate*HotThresholdRatio)
+
+	// The thresholds are calculated every time by default.
+	for i := uint64(1); i <= TopNN; i++ {
+		put(i, byteRate*2)
+	}
+	c.Assert(cache.calcHotThresholds(storeID)[byteDim], Equals, byteRate*2*HotThresholdRatio)
+
+	// The thresholds are cached within the interval.
+	cache.SetThresholdRecalibrationInterval(time.Minute)
+	thresholds = cache.calcHotThresholds(storeID)
+	for i := uint64(1); i <= TopNN; i++ {
+		put(i, byteRate*3)
+	}
+	c.Assert(cache.calcHotThresholds(storeID), Equals, thresholds)
+	// The cache is invalidated if the size of the TopN list changes a lot.
+	for i := uint64(TopNN + 1); i <= TopNN+4; i++ {
+		put(i, byteRate*3)
+	}
+	c.Assert(cache.calcHotThresholds(storeID)[byteDim], Equals, byteRate*3*HotThresholdRatio)
+	// The cache is invalidated if the interval has elapsed.
+	for i := uint64(1); i <= TopNN+4; i++ {
+		put(i, byteRate*4)
+	}
+	cache.cachedThresholds[storeID].updateTime = time.Now().Add(-time.Minute)
+	c.Assert(cache.calcHotThresholds(storeID)[byteDim], Equals, byteRate*4*HotThresholdRatio)
+}
+
+func (t *testHotPeerCache) TestConcurrentCalcHotThresholds(c *C) {
+	cache := NewHotStoresStats(WriteFlow)
+	cache.SetThresholdStabilityWindow(0)
+	byteRate := minHotThresholds[WriteFlow][byteDim] * 2
+	for storeID := uint64(1); storeID <= 3; storeID++ {
+		for i := uint64(1); i <= TopNN; i++ {
+			cache.Update(&HotPeerStat{StoreID: storeID, RegionID: i, ByteRate: byteRate, KeyRate: 1000})
+		}
+	}
+	// The heartbeats and the collection of the metrics calculate the thresholds
+	// concurrently under the read lock of the cluster, it should be run with -race.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if i%2 == 0 {
+					cache.CheckRegionFlow(buildRegion(nil, nil, WriteFlow))
+				} else {
+					cache.CollectMetrics("write")
+				}
+				cache.SetThresholdRecalibrationInterval(time.Duration(j%2) * time.Minute)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (t *testHotPeerCache) TestExpireStale(c *C) {
+	cache := NewHotStoresStats(WriteFlow)
+	cache.Update(&HotPeerStat{StoreID: 1, RegionID: 1, ByteRate: 1000, KeyRate: 1000})
+	cache.Update(&HotPeerStat{StoreID: 2, RegionID: 1, ByteRate: 1000, KeyRate: 1000})
+	now := time.Now()
+	cache.ExpireStale(now)
+	c.Assert(cache.getOldHotPeerStat(1, 1), NotNil)
+	c.Assert(cache.getOldHotPeerStat(1, 2), NotNil)
+
+	// the peer on store 1 is not updated since the region is migrated.
+	cache.getOldHotPeerStat(1, 1).lastSeen = now.Add(-staleHotPeerTTL - time.Second)
+	cache.ExpireStale(now)
+	c.Assert(cache.getOldHotPeerStat(1, 1), IsNil)
+	c.Assert(cache.getOldHotPeerStat(1, 2), NotNil)
+	c.Assert(cache.storesOfRegion[1], DeepEquals, map[uint64]struct{}{2: {}})
+
+	// the stale peers are also removed when the cache is updated.
+	cache.getOldHotPeerStat(1, 2).lastSeen = now.Add(-staleHotPeerTTL - time.Second)
+	cache.lastStaleCheckTime = time.Time{}
+	cache.Update(&HotPeerStat{StoreID: 3, RegionID: 2, ByteRate: 1000, KeyRate: 1000})
+	c.Assert(cache.getOldHotPeerStat(1, 2), IsNil)
+	c.Assert(cache.getOldHotPeerStat(2, 3), NotNil)
+}
+
+func (t *testHotPeerCache) TestSamplingRate(c *C) {
+	cache := NewHotStoresStats(WriteFlow)
+	peers := newPeers(3,
+		func(i int) uint64 { return uint64(10000 + i) },
+		func(i int) uint64 { return uint64(i) })
+	region := core.NewRegionInfo(&metapb.Region{Id: 1000, Peers: peers}, peers[0])
+	thresholds := minHotThresholds[WriteFlow]
+	coldByte, hotByte := thresholds[byteDim]/2, thresholds[byteDim]
+	coldKey, hotKey := thresholds[keyDim]/2, thresholds[keyDim]
+	// all heartbeats are checked by default.
+	c.Assert(cache.skipColdRegion(region, coldByte, coldKey), IsFalse)
+
+	cache.SetSamplingRate(0)
+	c.Assert(cache.skipColdRegion(region, coldByte, coldKey), IsTrue)
+	c.Assert(cache.CheckRegionFlow(region), HasLen, 0)
+	// the heartbeats of the hot regions in either dimension are never skipped.
+	c.Assert(cache.skipColdRegion(region, hotByte, coldKey), IsFalse)
+	c.Assert(cache.skipColdRegion(region, coldByte, hotKey), IsFalse)
+	cache.Update(&HotPeerStat{StoreID: 1, RegionID: 1000, ByteRate: hotByte, KeyRate: hotKey})
+	c.Assert(cache.skipColdRegion(region, coldByte, coldKey), IsFalse)
+}
+
+func (t *testHotPeerCache) TestHotPeerCountTimeSeries(c *C) {
+	history := newHotPeerCountHistory()
+	now := time.Now()
+	history.observe(1, 1, now.Add(-hotPeerCountRetention-time.Hour))
+	history.observe(1, 2, now.Add(-2*time.Hour))
+	// sampled at most once per interval.
+	history.observe(1, 3, now.Add(-2*time.Hour+hotPeerCountSampleInterval/2))
+	// the expired samples are dropped.
+	history.observe(1, 4, now)
+	c.Assert(history.points[1], HasLen, 2)
+	c.Assert(history.get(1, time.Hour, now), DeepEquals, []TimeSeriesPoint{{Time: now, Value: 4}})
+	c.Assert(history.get(1, 3*time.Hour, now), HasLen, 2)
+	c.Assert(history.get(2, time.Hour, now), HasLen, 0)
+
+	// sampled when collecting the metrics.
+	cache := NewHotCache()
+	cache.Update(&HotPeerStat{StoreID: 1, RegionID: 1, Kind: WriteFlow})
+	cache.Update(&HotPeerStat{StoreID: 1, RegionID: 2, Kind: WriteFlow})
+	cache.CollectMetrics()
+	points := cache.GetStoreHotPeerCountTimeSeries(1, WriteFlow, time.Minute)
+	c.Assert(points, HasLen, 1)
+	c.Assert(points[0].Value, Equals, 2.0)
+	c.Assert(cache.GetStoreHotPeerCountTimeSeries(1, ReadFlow, time.Minute), HasLen, 0)
+}
+
 func BenchmarkCheckRegionFlow(b *testing.B) {
 	cache := NewHotStoresStats(ReadFlow)
 	region := core.NewRegionInfo(&metapb.Region{
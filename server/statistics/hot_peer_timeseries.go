@@ -0,0 +1,72 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// The number of hot peers of each store is sampled at most once per
+	// hotPeerCountSampleInterval, and the samples are kept for a day.
+	hotPeerCountSampleInterval = time.Minute
+	hotPeerCountRetention      = 24 * time.Hour
+)
+
+// TimeSeriesPoint is a sample of a time series.
+type TimeSeriesPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// hotPeerCountHistory records the number of hot peers of each store over time.
+type hotPeerCountHistory struct {
+	sync.RWMutex
+	points map[uint64][]TimeSeriesPoint // storeID -> samples, the earlier the former
+}
+
+func newHotPeerCountHistory() *hotPeerCountHistory {
+	return &hotPeerCountHistory{
+		points: make(map[uint64][]TimeSeriesPoint),
+	}
+}
+
+func (h *hotPeerCountHistory) observe(storeID uint64, count int, now time.Time) {
+	h.Lock()
+	defer h.Unlock()
+	points := h.points[storeID]
+	if n := len(points); n > 0 && now.Sub(points[n-1].Time) < hotPeerCountSampleInterval {
+		return
+	}
+	points = append(points, TimeSeriesPoint{Time: now, Value: float64(count)})
+	expired := 0
+	for expired < len(points) && now.Sub(points[expired].Time) > hotPeerCountRetention {
+		expired++
+	}
+	h.points[storeID] = points[expired:]
+}
+
+// get returns the samples of the store in the window before now.
+func (h *hotPeerCountHistory) get(storeID uint64, window time.Duration, now time.Time) []TimeSeriesPoint {
+	h.RLock()
+	defer h.RUnlock()
+	var res []TimeSeriesPoint
+	for _, p := range h.points[storeID] {
+		if now.Sub(p.Time) <= window {
+			res = append(res, p)
+		}
+	}
+	return res
+}
@@ -13,6 +13,8 @@
 
 package statistics
 
+import "sort"
+
 // HotPeersStat records all hot regions statistics
 type HotPeersStat struct {
 	TotalBytesRate float64       `json:"total_flow_bytes"`
@@ -20,3 +22,41 @@ type HotPeersStat struct {
 	Count          int           `json:"regions_count"`
 	Stats          []HotPeerStat `json:"statistics"`
 }
+
+// HotStoreSummary records the summary of the hot peers on a store.
+type HotStoreSummary struct {
+	TotalByteRate float64 `json:"total_byte_rate"`
+	TotalKeyRate  float64 `json:"total_key_rate"`
+	HotPeerCount  int     `json:"hot_peer_count"`
+	P50ByteRate   float64 `json:"p50_byte_rate"`
+	P95ByteRate   float64 `json:"p95_byte_rate"`
+	P99ByteRate   float64 `json:"p99_byte_rate"`
+}
+
+// SummaryHotPeers summarizes the hot peers on a store.
+func SummaryHotPeers(peers []*HotPeerStat) *HotStoreSummary {
+	summary := &HotStoreSummary{HotPeerCount: len(peers)}
+	byteRates := make([]float64, 0, len(peers))
+	for _, peer := range peers {
+		summary.TotalByteRate += peer.GetByteRate()
+		summary.TotalKeyRate += peer.GetKeyRate()
+		byteRates = append(byteRates, peer.GetByteRate())
+	}
+	sort.Float64s(byteRates)
+	summary.P50ByteRate = percentile(byteRates, 50)
+	summary.P95ByteRate = percentile(byteRates, 95)
+	summary.P99ByteRate = percentile(byteRates, 99)
+	return summary
+}
+
+// percentile returns the nearest-rank percentile of the sorted values.
+func percentile(sorted []float64, p int) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (len(sorted)*p + 99) / 100 // ceil(len * p / 100)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
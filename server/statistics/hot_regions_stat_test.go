@@ -0,0 +1,47 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testHotRegionsStatSuite{})
+
+type testHotRegionsStatSuite struct{}
+
+func (s *testHotRegionsStatSuite) TestSummaryHotPeers(c *C) {
+	summary := SummaryHotPeers(nil)
+	c.Assert(summary, DeepEquals, &HotStoreSummary{})
+
+	var peers []*HotPeerStat
+	for i := 1; i <= 100; i++ {
+		peers = append(peers, &HotPeerStat{
+			RegionID: uint64(i),
+			ByteRate: float64(101-i) * 1024,
+			KeyRate:  float64(i),
+		})
+	}
+	summary = SummaryHotPeers(peers)
+	c.Assert(summary.HotPeerCount, Equals, 100)
+	c.Assert(summary.TotalByteRate, Equals, float64(5050*1024))
+	c.Assert(summary.TotalKeyRate, Equals, float64(5050))
+	c.Assert(summary.P50ByteRate, Equals, float64(50*1024))
+	c.Assert(summary.P95ByteRate, Equals, float64(95*1024))
+	c.Assert(summary.P99ByteRate, Equals, float64(99*1024))
+
+	summary = SummaryHotPeers(peers[:1])
+	c.Assert(summary.P50ByteRate, Equals, float64(100*1024))
+	c.Assert(summary.P99ByteRate, Equals, float64(100*1024))
+}
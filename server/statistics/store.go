@@ -137,6 +137,8 @@ type RollingStoreStats struct {
 	sync.RWMutex
 	timeMedians map[StoreStatKind]*movingaverage.TimeMedian
 	movingAvgs  map[StoreStatKind]movingaverage.MovingAvg
+	// capacitySamples are the used size samples to forecast the capacity.
+	capacitySamples []capacitySample
 }
 
 const (
@@ -193,6 +195,8 @@ func (r *RollingStoreStats) Observe(stats *pdpb.StoreStats) {
 	r.movingAvgs[StoreCPUUsage].Add(collect(stats.GetCpuUsages()))
 	r.movingAvgs[StoreDiskReadRate].Add(collect(stats.GetReadIoRates()))
 	r.movingAvgs[StoreDiskWriteRate].Add(collect(stats.GetWriteIoRates()))
+
+	r.observeCapacity(stats)
 }
 
 // Set sets the statistics (for test).
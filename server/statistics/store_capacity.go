@@ -0,0 +1,139 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+)
+
+const (
+	// capacitySampleInterval is the min interval between two samples of the used size.
+	capacitySampleInterval = 10 * time.Minute
+	// maxCapacitySamples keeps the samples of the last 7 days.
+	maxCapacitySamples = 7 * 24 * int(time.Hour/capacitySampleInterval)
+	// minForecastSamples is the min number of samples to fit the growth rate.
+	minForecastSamples = 3
+)
+
+type capacitySample struct {
+	at        time.Time
+	usedSize  uint64
+	available uint64
+}
+
+// CapacityForecast is the projection of when a store will be full according
+// to the growth rate of its used size.
+type CapacityForecast struct {
+	// EstimatedFullAt is nil if the used size is not growing.
+	EstimatedFullAt  *time.Time `json:"estimated_full_at,omitempty"`
+	DailyGrowthBytes int64      `json:"daily_growth_bytes"`
+	// ConfidenceInterval is the half width of the 95% confidence interval of
+	// the daily growth in bytes.
+	ConfidenceInterval float64 `json:"confidence_interval"`
+}
+
+// StoreCapacityForecast projects when the store will be full with a linear
+// regression over the used size samples within the window. It returns nil if
+// there are not enough samples.
+func (s *StoresStats) StoreCapacityForecast(storeID uint64, window time.Duration) *CapacityForecast {
+	stats := s.GetRollingStoreStats(storeID)
+	if stats == nil {
+		return nil
+	}
+	return stats.forecastCapacity(window)
+}
+
+// observeCapacity samples the used size of the store. It should be called with the lock held.
+func (r *RollingStoreStats) observeCapacity(stats *pdpb.StoreStats) {
+	at := time.Now()
+	if end := stats.GetInterval().GetEndTimestamp(); end != 0 {
+		at = time.Unix(int64(end), 0)
+	}
+	if n := len(r.capacitySamples); n > 0 && at.Sub(r.capacitySamples[n-1].at) < capacitySampleInterval {
+		return
+	}
+	r.capacitySamples = append(r.capacitySamples, capacitySample{
+		at:        at,
+		usedSize:  stats.GetUsedSize(),
+		available: stats.GetAvailable(),
+	})
+	if n := len(r.capacitySamples); n > maxCapacitySamples {
+		r.capacitySamples = r.capacitySamples[n-maxCapacitySamples:]
+	}
+}
+
+func (r *RollingStoreStats) forecastCapacity(window time.Duration) *CapacityForecast {
+	r.RLock()
+	defer r.RUnlock()
+	n := len(r.capacitySamples)
+	if n == 0 {
+		return nil
+	}
+	last := r.capacitySamples[n-1]
+	var samples []capacitySample
+	for _, sample := range r.capacitySamples {
+		if last.at.Sub(sample.at) <= window {
+			samples = append(samples, sample)
+		}
+	}
+	return forecastCapacity(samples)
+}
+
+func forecastCapacity(samples []capacitySample) *CapacityForecast {
+	if len(samples) < minForecastSamples {
+		return nil
+	}
+	// x is the number of days since the first sample, y is the used size.
+	n := float64(len(samples))
+	origin := samples[0].at
+	xs := make([]float64, len(samples))
+	var meanX, meanY float64
+	for i, sample := range samples {
+		xs[i] = sample.at.Sub(origin).Hours() / 24
+		meanX += xs[i] / n
+		meanY += float64(sample.usedSize) / n
+	}
+	var sxx, sxy float64
+	for i, sample := range samples {
+		dx := xs[i] - meanX
+		sxx += dx * dx
+		sxy += dx * (float64(sample.usedSize) - meanY)
+	}
+	if sxx == 0 {
+		return nil
+	}
+	slope := sxy / sxx
+	intercept := meanY - slope*meanX
+	var sse float64
+	for i, sample := range samples {
+		residual := float64(sample.usedSize) - (intercept + slope*xs[i])
+		sse += residual * residual
+	}
+
+	forecast := &CapacityForecast{
+		DailyGrowthBytes:   int64(math.Round(slope)),
+		ConfidenceInterval: 1.96 * math.Sqrt(sse/(n-2)/sxx),
+	}
+	if slope > 0 {
+		last := samples[len(samples)-1]
+		if d := float64(last.available) / slope * float64(24*time.Hour); d < math.MaxInt64 {
+			fullAt := last.at.Add(time.Duration(d))
+			forecast.EstimatedFullAt = &fullAt
+		}
+	}
+	return forecast
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+)
+
+var _ = Suite(&testStoreCapacitySuite{})
+
+type testStoreCapacitySuite struct{}
+
+func (t *testStoreCapacitySuite) TestStoreCapacityForecast(c *C) {
+	stats := NewStoresStats()
+	c.Assert(stats.StoreCapacityForecast(1, 24*time.Hour), IsNil)
+
+	const gb = 1 << 30
+	start := time.Now().Add(-48 * time.Hour).Truncate(time.Hour)
+	observe := func(at time.Time, used uint64) {
+		stats.Observe(1, &pdpb.StoreStats{
+			StoreId:   1,
+			UsedSize:  used,
+			Available: 100*gb - used,
+			Interval:  &pdpb.TimeInterval{StartTimestamp: uint64(at.Unix()) - 10, EndTimestamp: uint64(at.Unix())},
+		})
+	}
+	// the used size grows 1GB per hour.
+	for i := 0; i <= 48; i++ {
+		observe(start.Add(time.Duration(i)*time.Hour), uint64(i)*gb)
+		// the samples within the sample interval are ignored.
+		observe(start.Add(time.Duration(i)*time.Hour+time.Minute), uint64(i)*gb+gb/2)
+	}
+	forecast := stats.StoreCapacityForecast(1, 24*time.Hour)
+	c.Assert(forecast, NotNil)
+	c.Assert(forecast.DailyGrowthBytes, Equals, int64(24*gb))
+	c.Assert(forecast.ConfidenceInterval < 1<<20, IsTrue)
+	c.Assert(forecast.EstimatedFullAt, NotNil)
+	// 52GB is available after 48 hours.
+	c.Assert(forecast.EstimatedFullAt.Sub(start.Add(100*time.Hour)) < time.Second, IsTrue)
+	c.Assert(start.Add(100*time.Hour).Sub(*forecast.EstimatedFullAt) < time.Second, IsTrue)
+
+	// the used size stops growing.
+	stats = NewStoresStats()
+	for i := 0; i <= 10; i++ {
+		observe(start.Add(time.Duration(i)*time.Hour), 10*gb)
+	}
+	forecast = stats.StoreCapacityForecast(1, 24*time.Hour)
+	c.Assert(forecast, NotNil)
+	c.Assert(forecast.DailyGrowthBytes, Equals, int64(0))
+	c.Assert(forecast.EstimatedFullAt, IsNil)
+}
@@ -89,6 +89,8 @@ func NewTestSingleConfig(c *check.C) *config.Config {
 	})
 
 	c.Assert(cfg.Adjust(nil, false), check.IsNil)
+	// Do not wait for the regions to grow in tests.
+	cfg.Schedule.MinRegionsForScheduling = 0
 
 	return cfg
 }
@@ -1066,6 +1066,38 @@ func (s *testClientSuite) TestScatterRegion(c *C) {
 	c.Succeed()
 }
 
+func (s *testClientSuite) TestScatterRegionsAndWait(c *C) {
+	regionID := regionIDAllocator.alloc()
+	region := &metapb.Region{
+		Id: regionID,
+		RegionEpoch: &metapb.RegionEpoch{
+			ConfVer: 1,
+			Version: 1,
+		},
+		Peers:    peers,
+		StartKey: []byte("hhh"),
+		EndKey:   []byte("iii"),
+	}
+	req := &pdpb.RegionHeartbeatRequest{
+		Header: newHeader(s.srv),
+		Region: region,
+		Leader: peers[0],
+	}
+	err := s.regionHeartbeat.Send(req)
+	c.Assert(err, IsNil)
+	testutil.WaitUntil(c, func(c *C) bool {
+		r, err := s.client.GetRegionByID(context.Background(), regionID)
+		return err == nil && r != nil
+	})
+	// The scatter operator never finishes without the heartbeats of the region.
+	err = s.client.ScatterRegionsAndWait(context.Background(), []uint64{regionID}, time.Second)
+	c.Assert(err, NotNil)
+	resp, err := s.client.GetOperator(context.Background(), regionID)
+	c.Assert(err, IsNil)
+	c.Assert(string(resp.GetDesc()), Equals, "scatter-region")
+	c.Assert(resp.GetStatus(), Equals, pdpb.OperatorStatus_RUNNING)
+}
+
 type testConfigTTLSuite struct {
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -65,6 +65,8 @@ func (c *serverConfig) Generate(opts ...ConfigOption) (*config.Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Do not wait for the regions to grow in tests.
+	cfg.Schedule.MinRegionsForScheduling = 0
 	for _, opt := range opts {
 		opt(cfg, c.Name)
 	}